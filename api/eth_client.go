@@ -28,6 +28,8 @@ type EthClient interface {
 	NonceAt(context.Context, common.Address, *big.Int) (uint64, error)
 	AssetBalanceAt(context.Context, common.Address, ids.ID, *big.Int) (*big.Int, error)
 	SuggestGasPrice(context.Context) (*big.Int, error)
+	ChainID(context.Context) (*big.Int, error)
+	EstimateBaseFee(context.Context) (*big.Int, error)
 	AcceptedCodeAt(context.Context, common.Address) ([]byte, error)
 	AcceptedNonceAt(context.Context, common.Address) (uint64, error)
 	CodeAt(context.Context, common.Address, *big.Int) ([]byte, error)
@@ -37,6 +39,7 @@ type EthClient interface {
 	SuggestGasTipCap(context.Context) (*big.Int, error)
 	FilterLogs(context.Context, interfaces.FilterQuery) ([]types.Log, error)
 	SubscribeFilterLogs(context.Context, interfaces.FilterQuery, chan<- types.Log) (interfaces.Subscription, error)
+	SubscribeNewHead(context.Context, chan<- *types.Header) (interfaces.Subscription, error)
 }
 
 // ethClient websocket ethclient.Client with mutexed api calls and lazy conn (on first call)
@@ -170,6 +173,24 @@ func (c *ethClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
 	return c.client.SuggestGasPrice(ctx)
 }
 
+func (c *ethClient) ChainID(ctx context.Context) (*big.Int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c.client.ChainID(ctx)
+}
+
+func (c *ethClient) EstimateBaseFee(ctx context.Context) (*big.Int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c.client.EstimateBaseFee(ctx)
+}
+
 func (c *ethClient) AcceptedCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -250,3 +271,12 @@ func (c *ethClient) SubscribeFilterLogs(ctx context.Context, query interfaces.Fi
 	}
 	return c.client.SubscribeFilterLogs(ctx, query, ch)
 }
+
+func (c *ethClient) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (interfaces.Subscription, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.connect(); err != nil {
+		return nil, err
+	}
+	return c.client.SubscribeNewHead(ctx, ch)
+}
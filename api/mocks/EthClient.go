@@ -225,6 +225,29 @@ func (_m *EthClient) CallContract(_a0 context.Context, _a1 interfaces.CallMsg, _
 	return r0, r1
 }
 
+// ChainID provides a mock function with given fields: _a0
+func (_m *EthClient) ChainID(_a0 context.Context) (*big.Int, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func(context.Context) *big.Int); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Close provides a mock function with given fields:
 func (_m *EthClient) Close() {
 	_m.Called()
@@ -253,6 +276,29 @@ func (_m *EthClient) CodeAt(_a0 context.Context, _a1 common.Address, _a2 *big.In
 	return r0, r1
 }
 
+// EstimateBaseFee provides a mock function with given fields: _a0
+func (_m *EthClient) EstimateBaseFee(_a0 context.Context) (*big.Int, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *big.Int
+	if rf, ok := ret.Get(0).(func(context.Context) *big.Int); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EstimateGas provides a mock function with given fields: _a0, _a1
 func (_m *EthClient) EstimateGas(_a0 context.Context, _a1 interfaces.CallMsg) (uint64, error) {
 	ret := _m.Called(_a0, _a1)
@@ -378,6 +424,29 @@ func (_m *EthClient) SubscribeFilterLogs(_a0 context.Context, _a1 interfaces.Fil
 	return r0, r1
 }
 
+// SubscribeNewHead provides a mock function with given fields: _a0, _a1
+func (_m *EthClient) SubscribeNewHead(_a0 context.Context, _a1 chan<- *types.Header) (interfaces.Subscription, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 interfaces.Subscription
+	if rf, ok := ret.Get(0).(func(context.Context, chan<- *types.Header) interfaces.Subscription); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interfaces.Subscription)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, chan<- *types.Header) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SuggestGasPrice provides a mock function with given fields: _a0
 func (_m *EthClient) SuggestGasPrice(_a0 context.Context) (*big.Int, error) {
 	ret := _m.Called(_a0)
@@ -43,9 +43,37 @@ type Client interface {
 	SendOutboundMessage(ctx context.Context, nodeName string, peerID string, op uint32, msgBody []byte) (*rpcpb.SendOutboundMessageResponse, error)
 	Close() error
 	SaveSnapshot(ctx context.Context, snapshotName string) (*rpcpb.SaveSnapshotResponse, error)
-	LoadSnapshot(ctx context.Context, snapshotName string) (*rpcpb.LoadSnapshotResponse, error)
+	LoadSnapshot(ctx context.Context, snapshotName string, opts ...OpOption) (*rpcpb.LoadSnapshotResponse, error)
 	RemoveSnapshot(ctx context.Context, snapshotName string) (*rpcpb.RemoveSnapshotResponse, error)
 	GetSnapshotNames(ctx context.Context) ([]string, error)
+	CollectArtifacts(ctx context.Context, w io.Writer, opts ...OpOption) error
+	CollectProfiles(ctx context.Context, opts ...OpOption) (*rpcpb.CollectProfilesResponse, error)
+	SetLogLevel(ctx context.Context, loggerName string, logLevel string, displayLevel string, opts ...OpOption) (*rpcpb.SetLogLevelResponse, error)
+	Apply(ctx context.Context, spec []byte, opts ...OpOption) (*rpcpb.ApplyResponse, error)
+	EnableChaos(ctx context.Context, opts ...OpOption) (*rpcpb.EnableChaosResponse, error)
+	DisableChaos(ctx context.Context) (*rpcpb.DisableChaosResponse, error)
+	UpgradeNetwork(ctx context.Context, execPath string, opts ...OpOption) (*rpcpb.UpgradeNetworkResponse, error)
+	RegisterBinary(ctx context.Context, alias string, execPath string) (*rpcpb.RegisterBinaryResponse, error)
+	ListBinaries(ctx context.Context) ([]*rpcpb.BinaryInfo, error)
+	CreateSubnet(ctx context.Context, opts ...OpOption) (*rpcpb.CreateSubnetResponse, error)
+	AddSubnetValidators(ctx context.Context, subnetID string, validators []*rpcpb.SubnetValidatorSpec) (*rpcpb.AddSubnetValidatorsResponse, error)
+	CreateBlockchain(ctx context.Context, subnetID string, vmName string, genesisPath string, opts ...OpOption) (*rpcpb.CreateBlockchainResponse, error)
+	InstallCustomVMs(ctx context.Context, customVMs map[string]string, opts ...OpOption) (*rpcpb.InstallCustomVMsResponse, error)
+	Fund(ctx context.Context, address string, amount uint64) (*rpcpb.FundResponse, error)
+	CreateKey(ctx context.Context, name string) (*rpcpb.KeyInfo, error)
+	ImportKey(ctx context.Context, name string, privateKey string) (*rpcpb.KeyInfo, error)
+	ListKeys(ctx context.Context) ([]*rpcpb.KeyInfo, error)
+	AddValidator(ctx context.Context, nodeName string, opts ...OpOption) (*rpcpb.AddValidatorResponse, error)
+	AddDelegator(ctx context.Context, nodeName string, stake uint64, opts ...OpOption) (*rpcpb.AddDelegatorResponse, error)
+	CrossChainTransfer(ctx context.Context, sourceChain string, destinationChain string, amount uint64, address string) (*rpcpb.CrossChainTransferResponse, error)
+	CreateAsset(ctx context.Context, name string, symbol string, denomination uint32, variableCap bool, initialHolders []*rpcpb.AssetHolder) (*rpcpb.CreateAssetResponse, error)
+	MintAsset(ctx context.Context, assetID string, address string, amount uint64) (*rpcpb.MintAssetResponse, error)
+	TransferAsset(ctx context.Context, assetID string, keyName string, address string, amount uint64) (*rpcpb.TransferAssetResponse, error)
+	StartLoad(ctx context.Context, opts ...OpOption) (*rpcpb.StartLoadResponse, error)
+	StopLoad(ctx context.Context) (*rpcpb.StopLoadResponse, error)
+	LoadStatus(ctx context.Context) (*rpcpb.LoadStatusResponse, error)
+	CheckConsistency(ctx context.Context, depth uint32, maxLag uint64) (*rpcpb.CheckConsistencyResponse, error)
+	StreamBlocks(ctx context.Context, chain string, nodeName string) (<-chan *rpcpb.AcceptedBlock, error)
 }
 
 type client struct {
@@ -126,6 +154,21 @@ func (c *client) Start(ctx context.Context, execPath string, opts ...OpOption) (
 	if ret.customNodeConfigs != nil {
 		req.CustomNodeConfigs = ret.customNodeConfigs
 	}
+	if len(ret.nodeExecPaths) > 0 {
+		req.NodeExecPaths = ret.nodeExecPaths
+	}
+	if len(ret.customVMValidators) > 0 {
+		req.CustomVmValidators = ret.customVMValidators
+	}
+	if ret.chainConfigs != nil {
+		req.ChainConfigs = ret.chainConfigs
+	}
+	if len(ret.nodeChainConfigs) > 0 {
+		req.NodeChainConfigs = ret.nodeChainConfigs
+	}
+	if len(ret.customVMReadinessProbes) > 0 {
+		req.CustomVmReadinessProbes = ret.customVMReadinessProbes
+	}
 
 	zap.L().Info("start")
 	return c.controlc.Start(ctx, req)
@@ -219,6 +262,7 @@ func (c *client) AddNode(ctx context.Context, name string, execPath string, opts
 	if ret.pluginDir != "" {
 		req.StartRequest.PluginDir = &ret.pluginDir
 	}
+	req.Validator = ret.primaryValidatorSpec()
 
 	zap.L().Info("add node", zap.String("name", name))
 	return c.controlc.AddNode(ctx, req)
@@ -243,6 +287,7 @@ func (c *client) RestartNode(ctx context.Context, name string, opts ...OpOption)
 	if ret.rootDataDir != "" {
 		req.RootDataDir = &ret.rootDataDir
 	}
+	req.Force = ret.force
 
 	zap.L().Info("restart node", zap.String("name", name))
 	return c.controlc.RestartNode(ctx, req)
@@ -268,9 +313,20 @@ func (c *client) SaveSnapshot(ctx context.Context, snapshotName string) (*rpcpb.
 	return c.controlc.SaveSnapshot(ctx, &rpcpb.SaveSnapshotRequest{SnapshotName: snapshotName})
 }
 
-func (c *client) LoadSnapshot(ctx context.Context, snapshotName string) (*rpcpb.LoadSnapshotResponse, error) {
+func (c *client) LoadSnapshot(ctx context.Context, snapshotName string, opts ...OpOption) (*rpcpb.LoadSnapshotResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	req := &rpcpb.LoadSnapshotRequest{
+		SnapshotName: snapshotName,
+		Force:        ret.force,
+	}
+	if ret.execPath != "" {
+		req.ExecPath = &ret.execPath
+	}
+
 	zap.L().Info("load snapshot", zap.String("snapshot-name", snapshotName))
-	return c.controlc.LoadSnapshot(ctx, &rpcpb.LoadSnapshotRequest{SnapshotName: snapshotName})
+	return c.controlc.LoadSnapshot(ctx, req)
 }
 
 func (c *client) RemoveSnapshot(ctx context.Context, snapshotName string) (*rpcpb.RemoveSnapshotResponse, error) {
@@ -287,6 +343,459 @@ func (c *client) GetSnapshotNames(ctx context.Context) ([]string, error) {
 	return resp.SnapshotNames, nil
 }
 
+// CollectArtifacts writes the tar.gz artifacts bundle of the network to [w].
+func (c *client) CollectArtifacts(ctx context.Context, w io.Writer, opts ...OpOption) error {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	zap.L().Info("collect artifacts", zap.Strings("node-names", ret.nodeNames))
+	stream, err := c.controlc.CollectArtifacts(ctx, &rpcpb.CollectArtifactsRequest{
+		NodeNames:           ret.nodeNames,
+		ExcludeStakingCerts: ret.excludeStakingCerts,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(resp.Chunk); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *client) CollectProfiles(ctx context.Context, opts ...OpOption) (*rpcpb.CollectProfilesResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	req := &rpcpb.CollectProfilesRequest{
+		NodeNames:   ret.nodeNames,
+		Cpu:         ret.cpuProfile,
+		CpuDuration: int64(ret.cpuProfileDuration),
+		Memory:      ret.memoryProfile,
+		Lock:        ret.lockProfile,
+	}
+	if ret.profilesOutputDir != "" {
+		req.OutputDir = &ret.profilesOutputDir
+	}
+
+	zap.L().Info("collect profiles", zap.Strings("node-names", ret.nodeNames))
+	return c.controlc.CollectProfiles(ctx, req)
+}
+
+// SetLogLevel changes the levels of logger [loggerName], or of all loggers if empty.
+// Empty levels are left unchanged.
+func (c *client) SetLogLevel(ctx context.Context, loggerName string, logLevel string, displayLevel string, opts ...OpOption) (*rpcpb.SetLogLevelResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	zap.L().Info("set log level",
+		zap.Strings("node-names", ret.nodeNames),
+		zap.String("logger-name", loggerName),
+		zap.String("log-level", logLevel),
+		zap.String("display-level", displayLevel),
+	)
+	return c.controlc.SetLogLevel(ctx, &rpcpb.SetLogLevelRequest{
+		NodeNames:    ret.nodeNames,
+		LoggerName:   loggerName,
+		LogLevel:     logLevel,
+		DisplayLevel: displayLevel,
+	})
+}
+
+// Apply starts a network from the YAML or JSON [spec],
+// or converges the running network to it.
+func (c *client) Apply(ctx context.Context, spec []byte, opts ...OpOption) (*rpcpb.ApplyResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	zap.L().Info("apply", zap.Bool("dry-run", ret.dryRun))
+	return c.controlc.Apply(ctx, &rpcpb.ApplyRequest{
+		Spec:   string(spec),
+		DryRun: ret.dryRun,
+	})
+}
+
+// EnableChaos starts injecting faults into the non-beacon nodes of the network.
+func (c *client) EnableChaos(ctx context.Context, opts ...OpOption) (*rpcpb.EnableChaosResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	zap.L().Info("enable chaos",
+		zap.Int64("seed", ret.chaosSeed),
+		zap.Duration("duration", ret.chaosDuration),
+		zap.Duration("interval", ret.chaosInterval),
+		zap.Duration("down-duration", ret.chaosDownDuration),
+		zap.Uint32("min-healthy-validators", ret.minHealthyValidators),
+	)
+	return c.controlc.EnableChaos(ctx, &rpcpb.EnableChaosRequest{
+		Seed:                 ret.chaosSeed,
+		Duration:             int64(ret.chaosDuration),
+		Interval:             int64(ret.chaosInterval),
+		DownDuration:         int64(ret.chaosDownDuration),
+		FaultMix:             ret.chaosFaultMix,
+		MinHealthyValidators: ret.minHealthyValidators,
+	})
+}
+
+// DisableChaos stops injecting faults, and returns the actions taken.
+func (c *client) DisableChaos(ctx context.Context) (*rpcpb.DisableChaosResponse, error) {
+	zap.L().Info("disable chaos")
+	return c.controlc.DisableChaos(ctx, &rpcpb.DisableChaosRequest{})
+}
+
+// UpgradeNetwork restarts the nodes with [execPath] in batches,
+// waiting for each batch to be healthy and bootstrapped.
+func (c *client) UpgradeNetwork(ctx context.Context, execPath string, opts ...OpOption) (*rpcpb.UpgradeNetworkResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	req := &rpcpb.UpgradeNetworkRequest{
+		ExecPath:  execPath,
+		NodeNames: ret.nodeNames,
+		BatchSize: ret.batchSize,
+		Pause:     int64(ret.batchPause),
+	}
+	if ret.globalNodeConfig != "" {
+		req.GlobalNodeConfig = &ret.globalNodeConfig
+	}
+
+	zap.L().Info("upgrade network",
+		zap.String("exec-path", execPath),
+		zap.Strings("node-names", ret.nodeNames),
+		zap.Uint32("batch-size", ret.batchSize),
+		zap.Duration("pause", ret.batchPause),
+	)
+	return c.controlc.UpgradeNetwork(ctx, req)
+}
+
+// RegisterBinary registers [execPath] as [alias], to be used instead of exec paths.
+func (c *client) RegisterBinary(ctx context.Context, alias string, execPath string) (*rpcpb.RegisterBinaryResponse, error) {
+	zap.L().Info("register binary", zap.String("alias", alias), zap.String("exec-path", execPath))
+	return c.controlc.RegisterBinary(ctx, &rpcpb.RegisterBinaryRequest{
+		Alias:    alias,
+		ExecPath: execPath,
+	})
+}
+
+func (c *client) ListBinaries(ctx context.Context) ([]*rpcpb.BinaryInfo, error) {
+	zap.L().Info("list binaries")
+	resp, err := c.controlc.ListBinaries(ctx, &rpcpb.ListBinariesRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Binaries, nil
+}
+
+func (c *client) CreateSubnet(ctx context.Context, opts ...OpOption) (*rpcpb.CreateSubnetResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	zap.L().Info("create subnet",
+		zap.Strings("owner-addresses", ret.subnetOwners),
+		zap.Uint32("threshold", ret.subnetThreshold),
+	)
+	return c.controlc.CreateSubnet(ctx, &rpcpb.CreateSubnetRequest{
+		OwnerAddresses: ret.subnetOwners,
+		Threshold:      ret.subnetThreshold,
+	})
+}
+
+// AddSubnetValidators adds nodes as validators of subnet [subnetID],
+// restarting the nodes not whitelisting it yet.
+func (c *client) AddSubnetValidators(ctx context.Context, subnetID string, validators []*rpcpb.SubnetValidatorSpec) (*rpcpb.AddSubnetValidatorsResponse, error) {
+	zap.L().Info("add subnet validators", zap.String("subnet-id", subnetID), zap.Int("validators", len(validators)))
+	return c.controlc.AddSubnetValidators(ctx, &rpcpb.AddSubnetValidatorsRequest{
+		SubnetId:   subnetID,
+		Validators: validators,
+	})
+}
+
+func (c *client) CreateBlockchain(ctx context.Context, subnetID string, vmName string, genesisPath string, opts ...OpOption) (*rpcpb.CreateBlockchainResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	zap.L().Info("create blockchain",
+		zap.String("subnet-id", subnetID),
+		zap.String("vm-name", vmName),
+		zap.String("genesis-path", genesisPath),
+		zap.String("chain-name", ret.chainName),
+	)
+	return c.controlc.CreateBlockchain(ctx, &rpcpb.CreateBlockchainRequest{
+		SubnetId:    subnetID,
+		VmName:      vmName,
+		GenesisPath: genesisPath,
+		ChainName:   ret.chainName,
+	})
+}
+
+// InstallCustomVMs installs [customVMs] (VM name to genesis file path) on the running network,
+// and returns once they are ready. The plugin dir is set with WithPluginDir.
+func (c *client) InstallCustomVMs(ctx context.Context, customVMs map[string]string, opts ...OpOption) (*rpcpb.InstallCustomVMsResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	zap.L().Info("install custom VMs",
+		zap.String("plugin-dir", ret.pluginDir),
+		zap.Any("custom-vms", customVMs),
+	)
+	return c.controlc.InstallCustomVMs(ctx, &rpcpb.InstallCustomVMsRequest{
+		PluginDir:          ret.pluginDir,
+		CustomVms:          customVMs,
+		CustomVmValidators: ret.customVMValidators,
+		ChainConfigs:       ret.chainConfigs,
+		NodeChainConfigs:   ret.nodeChainConfigs,
+
+		CustomVmReadinessProbes: ret.customVMReadinessProbes,
+	})
+}
+
+// Fund sends [amount] nAXC from the pre-funded test key to [address], a SwapChain
+// or CoreChain address, or an AXChain hex address, and returns the tx ID once accepted.
+func (c *client) Fund(ctx context.Context, address string, amount uint64) (*rpcpb.FundResponse, error) {
+	zap.L().Info("fund",
+		zap.String("address", address),
+		zap.Uint64("amount", amount),
+	)
+	return c.controlc.Fund(ctx, &rpcpb.FundRequest{Address: address, Amount: amount})
+}
+
+// CreateKey generates a key named [name] in the server keychain,
+// and returns its addresses and balances on the running network.
+func (c *client) CreateKey(ctx context.Context, name string) (*rpcpb.KeyInfo, error) {
+	zap.L().Info("create key", zap.String("name", name))
+	resp, err := c.controlc.CreateKey(ctx, &rpcpb.CreateKeyRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Key, nil
+}
+
+// ImportKey imports [privateKey], "PrivateKey-" prefixed CB58 or hex encoded,
+// as [name] in the server keychain, and returns its addresses and balances on the running network.
+func (c *client) ImportKey(ctx context.Context, name string, privateKey string) (*rpcpb.KeyInfo, error) {
+	zap.L().Info("import key", zap.String("name", name))
+	resp, err := c.controlc.ImportKey(ctx, &rpcpb.ImportKeyRequest{Name: name, PrivateKey: privateKey})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Key, nil
+}
+
+// ListKeys returns the keys of the server keychain, starting with the pre-funded test key,
+// with their addresses and balances on the running network.
+func (c *client) ListKeys(ctx context.Context) ([]*rpcpb.KeyInfo, error) {
+	zap.L().Info("list keys")
+	resp, err := c.controlc.ListKeys(ctx, &rpcpb.ListKeysRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Keys, nil
+}
+
+// AddValidator adds node [nodeName] as a primary network validator, staking from the
+// pre-funded test key as set with WithValidator, and returns once it is a current validator,
+// or once it is pending if scheduled with WithStakingPeriod.
+func (c *client) AddValidator(ctx context.Context, nodeName string, opts ...OpOption) (*rpcpb.AddValidatorResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	zap.L().Info("add validator", zap.String("node-name", nodeName))
+	if ret.validator == nil {
+		// server defaults
+		ret.validator = &rpcpb.PrimaryValidatorSpec{}
+	}
+	return c.controlc.AddValidator(ctx, &rpcpb.AddValidatorRequest{
+		NodeName:  nodeName,
+		Validator: ret.primaryValidatorSpec(),
+	})
+}
+
+// AddDelegator delegates [stake] nAXC from the pre-funded test key to the primary network
+// validator [nodeName], 1 AXC if zero. Starts shortly and lasts as long as the validation,
+// unless scheduled with WithStakingPeriod.
+func (c *client) AddDelegator(ctx context.Context, nodeName string, stake uint64, opts ...OpOption) (*rpcpb.AddDelegatorResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	zap.L().Info("add delegator", zap.String("node-name", nodeName), zap.Uint64("stake", stake))
+	req := &rpcpb.AddDelegatorRequest{
+		NodeName:    nodeName,
+		StakeAmount: stake,
+	}
+	if !ret.stakingStart.IsZero() {
+		req.StartTime = ret.stakingStart.Unix()
+	}
+	if !ret.stakingEnd.IsZero() {
+		req.EndTime = ret.stakingEnd.Unix()
+	}
+	return c.controlc.AddDelegator(ctx, req)
+}
+
+// CrossChainTransfer moves [amount] nAXC of the pre-funded test key from chain [sourceChain]
+// to chain [destinationChain] ("X", "P" or "C"), crediting [address] on the destination chain,
+// or the test key if empty, and returns the export and import tx IDs once accepted.
+func (c *client) CrossChainTransfer(ctx context.Context, sourceChain string, destinationChain string, amount uint64, address string) (*rpcpb.CrossChainTransferResponse, error) {
+	zap.L().Info("cross chain transfer",
+		zap.String("source-chain", sourceChain),
+		zap.String("destination-chain", destinationChain),
+		zap.Uint64("amount", amount),
+		zap.String("address", address),
+	)
+	return c.controlc.CrossChainTransfer(ctx, &rpcpb.CrossChainTransferRequest{
+		SourceChain:      sourceChain,
+		DestinationChain: destinationChain,
+		Amount:           amount,
+		Address:          address,
+	})
+}
+
+// CreateAsset creates a SwapChain asset held by [initialHolders], and mintable
+// by the pre-funded test key if [variableCap], and returns its ID once accepted.
+func (c *client) CreateAsset(
+	ctx context.Context,
+	name string,
+	symbol string,
+	denomination uint32,
+	variableCap bool,
+	initialHolders []*rpcpb.AssetHolder,
+) (*rpcpb.CreateAssetResponse, error) {
+	zap.L().Info("create asset",
+		zap.String("name", name),
+		zap.String("symbol", symbol),
+		zap.Uint32("denomination", denomination),
+		zap.Bool("variable-cap", variableCap),
+		zap.Int("initial-holders", len(initialHolders)),
+	)
+	return c.controlc.CreateAsset(ctx, &rpcpb.CreateAssetRequest{
+		Name:           name,
+		Symbol:         symbol,
+		Denomination:   denomination,
+		VariableCap:    variableCap,
+		InitialHolders: initialHolders,
+	})
+}
+
+// MintAsset mints [amount] of the variable-cap asset [assetID] to the SwapChain address [address].
+func (c *client) MintAsset(ctx context.Context, assetID string, address string, amount uint64) (*rpcpb.MintAssetResponse, error) {
+	zap.L().Info("mint asset",
+		zap.String("asset-id", assetID),
+		zap.String("address", address),
+		zap.Uint64("amount", amount),
+	)
+	return c.controlc.MintAsset(ctx, &rpcpb.MintAssetRequest{
+		AssetId: assetID,
+		Holder:  &rpcpb.AssetHolder{Address: address, Amount: amount},
+	})
+}
+
+// TransferAsset sends [amount] of asset [assetID] from the key [keyName] of the server keychain,
+// or the pre-funded test key if empty, to the SwapChain address [address].
+func (c *client) TransferAsset(ctx context.Context, assetID string, keyName string, address string, amount uint64) (*rpcpb.TransferAssetResponse, error) {
+	zap.L().Info("transfer asset",
+		zap.String("asset-id", assetID),
+		zap.String("key-name", keyName),
+		zap.String("address", address),
+		zap.Uint64("amount", amount),
+	)
+	return c.controlc.TransferAsset(ctx, &rpcpb.TransferAssetRequest{
+		AssetId:   assetID,
+		KeyName:   keyName,
+		Recipient: &rpcpb.AssetHolder{Address: address, Amount: amount},
+	})
+}
+
+// StartLoad starts sending txs at a target rate to chains of the network,
+// from keys generated and funded by the server.
+func (c *client) StartLoad(ctx context.Context, opts ...OpOption) (*rpcpb.StartLoadResponse, error) {
+	ret := &Op{}
+	ret.applyOpts(opts)
+
+	zap.L().Info("start load",
+		zap.Strings("chains", ret.loadChains),
+		zap.Uint32("rate", ret.loadRate),
+		zap.Uint32("num-keys", ret.loadNumKeys),
+		zap.Bool("contract-calls", ret.loadContractCalls),
+		zap.Duration("duration", ret.loadDuration),
+	)
+	return c.controlc.StartLoad(ctx, &rpcpb.StartLoadRequest{
+		Chains:        ret.loadChains,
+		Rate:          ret.loadRate,
+		NumKeys:       ret.loadNumKeys,
+		ContractCalls: ret.loadContractCalls,
+		Duration:      int64(ret.loadDuration),
+	})
+}
+
+// StopLoad stops sending txs, and returns the final report of the load.
+func (c *client) StopLoad(ctx context.Context) (*rpcpb.StopLoadResponse, error) {
+	zap.L().Info("stop load")
+	return c.controlc.StopLoad(ctx, &rpcpb.StopLoadRequest{})
+}
+
+// LoadStatus returns the report of the load so far.
+func (c *client) LoadStatus(ctx context.Context) (*rpcpb.LoadStatusResponse, error) {
+	zap.L().Debug("load status")
+	return c.controlc.LoadStatus(ctx, &rpcpb.LoadStatusRequest{})
+}
+
+// CheckConsistency compares the blocks accepted by the nodes on the CoreChain and AXChain
+// over the [depth] most recent common heights, and reports the nodes more than [maxLag] blocks behind.
+// Zero values are set to the defaults of the server.
+func (c *client) CheckConsistency(ctx context.Context, depth uint32, maxLag uint64) (*rpcpb.CheckConsistencyResponse, error) {
+	zap.L().Info("check consistency", zap.Uint32("depth", depth), zap.Uint64("max-lag", maxLag))
+	return c.controlc.CheckConsistency(ctx, &rpcpb.CheckConsistencyRequest{Depth: depth, MaxLag: maxLag})
+}
+
+// StreamBlocks returns a channel of the blocks accepted by [chain] from now on, as reported by
+// node [nodeName], or by the first node running [chain] if empty.
+// The channel is closed once [ctx] is done, or the stream ends with the network.
+func (c *client) StreamBlocks(ctx context.Context, chain string, nodeName string) (<-chan *rpcpb.AcceptedBlock, error) {
+	zap.L().Info("stream blocks", zap.String("chain", chain), zap.String("node-name", nodeName))
+	stream, err := c.controlc.StreamBlocks(ctx, &rpcpb.StreamBlocksRequest{
+		Chain:    chain,
+		NodeName: nodeName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *rpcpb.AcceptedBlock, 1)
+	go func() {
+		defer close(ch)
+		for {
+			resp, err := stream.Recv()
+			if err == nil {
+				select {
+				case ch <- resp.GetBlock():
+					continue
+				case <-ctx.Done():
+					return
+				case <-c.closed:
+					return
+				}
+			}
+			if errors.Is(err, io.EOF) {
+				zap.L().Debug("block stream ended")
+				return
+			}
+			if isClientCanceled(stream.Context().Err(), err) {
+				zap.L().Warn("failed to receive block from gRPC stream due to client cancellation", zap.Error(err))
+			} else {
+				zap.L().Warn("failed to receive block from gRPC stream", zap.Error(err))
+			}
+			return
+		}
+	}()
+	return ch, nil
+}
+
 func (c *client) Close() error {
 	c.closeOnce.Do(func() {
 		close(c.closed)
@@ -303,6 +812,50 @@ type Op struct {
 	pluginDir          string
 	customVMs          map[string]string
 	customNodeConfigs  map[string]string
+	nodeExecPaths      map[string]string
+	customVMValidators map[string]*rpcpb.CustomVmValidators
+	chainConfigs       *rpcpb.ChainConfigs
+	nodeChainConfigs   map[string]*rpcpb.ChainConfigs
+
+	customVMReadinessProbes map[string]string
+
+	nodeNames           []string
+	excludeStakingCerts bool
+
+	cpuProfile         bool
+	cpuProfileDuration time.Duration
+	memoryProfile      bool
+	lockProfile        bool
+	profilesOutputDir  string
+
+	dryRun bool
+
+	chaosSeed            int64
+	chaosDuration        time.Duration
+	chaosInterval        time.Duration
+	chaosDownDuration    time.Duration
+	chaosFaultMix        *rpcpb.ChaosFaultMix
+	minHealthyValidators uint32
+
+	batchSize  uint32
+	batchPause time.Duration
+
+	force bool
+
+	subnetOwners    []string
+	subnetThreshold uint32
+	chainName       string
+
+	validator *rpcpb.PrimaryValidatorSpec
+	// staking period of a validator or delegator
+	stakingStart time.Time
+	stakingEnd   time.Time
+
+	loadChains        []string
+	loadRate          uint32
+	loadNumKeys       uint32
+	loadContractCalls bool
+	loadDuration      time.Duration
 }
 
 type OpOption func(*Op)
@@ -363,6 +916,256 @@ func WithCustomNodeConfigs(customNodeConfigs map[string]string) OpOption {
 	}
 }
 
+// Map from node name to the binary it runs, instead of the network exec path
+func WithNodeExecPaths(nodeExecPaths map[string]string) OpOption {
+	return func(op *Op) {
+		op.nodeExecPaths = nodeExecPaths
+	}
+}
+
+// Map from custom VM name to the nodes validating and tracking its subnet,
+// instead of all nodes
+func WithCustomVMValidators(customVMValidators map[string]*rpcpb.CustomVmValidators) OpOption {
+	return func(op *Op) {
+		op.customVMValidators = customVMValidators
+	}
+}
+
+// Chain config, upgrade and subnet config files for all nodes.
+func WithChainConfigs(chainConfigs *rpcpb.ChainConfigs) OpOption {
+	return func(op *Op) {
+		op.chainConfigs = chainConfigs
+	}
+}
+
+// Map from node name to its chain config, upgrade and subnet config files,
+// taking precedence over the ones given with WithChainConfigs.
+func WithNodeChainConfigs(nodeChainConfigs map[string]*rpcpb.ChainConfigs) OpOption {
+	return func(op *Op) {
+		op.nodeChainConfigs = nodeChainConfigs
+	}
+}
+
+// Map from custom VM name to a JSON-RPC method called on its blockchain
+// to check that it is ready, e.g., "eth_blockNumber".
+func WithCustomVMReadinessProbes(probes map[string]string) OpOption {
+	return func(op *Op) {
+		op.customVMReadinessProbes = probes
+	}
+}
+
+// Names of the nodes to operate on, all nodes if empty.
+func WithNodeNames(nodeNames []string) OpOption {
+	return func(op *Op) {
+		op.nodeNames = nodeNames
+	}
+}
+
+func WithExcludeStakingCerts(excludeStakingCerts bool) OpOption {
+	return func(op *Op) {
+		op.excludeStakingCerts = excludeStakingCerts
+	}
+}
+
+// Takes a CPU profile for [duration], the server default if zero.
+func WithCPUProfile(duration time.Duration) OpOption {
+	return func(op *Op) {
+		op.cpuProfile = true
+		op.cpuProfileDuration = duration
+	}
+}
+
+func WithMemoryProfile(memoryProfile bool) OpOption {
+	return func(op *Op) {
+		op.memoryProfile = memoryProfile
+	}
+}
+
+func WithLockProfile(lockProfile bool) OpOption {
+	return func(op *Op) {
+		op.lockProfile = lockProfile
+	}
+}
+
+// Directory on the server host to write the profiles to,
+// instead of returning them.
+func WithProfilesOutputDir(outputDir string) OpOption {
+	return func(op *Op) {
+		op.profilesOutputDir = outputDir
+	}
+}
+
+// Only compute the changes to apply, without applying them.
+func WithDryRun(dryRun bool) OpOption {
+	return func(op *Op) {
+		op.dryRun = dryRun
+	}
+}
+
+// Seed of the fault schedule, to replay a previous run.
+// A random seed is used if zero.
+func WithChaosSeed(seed int64) OpOption {
+	return func(op *Op) {
+		op.chaosSeed = seed
+	}
+}
+
+// Stop injecting faults after [duration], or only on disable if zero.
+func WithChaosDuration(duration time.Duration) OpOption {
+	return func(op *Op) {
+		op.chaosDuration = duration
+	}
+}
+
+func WithChaosInterval(interval time.Duration) OpOption {
+	return func(op *Op) {
+		op.chaosInterval = interval
+	}
+}
+
+// How long a faulted node is kept down before being recovered.
+func WithChaosDownDuration(downDuration time.Duration) OpOption {
+	return func(op *Op) {
+		op.chaosDownDuration = downDuration
+	}
+}
+
+// Relative weights of the faults, all faults being equally likely if all are zero.
+func WithChaosFaultMix(kill uint32, restart uint32, pause uint32, remove uint32) OpOption {
+	return func(op *Op) {
+		op.chaosFaultMix = &rpcpb.ChaosFaultMix{
+			Kill:    kill,
+			Restart: restart,
+			Pause:   pause,
+			Remove:  remove,
+		}
+	}
+}
+
+// Skip faults that would leave less than [minHealthyValidators] healthy validators.
+func WithMinHealthyValidators(minHealthyValidators uint32) OpOption {
+	return func(op *Op) {
+		op.minHealthyValidators = minHealthyValidators
+	}
+}
+
+// Number of nodes restarted at once by an upgrade.
+func WithBatchSize(batchSize uint32) OpOption {
+	return func(op *Op) {
+		op.batchSize = batchSize
+	}
+}
+
+// Pause between the batches of an upgrade.
+func WithBatchPause(pause time.Duration) OpOption {
+	return func(op *Op) {
+		op.batchPause = pause
+	}
+}
+
+// Restart a node or load a snapshot even if the binary's
+// database version is older than the existing database.
+func WithForce(force bool) OpOption {
+	return func(op *Op) {
+		op.force = force
+	}
+}
+
+// P-chain addresses owning a new subnet, and the number of their signatures
+// needed to modify it.
+func WithSubnetOwners(ownerAddresses []string, threshold uint32) OpOption {
+	return func(op *Op) {
+		op.subnetOwners = ownerAddresses
+		op.subnetThreshold = threshold
+	}
+}
+
+// Name of a new blockchain, if not its VM name.
+func WithChainName(chainName string) OpOption {
+	return func(op *Op) {
+		op.chainName = chainName
+	}
+}
+
+// Stake, duration and delegation fee of a primary network validator,
+// the defaults of the server for the zero values.
+// With AddNode, adds the new node as a validator.
+func WithValidator(stake uint64, duration time.Duration, delegationFee uint32) OpOption {
+	return func(op *Op) {
+		op.validator = &rpcpb.PrimaryValidatorSpec{
+			StakeAmount:   stake,
+			Duration:      int64(duration / time.Second),
+			DelegationFee: delegationFee,
+		}
+	}
+}
+
+// Start and end of the staking period of a primary network validator or delegator,
+// e.g. to schedule a validator leaving the validator set at [end].
+// Zero times are set by the server.
+func WithStakingPeriod(start time.Time, end time.Time) OpOption {
+	return func(op *Op) {
+		op.stakingStart = start
+		op.stakingEnd = end
+	}
+}
+
+// Chains receiving load: "X", "C", or the name of a custom EVM whose blockchain was created.
+// Defaults to the SwapChain and AXChain.
+func WithLoadChains(chains []string) OpOption {
+	return func(op *Op) {
+		op.loadChains = chains
+	}
+}
+
+// Number of txs sent per second to each chain, 10 by default.
+func WithLoadRate(rate uint32) OpOption {
+	return func(op *Op) {
+		op.loadRate = rate
+	}
+}
+
+// Number of keys the load is sent from, 10 by default.
+func WithLoadKeys(numKeys uint32) OpOption {
+	return func(op *Op) {
+		op.loadNumKeys = numKeys
+	}
+}
+
+// Sends calls to a contract deployed on the EVM chains, instead of value transfers.
+func WithLoadContractCalls(contractCalls bool) OpOption {
+	return func(op *Op) {
+		op.loadContractCalls = contractCalls
+	}
+}
+
+// Stops the load after [duration]. Runs until stopped if zero.
+func WithLoadDuration(duration time.Duration) OpOption {
+	return func(op *Op) {
+		op.loadDuration = duration
+	}
+}
+
+// primaryValidatorSpec returns the validator set with WithValidator,
+// with the staking period set with WithStakingPeriod
+func (op *Op) primaryValidatorSpec() *rpcpb.PrimaryValidatorSpec {
+	if op.validator == nil {
+		return nil
+	}
+	spec := &rpcpb.PrimaryValidatorSpec{
+		StakeAmount:   op.validator.StakeAmount,
+		Duration:      op.validator.Duration,
+		DelegationFee: op.validator.DelegationFee,
+	}
+	if !op.stakingStart.IsZero() {
+		spec.StartTime = op.stakingStart.Unix()
+	}
+	if !op.stakingEnd.IsZero() {
+		spec.EndTime = op.stakingEnd.Unix()
+	}
+	return spec
+}
+
 func isClientCanceled(ctxErr error, err error) bool {
 	if ctxErr != nil {
 		return true
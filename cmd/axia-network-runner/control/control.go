@@ -7,18 +7,26 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/axiacoin/axia-network-runner/client"
 	"github.com/axiacoin/axia-network-runner/local"
 	"github.com/axiacoin/axia-network-runner/pkg/color"
 	"github.com/axiacoin/axia-network-runner/pkg/logutil"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
 )
 
 func init() {
@@ -52,6 +60,7 @@ func NewCommand() *cobra.Command {
 		newURIsCommand(),
 		newStatusCommand(),
 		newStreamStatusCommand(),
+		newTopCommand(),
 		newAddNodeCommand(),
 		newRemoveNodeCommand(),
 		newRestartNodeCommand(),
@@ -62,6 +71,34 @@ func NewCommand() *cobra.Command {
 		newLoadSnapshotCommand(),
 		newRemoveSnapshotCommand(),
 		newGetSnapshotNamesCommand(),
+		newCollectCommand(),
+		newCollectProfilesCommand(),
+		newSetLogLevelCommand(),
+		newApplyCommand(),
+		newEnableChaosCommand(),
+		newDisableChaosCommand(),
+		newUpgradeCommand(),
+		newRegisterBinaryCommand(),
+		newListBinariesCommand(),
+		newCreateSubnetCommand(),
+		newAddSubnetValidatorsCommand(),
+		newCreateBlockchainCommand(),
+		newInstallCustomVMsCommand(),
+		newFundCommand(),
+		newCreateKeyCommand(),
+		newImportKeyCommand(),
+		newListKeysCommand(),
+		newAddValidatorCommand(),
+		newAddDelegatorCommand(),
+		newCrossChainTransferCommand(),
+		newCreateAssetCommand(),
+		newMintAssetCommand(),
+		newTransferAssetCommand(),
+		newStartLoadCommand(),
+		newStopLoadCommand(),
+		newLoadStatusCommand(),
+		newCheckConsistencyCommand(),
+		newStreamBlocksCommand(),
 	)
 
 	return cmd
@@ -75,6 +112,11 @@ var (
 	addNodeConfig             string
 	customVMNameToGenesisPath string
 	customNodeConfigs         string
+	nodeExecPaths             string
+	customVMValidators        string
+	chainConfigs              string
+	nodeChainConfigs          string
+	customVMReadinessProbes   string
 )
 
 func newStartCommand() *cobra.Command {
@@ -120,6 +162,25 @@ func newStartCommand() *cobra.Command {
 		"",
 		"[optional] custom node configs as JSON string of map, for each node individually. Common entries override `global-node-config`, but can be combined. Invalidates `number-of-nodes` (provide all node configs if used).",
 	)
+	cmd.PersistentFlags().StringVar(
+		&nodeExecPaths,
+		"node-exec-paths",
+		"",
+		"[optional] JSON string of map that maps from node name to its binary path, for nodes not running `axia-path`",
+	)
+	cmd.PersistentFlags().StringVar(
+		&customVMValidators,
+		"custom-vm-validators",
+		"",
+		"[optional] JSON string of map that maps from VM to the nodes validating and tracking its subnet, e.g., '{\"subnetevm\":{\"validators\":[{\"nodeName\":\"node1\",\"weight\":1000}],\"observers\":[\"node2\"]}}'",
+	)
+	addChainConfigsFlags(cmd)
+	cmd.PersistentFlags().StringVar(
+		&customVMReadinessProbes,
+		"custom-vm-readiness-probes",
+		"",
+		"[optional] JSON string of map that maps from VM to a JSON-RPC method called on its blockchain to check that it is ready, e.g., '{\"subnetevm\":\"eth_blockNumber\"}'",
+	)
 	return cmd
 }
 
@@ -155,6 +216,14 @@ func startFunc(cmd *cobra.Command, args []string) error {
 		opts = append(opts, client.WithCustomNodeConfigs(nodeConfigs))
 	}
 
+	if nodeExecPaths != "" {
+		execPaths := make(map[string]string)
+		if err := json.Unmarshal([]byte(nodeExecPaths), &execPaths); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithNodeExecPaths(execPaths))
+	}
+
 	if customVMNameToGenesisPath != "" {
 		customVMs := make(map[string]string)
 		if err := json.Unmarshal([]byte(customVMNameToGenesisPath), &customVMs); err != nil {
@@ -163,6 +232,26 @@ func startFunc(cmd *cobra.Command, args []string) error {
 		opts = append(opts, client.WithCustomVMs(customVMs))
 	}
 
+	if customVMValidators != "" {
+		vmValidators, err := parseCustomVMValidators(customVMValidators)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, client.WithCustomVMValidators(vmValidators))
+	}
+	chainConfigOpts, err := parseChainConfigsFlags()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, chainConfigOpts...)
+	if customVMReadinessProbes != "" {
+		probes := make(map[string]string)
+		if err := json.Unmarshal([]byte(customVMReadinessProbes), &probes); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithCustomVMReadinessProbes(probes))
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
 	// don't call since "start" is async
 	// and the top-level context here "ctx" is passed
@@ -321,7 +410,98 @@ func streamStatusFunc(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-var nodeName string
+func newTopCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "top [options]",
+		Short: "Periodically shows the resource usage of the nodes, until interrupted.",
+		RunE:  topFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().DurationVar(
+		&pushInterval,
+		"push-interval",
+		5*time.Second,
+		"interval that server pushes resource usage updates to the client",
+	)
+	return cmd
+}
+
+func topFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	// runs until os signal, so no request timeout
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+
+	donec := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case sig := <-sigc:
+			zap.L().Warn("received signal", zap.String("signal", sig.String()))
+		case <-ctx.Done():
+		}
+		cancel()
+		close(donec)
+	}()
+
+	ch, err := cli.StreamStatus(ctx, pushInterval)
+	if err != nil {
+		cancel()
+		return err
+	}
+	for info := range ch {
+		printResourceUsage(info)
+	}
+	cancel() // receiver channel is closed, so cancel goroutine
+	<-donec
+	return nil
+}
+
+func printResourceUsage(info *rpcpb.ClusterInfo) {
+	color.Outf("\n{{cyan}}%s{{/}}\n", time.Now().Format(time.RFC3339))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tPID\tCPU\tRSS\tFDS\tTHREADS\tDB SIZE")
+	for _, name := range info.GetNodeNames() {
+		usage := info.GetNodeInfos()[name].GetResourceUsage()
+		if usage == nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\t-\t-\n", name)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%d\t%d\t%s\n",
+			name,
+			usage.Pid,
+			time.Duration(usage.CpuSeconds*float64(time.Second)).Round(time.Millisecond),
+			formatBytes(usage.RssBytes),
+			usage.OpenFds,
+			usage.Threads,
+			formatBytes(usage.DbDirSizeBytes),
+		)
+	}
+	_ = w.Flush()
+}
+
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
+
+var (
+	nodeName string
+	force    bool
+)
 
 func newRemoveNodeCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -383,6 +563,13 @@ func newAddNodeCommand() *cobra.Command {
 		"",
 		"node config as string",
 	)
+	cmd.PersistentFlags().BoolVar(
+		&addAsValidator,
+		"validator",
+		false,
+		"[optional] true to add the node as a primary network validator",
+	)
+	addValidatorFlags(cmd)
 	return cmd
 }
 
@@ -413,6 +600,9 @@ func addNodeFunc(cmd *cobra.Command, args []string) error {
 		}
 		opts = append(opts, client.WithCustomVMs(customVMs))
 	}
+	if addAsValidator {
+		opts = append(opts, validatorOpts()...)
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
 	info, err := cli.AddNode(
@@ -455,6 +645,12 @@ func newRestartNodeCommand() *cobra.Command {
 		"",
 		"whitelisted subnets (comma-separated)",
 	)
+	cmd.PersistentFlags().BoolVar(
+		&force,
+		"force",
+		false,
+		"[optional] restart even if the binary's database version is older than the node's database",
+	)
 	return cmd
 }
 
@@ -471,6 +667,7 @@ func restartNodeFunc(cmd *cobra.Command, args []string) error {
 		nodeName,
 		client.WithExecPath(axiaBinPath),
 		client.WithWhitelistedSubnets(whitelistedSubnets),
+		client.WithForce(force),
 	)
 	cancel()
 	if err != nil {
@@ -641,6 +838,18 @@ func newLoadSnapshotCommand() *cobra.Command {
 		RunE:  loadSnapshotFunc,
 		Args:  cobra.ExactArgs(1),
 	}
+	cmd.PersistentFlags().StringVar(
+		&axiaBinPath,
+		"axia-path",
+		"",
+		"[optional] axia binary path for all the nodes, instead of the binaries saved in the snapshot",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&force,
+		"force",
+		false,
+		"[optional] load even if a binary's database version is older than the saved databases",
+	)
 	return cmd
 }
 
@@ -652,7 +861,7 @@ func loadSnapshotFunc(cmd *cobra.Command, args []string) error {
 	defer cli.Close()
 
 	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-	resp, err := cli.LoadSnapshot(ctx, args[0])
+	resp, err := cli.LoadSnapshot(ctx, args[0], client.WithExecPath(axiaBinPath), client.WithForce(force))
 	cancel()
 	if err != nil {
 		return err
@@ -717,10 +926,1658 @@ func getSnapshotNamesFunc(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func newClient() (client.Client, error) {
-	return client.New(client.Config{
-		LogLevel:    logLevel,
-		Endpoint:    endpoint,
-		DialTimeout: dialTimeout,
-	})
+var (
+	nodeNames           []string
+	excludeStakingCerts bool
+	artifactsOutput     string
+)
+
+func newCollectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collect [options]",
+		Short: "Requests server to bundle the network artifacts into a tar.gz file.",
+		RunE:  collectFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringSliceVar(
+		&nodeNames,
+		"node-names",
+		nil,
+		"[optional] node names to collect artifacts from (comma-separated), all nodes if empty",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&excludeStakingCerts,
+		"exclude-staking-certs",
+		false,
+		"[optional] leave the staking keys and certificates out of the bundle",
+	)
+	cmd.PersistentFlags().StringVar(
+		&artifactsOutput,
+		"output",
+		"artifacts.tar.gz",
+		"path of the tar.gz file to write",
+	)
+	return cmd
+}
+
+func collectFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	f, err := os.Create(artifactsOutput)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	err = cli.CollectArtifacts(
+		ctx,
+		f,
+		client.WithNodeNames(nodeNames),
+		client.WithExcludeStakingCerts(excludeStakingCerts),
+	)
+	cancel()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(artifactsOutput)
+		return err
+	}
+
+	color.Outf("{{green}}artifacts written to:{{/}} %s\n", artifactsOutput)
+	return nil
+}
+
+var (
+	cpuProfile         bool
+	cpuProfileDuration time.Duration
+	memoryProfile      bool
+	lockProfile        bool
+	profilesOutputDir  string
+)
+
+func newCollectProfilesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collect-profiles [options]",
+		Short: "Requests server to profile the nodes through their admin API, and writes the profiles to a local dir.",
+		RunE:  collectProfilesFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringSliceVar(
+		&nodeNames,
+		"node-names",
+		nil,
+		"[optional] node names to profile (comma-separated), all nodes if empty",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&cpuProfile,
+		"cpu",
+		false,
+		"take a CPU profile (all profiles are taken if none is set)",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&cpuProfileDuration,
+		"cpu-duration",
+		10*time.Second,
+		"how long to run the CPU profiler for",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&memoryProfile,
+		"memory",
+		false,
+		"take a memory profile (all profiles are taken if none is set)",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&lockProfile,
+		"lock",
+		false,
+		"take a lock profile (all profiles are taken if none is set)",
+	)
+	cmd.PersistentFlags().StringVar(
+		&profilesOutputDir,
+		"output-dir",
+		"profiles",
+		"dir to write the profiles to, as <output-dir>/<node name>/<profile file>",
+	)
+	return cmd
+}
+
+func collectProfilesFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	opts := []client.OpOption{
+		client.WithNodeNames(nodeNames),
+		client.WithMemoryProfile(memoryProfile),
+		client.WithLockProfile(lockProfile),
+	}
+	if cpuProfile || (!memoryProfile && !lockProfile) {
+		opts = append(opts, client.WithCPUProfile(cpuProfileDuration))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.CollectProfiles(ctx, opts...)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	for _, profile := range resp.Profiles {
+		nodeDir := filepath.Join(profilesOutputDir, profile.NodeName)
+		if err := os.MkdirAll(nodeDir, os.ModePerm); err != nil {
+			return err
+		}
+		profilePath := filepath.Join(nodeDir, profile.FileName)
+		if err := os.WriteFile(profilePath, profile.Data, 0o644); err != nil {
+			return err
+		}
+		color.Outf("{{green}}%s profile of %s written to:{{/}} %s\n", profile.Type, profile.NodeName, profilePath)
+	}
+	return nil
+}
+
+var (
+	loggerName       string
+	nodeLogLevel     string
+	nodeDisplayLevel string
+)
+
+func newSetLogLevelCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-log-level [options]",
+		Short: "Requests server to change the log levels of the nodes through their admin API, without restarting them.",
+		RunE:  setLogLevelFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringSliceVar(
+		&nodeNames,
+		"node-names",
+		nil,
+		"[optional] node names to change the levels of (comma-separated), all nodes if empty",
+	)
+	cmd.PersistentFlags().StringVar(
+		&loggerName,
+		"logger-name",
+		"",
+		"[optional] logger name or chain alias (e.g., C, X, P), all loggers if empty",
+	)
+	cmd.PersistentFlags().StringVar(
+		&nodeLogLevel,
+		"node-log-level",
+		"",
+		"[optional] new log level (e.g., DEBUG), unchanged if empty",
+	)
+	cmd.PersistentFlags().StringVar(
+		&nodeDisplayLevel,
+		"node-display-level",
+		"",
+		"[optional] new display level (e.g., INFO), unchanged if empty",
+	)
+	return cmd
+}
+
+func setLogLevelFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.SetLogLevel(
+		ctx,
+		loggerName,
+		nodeLogLevel,
+		nodeDisplayLevel,
+		client.WithNodeNames(nodeNames),
+	)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}set-log-level response:{{/}} %+v\n", resp)
+	return nil
+}
+
+var (
+	specFile string
+	dryRun   bool
+)
+
+func newApplyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply [options]",
+		Short: "Requests server to start a network from a spec file, or to converge the running network to it.",
+		RunE:  applyFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringVarP(
+		&specFile,
+		"file",
+		"f",
+		"",
+		"network spec file (YAML or JSON)",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"[optional] only print the changes, without applying them",
+	)
+	return cmd
+}
+
+func applyFunc(cmd *cobra.Command, args []string) error {
+	spec, err := os.ReadFile(specFile)
+	if err != nil {
+		return err
+	}
+
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.Apply(ctx, spec, client.WithDryRun(dryRun))
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}apply response:{{/}} %+v\n", resp)
+	return nil
+}
+
+var (
+	chaosSeed            int64
+	chaosDuration        time.Duration
+	chaosInterval        time.Duration
+	chaosDownDuration    time.Duration
+	killWeight           uint32
+	restartWeight        uint32
+	pauseWeight          uint32
+	removeWeight         uint32
+	minHealthyValidators uint32
+)
+
+func newEnableChaosCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enable-chaos [options]",
+		Short: "Requests server to inject random faults into the non-beacon nodes.",
+		RunE:  enableChaosFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().Int64Var(
+		&chaosSeed,
+		"seed",
+		0,
+		"[optional] seed of the fault schedule, to replay a previous run (random if zero)",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&chaosDuration,
+		"duration",
+		0,
+		"[optional] how long to inject faults for (until disabled if zero)",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&chaosInterval,
+		"interval",
+		30*time.Second,
+		"[optional] interval between faults",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&chaosDownDuration,
+		"down-duration",
+		10*time.Second,
+		"[optional] how long a faulted node stays down",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&killWeight,
+		"kill-weight",
+		0,
+		"[optional] relative weight of node kills",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&restartWeight,
+		"restart-weight",
+		0,
+		"[optional] relative weight of node restarts",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&pauseWeight,
+		"pause-weight",
+		0,
+		"[optional] relative weight of node pauses",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&removeWeight,
+		"remove-weight",
+		0,
+		"[optional] relative weight of node removals",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&minHealthyValidators,
+		"min-healthy-validators",
+		0,
+		"[optional] skip faults that would leave less healthy validators",
+	)
+	return cmd
+}
+
+func enableChaosFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.EnableChaos(
+		ctx,
+		client.WithChaosSeed(chaosSeed),
+		client.WithChaosDuration(chaosDuration),
+		client.WithChaosInterval(chaosInterval),
+		client.WithChaosDownDuration(chaosDownDuration),
+		client.WithChaosFaultMix(killWeight, restartWeight, pauseWeight, removeWeight),
+		client.WithMinHealthyValidators(minHealthyValidators),
+	)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}enable-chaos response:{{/}} %+v\n", resp)
+	return nil
+}
+
+func newDisableChaosCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "disable-chaos [options]",
+		Short: "Requests server to stop injecting faults, and prints the faults injected.",
+		RunE:  disableChaosFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func disableChaosFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.DisableChaos(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}chaos seed:{{/}} %d\n", resp.Seed)
+	for _, action := range resp.Actions {
+		line := fmt.Sprintf("%s %s %s", time.Unix(0, action.Timestamp).Format(time.RFC3339Nano), action.Fault, action.NodeName)
+		if action.Error != "" {
+			color.Outf("{{red}}%s: %s{{/}}\n", line, action.Error)
+			continue
+		}
+		color.Outf("%s\n", line)
+	}
+	return nil
+}
+
+var (
+	batchSize  uint32
+	batchPause time.Duration
+)
+
+func newUpgradeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade [options]",
+		Short: "Requests server to restart the nodes with a new binary, one batch at a time.",
+		RunE:  upgradeFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringVar(
+		&axiaBinPath,
+		"axia-path",
+		"",
+		"axia binary path to upgrade the nodes to",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&nodeNames,
+		"node-names",
+		nil,
+		"[optional] node names to upgrade in order (comma-separated), all nodes if empty",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&batchSize,
+		"batch-size",
+		1,
+		"[optional] number of nodes restarted at once",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&batchPause,
+		"pause",
+		0,
+		"[optional] pause between batches",
+	)
+	cmd.PersistentFlags().StringVar(
+		&globalNodeConfig,
+		"global-node-config",
+		"",
+		"[optional] node config as JSON string, merged into the config of every upgraded node",
+	)
+	return cmd
+}
+
+func upgradeFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	opts := []client.OpOption{
+		client.WithNodeNames(nodeNames),
+		client.WithBatchSize(batchSize),
+		client.WithBatchPause(batchPause),
+	}
+	if globalNodeConfig != "" {
+		opts = append(opts, client.WithGlobalNodeConfig(globalNodeConfig))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.UpgradeNetwork(ctx, axiaBinPath, opts...)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	for _, info := range resp.UpgradedNodes {
+		if info.Error != "" {
+			color.Outf("{{red}}%s: failed after %v (was %q): %s{{/}}\n", info.NodeName, time.Duration(info.Duration), info.OldExecPath, info.Error)
+			continue
+		}
+		color.Outf("{{green}}%s: upgraded in %v (was %q){{/}}\n", info.NodeName, time.Duration(info.Duration), info.OldExecPath)
+	}
+	if resp.Error != "" {
+		if len(resp.PendingNodes) > 0 {
+			color.Outf("{{yellow}}not upgraded:{{/}} %v\n", resp.PendingNodes)
+		}
+		return fmt.Errorf("upgrade stopped: %s", resp.Error)
+	}
+	color.Outf("{{green}}upgraded %d nodes to %q{{/}}\n", len(resp.UpgradedNodes), axiaBinPath)
+	return nil
+}
+
+func newRegisterBinaryCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "register-binary alias exec-path",
+		Short: "Requests server to register a binary under an alias, usable instead of exec paths.",
+		RunE:  registerBinaryFunc,
+		Args:  cobra.ExactArgs(2),
+	}
+	return cmd
+}
+
+func registerBinaryFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.RegisterBinary(ctx, args[0], args[1])
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}register-binary response:{{/}} %+v\n", resp)
+	return nil
+}
+
+func newListBinariesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-binaries [options]",
+		Short: "Requests server to list the registered binaries.",
+		RunE:  listBinariesFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func listBinariesFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	binaries, err := cli.ListBinaries(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ALIAS\tAPP VERSION\tDB VERSION\tSHA256\tEXEC PATH")
+	for _, binary := range binaries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			binary.Alias,
+			binary.AppVersion,
+			binary.DbVersion,
+			binary.Sha256,
+			binary.ExecPath,
+		)
+	}
+	return w.Flush()
+}
+
+var (
+	subnetOwners    []string
+	subnetThreshold uint32
+)
+
+func newCreateSubnetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-subnet [options]",
+		Short: "Requests server to create a subnet.",
+		RunE:  createSubnetFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringSliceVar(
+		&subnetOwners,
+		"owner-addresses",
+		nil,
+		"[optional] P-chain addresses owning the subnet (comma-separated), the pre-funded test key if empty",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&subnetThreshold,
+		"threshold",
+		1,
+		"[optional] number of owner signatures needed to modify the subnet",
+	)
+	return cmd
+}
+
+func createSubnetFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.CreateSubnet(ctx, client.WithSubnetOwners(subnetOwners, subnetThreshold))
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}created subnet:{{/}} %s\n", resp.SubnetId)
+	return nil
+}
+
+var (
+	validatorWeight     uint64
+	validatorStartDelay time.Duration
+	validatorDuration   time.Duration
+)
+
+func newAddSubnetValidatorsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-subnet-validators subnet-id [options]",
+		Short: "Requests server to add nodes as validators of a subnet, restarting them to whitelist it if needed.",
+		RunE:  addSubnetValidatorsFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	cmd.PersistentFlags().StringSliceVar(
+		&nodeNames,
+		"node-names",
+		nil,
+		"node names to add as validators (comma-separated)",
+	)
+	cmd.PersistentFlags().Uint64Var(
+		&validatorWeight,
+		"weight",
+		0,
+		"[optional] weight of each validator, 1000 if zero",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&validatorStartDelay,
+		"start-delay",
+		0,
+		"[optional] delay before the validations start, one minute if zero",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&validatorDuration,
+		"validation-duration",
+		0,
+		"[optional] duration of the validations, 100 hours if zero",
+	)
+	return cmd
+}
+
+func addSubnetValidatorsFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	// zero times are set by the server
+	var startTime, endTime int64
+	if validatorStartDelay > 0 || validatorDuration > 0 {
+		startDelay := validatorStartDelay
+		if startDelay == 0 {
+			startDelay = time.Minute
+		}
+		start := time.Now().Add(startDelay)
+		startTime = start.Unix()
+		if validatorDuration > 0 {
+			endTime = start.Add(validatorDuration).Unix()
+		}
+	}
+	validators := make([]*rpcpb.SubnetValidatorSpec, 0, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		validators = append(validators, &rpcpb.SubnetValidatorSpec{
+			NodeName:  nodeName,
+			Weight:    validatorWeight,
+			StartTime: startTime,
+			EndTime:   endTime,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.AddSubnetValidators(ctx, args[0], validators)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	for _, nodeName := range nodeNames {
+		color.Outf("{{green}}%s: added as validator of %s{{/}} (tx %s)\n", nodeName, args[0], resp.TxIds[nodeName])
+	}
+	return nil
+}
+
+var chainName string
+
+func newCreateBlockchainCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-blockchain subnet-id vm-name genesis-path [options]",
+		Short: "Requests server to create a blockchain of a custom VM on a subnet.",
+		RunE:  createBlockchainFunc,
+		Args:  cobra.ExactArgs(3),
+	}
+	cmd.PersistentFlags().StringVar(
+		&chainName,
+		"chain-name",
+		"",
+		"[optional] name of the blockchain, the VM name if empty",
+	)
+	return cmd
+}
+
+func createBlockchainFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.CreateBlockchain(ctx, args[0], args[1], args[2], client.WithChainName(chainName))
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}created blockchain:{{/}} %+v\n", resp.Blockchain)
+	return nil
+}
+
+func newInstallCustomVMsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install-custom-vms [options]",
+		Short: "Requests server to install custom VMs on the running network.",
+		RunE:  installCustomVMsFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringVar(
+		&pluginDir,
+		"plugin-dir",
+		"",
+		"[optional] plugin directory, the one of the network if empty",
+	)
+	cmd.PersistentFlags().StringVar(
+		&customVMNameToGenesisPath,
+		"custom-vms",
+		"",
+		"JSON string of map that maps from VM to its genesis file path",
+	)
+	cmd.PersistentFlags().StringVar(
+		&customVMValidators,
+		"custom-vm-validators",
+		"",
+		"[optional] JSON string of map that maps from VM to the nodes validating and tracking its subnet, e.g., '{\"subnetevm\":{\"validators\":[{\"nodeName\":\"node1\",\"weight\":1000}],\"observers\":[\"node2\"]}}'",
+	)
+	addChainConfigsFlags(cmd)
+	cmd.PersistentFlags().StringVar(
+		&customVMReadinessProbes,
+		"custom-vm-readiness-probes",
+		"",
+		"[optional] JSON string of map that maps from VM to a JSON-RPC method called on its blockchain to check that it is ready, e.g., '{\"subnetevm\":\"eth_blockNumber\"}'",
+	)
+	return cmd
+}
+
+func installCustomVMsFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	customVMs := make(map[string]string)
+	if customVMNameToGenesisPath != "" {
+		if err := json.Unmarshal([]byte(customVMNameToGenesisPath), &customVMs); err != nil {
+			return err
+		}
+	}
+
+	opts := []client.OpOption{client.WithPluginDir(pluginDir)}
+	if customVMValidators != "" {
+		vmValidators, err := parseCustomVMValidators(customVMValidators)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, client.WithCustomVMValidators(vmValidators))
+	}
+	chainConfigOpts, err := parseChainConfigsFlags()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, chainConfigOpts...)
+	if customVMReadinessProbes != "" {
+		probes := make(map[string]string)
+		if err := json.Unmarshal([]byte(customVMReadinessProbes), &probes); err != nil {
+			return err
+		}
+		opts = append(opts, client.WithCustomVMReadinessProbes(probes))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.InstallCustomVMs(ctx, customVMs, opts...)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	for vmID, vmInfo := range resp.ClusterInfo.CustomVms {
+		color.Outf("{{green}}%s (%s):{{/}} subnet %s, blockchain %s\n", vmInfo.VmName, vmID, vmInfo.SubnetId, vmInfo.BlockchainId)
+	}
+	return nil
+}
+
+// parseCustomVMValidators parses a JSON map from VM name to its validators,
+// with the field names of the REST API
+func parseCustomVMValidators(s string) (map[string]*rpcpb.CustomVmValidators, error) {
+	rawValidators := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(s), &rawValidators); err != nil {
+		return nil, err
+	}
+	vmValidators := make(map[string]*rpcpb.CustomVmValidators, len(rawValidators))
+	for vmName, raw := range rawValidators {
+		validators := &rpcpb.CustomVmValidators{}
+		if err := protojson.Unmarshal(raw, validators); err != nil {
+			return nil, fmt.Errorf("invalid validators for %q: %w", vmName, err)
+		}
+		vmValidators[vmName] = validators
+	}
+	return vmValidators, nil
+}
+
+func addChainConfigsFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(
+		&chainConfigs,
+		"chain-configs",
+		"",
+		"[optional] JSON string of the chain config, upgrade and subnet config file paths for all nodes, by chain alias, blockchain ID or VM name, and by subnet ID or VM name, e.g., '{\"chainConfigs\":{\"C\":\"/tmp/c.json\",\"subnetevm\":\"/tmp/config.json\"},\"upgradeConfigs\":{\"subnetevm\":\"/tmp/upgrade.json\"},\"subnetConfigs\":{\"subnetevm\":\"/tmp/subnet.json\"}}'",
+	)
+	cmd.PersistentFlags().StringVar(
+		&nodeChainConfigs,
+		"node-chain-configs",
+		"",
+		"[optional] JSON string of map that maps from node name to its chain config, upgrade and subnet config file paths as in `chain-configs`, taking precedence over them",
+	)
+}
+
+// parseChainConfigsFlags returns the options for the chain config files
+// of the "chain-configs" and "node-chain-configs" flags
+func parseChainConfigsFlags() ([]client.OpOption, error) {
+	opts := []client.OpOption{}
+	if chainConfigs != "" {
+		configs, err := parseChainConfigs([]byte(chainConfigs))
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, client.WithChainConfigs(configs))
+	}
+	if nodeChainConfigs != "" {
+		rawConfigs := make(map[string]json.RawMessage)
+		if err := json.Unmarshal([]byte(nodeChainConfigs), &rawConfigs); err != nil {
+			return nil, err
+		}
+		nodeConfigs := make(map[string]*rpcpb.ChainConfigs, len(rawConfigs))
+		for nodeName, raw := range rawConfigs {
+			configs, err := parseChainConfigs(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid chain configs for %q: %w", nodeName, err)
+			}
+			nodeConfigs[nodeName] = configs
+		}
+		opts = append(opts, client.WithNodeChainConfigs(nodeConfigs))
+	}
+	return opts, nil
+}
+
+// parseChainConfigs parses JSON chain configs with file paths,
+// and replaces the paths by the contents of the files
+func parseChainConfigs(b []byte) (*rpcpb.ChainConfigs, error) {
+	configs := &rpcpb.ChainConfigs{}
+	if err := protojson.Unmarshal(b, configs); err != nil {
+		return nil, err
+	}
+	for _, files := range []map[string]string{configs.ChainConfigs, configs.UpgradeConfigs, configs.SubnetConfigs} {
+		for k, path := range files {
+			contents, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read config file for %q: %w", k, err)
+			}
+			files[k] = string(contents)
+		}
+	}
+	return configs, nil
+}
+
+func newClient() (client.Client, error) {
+	return client.New(client.Config{
+		LogLevel:    logLevel,
+		Endpoint:    endpoint,
+		DialTimeout: dialTimeout,
+	})
+}
+
+func newFundCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fund address amount",
+		Short: "Requests server to send AXC (amount in nAXC) from the pre-funded test key to a SwapChain, CoreChain or AXChain address.",
+		RunE:  fundFunc,
+		Args:  cobra.ExactArgs(2),
+	}
+	return cmd
+}
+
+func fundFunc(cmd *cobra.Command, args []string) error {
+	amount, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", args[1], err)
+	}
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.Fund(ctx, args[0], amount)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}sent %d nAXC to %s{{/}} (tx %s)\n", amount, args[0], resp.TxId)
+	return nil
+}
+
+var transferAddress string
+
+func newCrossChainTransferCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cross-chain-transfer source-chain destination-chain amount [options]",
+		Short: "Requests server to move AXC (amount in nAXC) of the pre-funded test key between the X, P and C chains.",
+		RunE:  crossChainTransferFunc,
+		Args:  cobra.ExactArgs(3),
+	}
+	cmd.PersistentFlags().StringVar(
+		&transferAddress,
+		"address",
+		"",
+		"[optional] address to credit on the destination chain, the pre-funded test key's if empty",
+	)
+	return cmd
+}
+
+func crossChainTransferFunc(cmd *cobra.Command, args []string) error {
+	amount, err := strconv.ParseUint(args[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", args[2], err)
+	}
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.CrossChainTransfer(ctx, args[0], args[1], amount, transferAddress)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}moved %d nAXC from the %sChain to the %sChain{{/}} (txs %s)\n", amount, args[0], args[1], strings.Join(resp.TxIds, ", "))
+	return nil
+}
+
+var (
+	assetDenomination   uint32
+	assetVariableCap    bool
+	assetInitialHolders []string
+	assetKeyName        string
+)
+
+func newCreateAssetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-asset name symbol [options]",
+		Short: "Requests server to create a fixed-cap or variable-cap asset on the SwapChain.",
+		RunE:  createAssetFunc,
+		Args:  cobra.ExactArgs(2),
+	}
+	cmd.PersistentFlags().Uint32Var(
+		&assetDenomination,
+		"denomination",
+		0,
+		"[optional] number of decimal places of the asset",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&assetVariableCap,
+		"variable-cap",
+		false,
+		"[optional] true to let the pre-funded test key mint the asset",
+	)
+	cmd.PersistentFlags().StringSliceVar(
+		&assetInitialHolders,
+		"initial-holders",
+		nil,
+		"initial supply of the asset as address=amount pairs (comma-separated), required for a fixed-cap asset",
+	)
+	return cmd
+}
+
+func createAssetFunc(cmd *cobra.Command, args []string) error {
+	holders := make([]*rpcpb.AssetHolder, 0, len(assetInitialHolders))
+	for _, s := range assetInitialHolders {
+		ss := strings.Split(s, "=")
+		if len(ss) != 2 {
+			return fmt.Errorf("invalid initial holder %q, expected address=amount", s)
+		}
+		amount, err := strconv.ParseUint(ss[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid amount %q: %w", ss[1], err)
+		}
+		holders = append(holders, &rpcpb.AssetHolder{Address: ss[0], Amount: amount})
+	}
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.CreateAsset(ctx, args[0], args[1], assetDenomination, assetVariableCap, holders)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}created asset %s:{{/}} %s\n", args[0], resp.AssetId)
+	return nil
+}
+
+func newMintAssetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mint-asset asset-id address amount",
+		Short: "Requests server to mint a variable-cap asset to a SwapChain address.",
+		RunE:  mintAssetFunc,
+		Args:  cobra.ExactArgs(3),
+	}
+	return cmd
+}
+
+func mintAssetFunc(cmd *cobra.Command, args []string) error {
+	amount, err := strconv.ParseUint(args[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", args[2], err)
+	}
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.MintAsset(ctx, args[0], args[1], amount)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}minted %d of %s to %s{{/}} (tx %s)\n", amount, args[0], args[1], resp.TxId)
+	return nil
+}
+
+func newTransferAssetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer-asset asset-id address amount [options]",
+		Short: "Requests server to send an asset from a key of its keychain to a SwapChain address.",
+		RunE:  transferAssetFunc,
+		Args:  cobra.ExactArgs(3),
+	}
+	cmd.PersistentFlags().StringVar(
+		&assetKeyName,
+		"key-name",
+		"",
+		"[optional] name of the key to send the asset from, the pre-funded test key if empty",
+	)
+	return cmd
+}
+
+func transferAssetFunc(cmd *cobra.Command, args []string) error {
+	amount, err := strconv.ParseUint(args[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %q: %w", args[2], err)
+	}
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.TransferAsset(ctx, args[0], assetKeyName, args[1], amount)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}sent %d of %s to %s{{/}} (tx %s)\n", amount, args[0], args[1], resp.TxId)
+	return nil
+}
+
+func newCreateKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-key name [options]",
+		Short: "Requests server to generate a key in its keychain.",
+		RunE:  createKeyFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	return cmd
+}
+
+func createKeyFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	key, err := cli.CreateKey(ctx, args[0])
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	printKeys([]*rpcpb.KeyInfo{key}, true)
+	return nil
+}
+
+func newImportKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-key name private-key [options]",
+		Short: "Requests server to import a \"PrivateKey-\" prefixed CB58 or hex encoded private key into its keychain.",
+		RunE:  importKeyFunc,
+		Args:  cobra.ExactArgs(2),
+	}
+	return cmd
+}
+
+func importKeyFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	key, err := cli.ImportKey(ctx, args[0], args[1])
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	printKeys([]*rpcpb.KeyInfo{key}, false)
+	return nil
+}
+
+var showPrivateKeys bool
+
+func newListKeysCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-keys [options]",
+		Short: "Requests server to list the keys of its keychain, with their addresses and balances.",
+		RunE:  listKeysFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().BoolVar(
+		&showPrivateKeys,
+		"show-private-keys",
+		false,
+		"[optional] true to also print the private keys",
+	)
+	return cmd
+}
+
+func listKeysFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	keys, err := cli.ListKeys(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	printKeys(keys, showPrivateKeys)
+	return nil
+}
+
+// printKeys prints the addresses and balances (in nAXC) of [keys],
+// and their private keys if [showPrivate]
+func printKeys(keys []*rpcpb.KeyInfo, showPrivate bool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCHAIN\tADDRESS\tBALANCE")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\tX\t%s\t%d\n", key.Name, key.XAddress, key.XBalance)
+		fmt.Fprintf(w, "%s\tP\t%s\t%d\n", key.Name, key.PAddress, key.PBalance)
+		fmt.Fprintf(w, "%s\tC\t%s\t%d\n", key.Name, key.CAddress, key.CBalance)
+	}
+	w.Flush()
+	if !showPrivate {
+		return
+	}
+	for _, key := range keys {
+		color.Outf("{{green}}%s private key:{{/}} %s (hex %s)\n", key.Name, key.PrivateKey, key.PrivateKeyHex)
+	}
+}
+
+var (
+	addAsValidator         bool
+	validatorStake         uint64
+	validatorDelegationFee uint32
+)
+
+func newAddValidatorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-validator node-name [options]",
+		Short: "Requests server to add a node as a primary network validator, staking from the pre-funded test key.",
+		RunE:  addValidatorFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	addValidatorFlags(cmd)
+	return cmd
+}
+
+func addValidatorFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().Uint64Var(
+		&validatorStake,
+		"stake",
+		0,
+		"[optional] stake of the validator in nAXC, 1 AXC if zero",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&validatorDuration,
+		"validation-duration",
+		0,
+		"[optional] duration of the validation, 300 hours if zero",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&validatorDelegationFee,
+		"delegation-fee",
+		0,
+		"[optional] delegation fee in shares of 1,000,000 (e.g., 100000 for 10%), 10% if zero",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&validatorStartDelay,
+		"start-delay",
+		0,
+		"[optional] delay before the validation starts, to schedule it without waiting for its start",
+	)
+}
+
+// validatorOpts returns the client options of the validator flags
+func validatorOpts() []client.OpOption {
+	opts := []client.OpOption{
+		client.WithValidator(validatorStake, validatorDuration, validatorDelegationFee),
+	}
+	if validatorStartDelay > 0 {
+		// the end is set by the server from the duration
+		opts = append(opts, client.WithStakingPeriod(time.Now().Add(validatorStartDelay), time.Time{}))
+	}
+	return opts
+}
+
+func addValidatorFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.AddValidator(ctx, args[0], validatorOpts()...)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}%s: added as primary network validator{{/}} (tx %s)\n", args[0], resp.TxId)
+	return nil
+}
+
+var (
+	delegatorStake     uint64
+	delegationDuration time.Duration
+)
+
+func newAddDelegatorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-delegator node-name [options]",
+		Short: "Requests server to delegate stake from the pre-funded test key to a primary network validator.",
+		RunE:  addDelegatorFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	cmd.PersistentFlags().Uint64Var(
+		&delegatorStake,
+		"stake",
+		0,
+		"[optional] stake of the delegator in nAXC, 1 AXC if zero",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&validatorStartDelay,
+		"start-delay",
+		0,
+		"[optional] delay before the delegation starts, 20 seconds if zero",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&delegationDuration,
+		"delegation-duration",
+		0,
+		"[optional] duration of the delegation, until the end of the validation if zero",
+	)
+	return cmd
+}
+
+func addDelegatorFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	// zero times are set by the server
+	var start, end time.Time
+	if validatorStartDelay > 0 || delegationDuration > 0 {
+		startDelay := validatorStartDelay
+		if startDelay == 0 {
+			startDelay = 20 * time.Second
+		}
+		start = time.Now().Add(startDelay)
+		if delegationDuration > 0 {
+			end = start.Add(delegationDuration)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.AddDelegator(ctx, args[0], delegatorStake, client.WithStakingPeriod(start, end))
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}%s: delegated to{{/}} (tx %s)\n", args[0], resp.TxId)
+	return nil
+}
+
+var (
+	loadChains        []string
+	loadRate          uint32
+	loadNumKeys       uint32
+	loadContractCalls bool
+	loadDuration      time.Duration
+)
+
+func newStartLoadCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start-load [options]",
+		Short: "Requests server to send txs at a target rate to chains of the network, from generated keys.",
+		RunE:  startLoadFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().StringSliceVar(
+		&loadChains,
+		"chains",
+		nil,
+		"[optional] chains to send txs to: X, C, or the name of a custom EVM (X and C if empty)",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&loadRate,
+		"rate",
+		0,
+		"[optional] txs sent per second to each chain, 10 if zero",
+	)
+	cmd.PersistentFlags().Uint32Var(
+		&loadNumKeys,
+		"num-keys",
+		0,
+		"[optional] number of keys to send txs from, 10 if zero",
+	)
+	cmd.PersistentFlags().BoolVar(
+		&loadContractCalls,
+		"contract-calls",
+		false,
+		"[optional] true to call a contract on the EVM chains, instead of transferring value",
+	)
+	cmd.PersistentFlags().DurationVar(
+		&loadDuration,
+		"duration",
+		0,
+		"[optional] how long to send txs for (until stopped if zero)",
+	)
+	return cmd
+}
+
+func startLoadFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.StartLoad(
+		ctx,
+		client.WithLoadChains(loadChains),
+		client.WithLoadRate(loadRate),
+		client.WithLoadKeys(loadNumKeys),
+		client.WithLoadContractCalls(loadContractCalls),
+		client.WithLoadDuration(loadDuration),
+	)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	color.Outf("{{green}}load started{{/}}\n")
+	printLoadReport(resp.Report)
+	return nil
+}
+
+func newStopLoadCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop-load [options]",
+		Short: "Requests server to stop sending txs, and prints the final load report.",
+		RunE:  stopLoadFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func stopLoadFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.StopLoad(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	printLoadReport(resp.Report)
+	return nil
+}
+
+func newLoadStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "load-status [options]",
+		Short: "Requests server to print the report of the load so far.",
+		RunE:  loadStatusFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	return cmd
+}
+
+func loadStatusFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.LoadStatus(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	printLoadReport(resp.Report)
+	return nil
+}
+
+// printLoadReport prints the stats of [report] by chain
+func printLoadReport(report *rpcpb.LoadReport) {
+	start := time.Unix(0, report.StartTime)
+	if report.Running {
+		color.Outf("{{green}}load running{{/}} for %v, %d txs/s per chain from %d keys\n",
+			time.Since(start).Round(time.Second), report.Rate, report.NumKeys)
+	} else {
+		color.Outf("{{green}}load done{{/}} after %v, %d txs/s per chain from %d keys\n",
+			time.Unix(0, report.EndTime).Sub(start).Round(time.Second), report.Rate, report.NumKeys)
+	}
+	chains := make([]string, 0, len(report.Chains))
+	for chain := range report.Chains {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHAIN\tSENT\tACCEPTED\tFAILED\tACCEPTED/S\tP50\tP90\tP99")
+	for _, chain := range chains {
+		stats := report.Chains[chain]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1f\t%v\t%v\t%v\n",
+			chain, stats.Sent, stats.Accepted, stats.Failed, stats.AcceptedPerSecond,
+			time.Duration(stats.LatencyP50).Round(time.Millisecond),
+			time.Duration(stats.LatencyP90).Round(time.Millisecond),
+			time.Duration(stats.LatencyP99).Round(time.Millisecond),
+		)
+	}
+	w.Flush()
+}
+
+var (
+	consistencyDepth  uint32
+	consistencyMaxLag uint64
+)
+
+func newCheckConsistencyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check-consistency [options]",
+		Short: "Requests server to compare the blocks accepted by the nodes, and to report forks and lagging nodes.",
+		RunE:  checkConsistencyFunc,
+		Args:  cobra.ExactArgs(0),
+	}
+	cmd.PersistentFlags().Uint32Var(
+		&consistencyDepth,
+		"depth",
+		0,
+		"[optional] number of heights checked per chain, 10 if zero",
+	)
+	cmd.PersistentFlags().Uint64Var(
+		&consistencyMaxLag,
+		"max-lag",
+		0,
+		"[optional] number of blocks a node can be behind before being reported as lagging, 5 if zero",
+	)
+	return cmd
+}
+
+func checkConsistencyFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	resp, err := cli.CheckConsistency(ctx, consistencyDepth, consistencyMaxLag)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	for _, chain := range resp.Chains {
+		nodeNames := make([]string, 0, len(chain.Heights))
+		for name := range chain.Heights {
+			nodeNames = append(nodeNames, name)
+		}
+		sort.Strings(nodeNames)
+		heights := make([]string, 0, len(nodeNames))
+		for _, name := range nodeNames {
+			heights = append(heights, fmt.Sprintf("%s=%d", name, chain.Heights[name]))
+		}
+		color.Outf("{{green}}%s heights:{{/}} %s\n", chain.Chain, strings.Join(heights, " "))
+		for _, d := range chain.Disagreements {
+			color.Outf("{{red}}%s fork at height %d:{{/}} %v\n", chain.Chain, d.Height, d.BlockIds)
+		}
+		if len(chain.LaggingNodes) > 0 {
+			color.Outf("{{yellow}}%s lagging nodes:{{/}} %s\n", chain.Chain, strings.Join(chain.LaggingNodes, " "))
+		}
+		for name, errMsg := range chain.Errors {
+			color.Outf("{{red}}%s %s:{{/}} %s\n", chain.Chain, name, errMsg)
+		}
+	}
+	if !resp.Consistent {
+		return errors.New("network not consistent")
+	}
+	color.Outf("{{green}}network consistent{{/}}\n")
+	return nil
+}
+
+var (
+	blocksNodeName  string
+	blocksNumBlocks uint64
+)
+
+func newStreamBlocksCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stream-blocks chain [options]",
+		Short: "Prints the blocks accepted by a chain (X, P, C or the name of a custom EVM), until interrupted.",
+		RunE:  streamBlocksFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	cmd.PersistentFlags().StringVar(
+		&blocksNodeName,
+		"node-name",
+		"",
+		"[optional] node reporting the blocks, the first node running the chain if empty",
+	)
+	cmd.PersistentFlags().Uint64Var(
+		&blocksNumBlocks,
+		"num-blocks",
+		0,
+		"[optional] number of blocks to wait for before returning (until interrupted if zero)",
+	)
+	return cmd
+}
+
+func streamBlocksFunc(cmd *cobra.Command, args []string) error {
+	cli, err := newClient()
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	// stream until the number of blocks is reached or os signal
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+
+	donec := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case sig := <-sigc:
+			zap.L().Warn("received signal", zap.String("signal", sig.String()))
+		case <-ctx.Done():
+		}
+		cancel()
+		close(donec)
+	}()
+
+	ch, err := cli.StreamBlocks(ctx, args[0], blocksNodeName)
+	if err != nil {
+		cancel()
+		return err
+	}
+	received := uint64(0)
+	for blk := range ch {
+		color.Outf("{{cyan}}%s block %d{{/}} %s (%s, %d txs, from %s)\n",
+			blk.Chain, blk.Height, blk.BlockId, time.Unix(0, blk.Timestamp).Format(time.RFC3339), blk.NumTxs, blk.NodeName)
+		for _, txID := range blk.TxIds {
+			color.Outf("  tx %s\n", txID)
+		}
+		received++
+		if blocksNumBlocks > 0 && received >= blocksNumBlocks {
+			break
+		}
+	}
+	cancel()
+	<-donec
+	if blocksNumBlocks > 0 && received < blocksNumBlocks {
+		return fmt.Errorf("stream ended after %d of %d blocks", received, blocksNumBlocks)
+	}
+	return nil
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/axiacoin/axia-network-runner/cmd/axia-network-runner/control"
 	"github.com/axiacoin/axia-network-runner/cmd/axia-network-runner/ping"
+	"github.com/axiacoin/axia-network-runner/cmd/axia-network-runner/scenario"
 	"github.com/axiacoin/axia-network-runner/cmd/axia-network-runner/server"
 	"github.com/spf13/cobra"
 )
@@ -28,6 +29,7 @@ func init() {
 		server.NewCommand(),
 		ping.NewCommand(),
 		control.NewCommand(),
+		scenario.NewCommand(),
 	)
 }
 
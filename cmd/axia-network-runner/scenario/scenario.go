@@ -0,0 +1,113 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/client"
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+	"github.com/axiacoin/axia-network-runner/pkg/logutil"
+	"github.com/axiacoin/axia-network-runner/scenario"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logLevel    string
+	endpoint    string
+	dialTimeout time.Duration
+	junitReport string
+)
+
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scenario [options]",
+		Short: "Run scripted test flows against the server.",
+	}
+
+	cmd.PersistentFlags().StringVar(&logLevel, "log-level", logutil.DefaultLogLevel, "log level")
+	cmd.PersistentFlags().StringVar(&endpoint, "endpoint", "0.0.0.0:8080", "server endpoint")
+	cmd.PersistentFlags().DurationVar(&dialTimeout, "dial-timeout", 10*time.Second, "server dial timeout")
+
+	cmd.AddCommand(newRunCommand())
+
+	return cmd
+}
+
+func newRunCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run scenario-file",
+		Short: "Runs the steps of a YAML scenario file in order, and fails if any step fails.",
+		RunE:  runFunc,
+		Args:  cobra.ExactArgs(1),
+	}
+	cmd.PersistentFlags().StringVar(
+		&junitReport,
+		"junit-report",
+		"",
+		"[optional] path to write a JUnit XML report of the steps to",
+	)
+	return cmd
+}
+
+func runFunc(cmd *cobra.Command, args []string) error {
+	b, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	sc, err := scenario.Parse(b)
+	if err != nil {
+		return err
+	}
+	if sc.Name == "" {
+		sc.Name = args[0]
+	}
+
+	cli, err := client.New(client.Config{
+		LogLevel:    logLevel,
+		Endpoint:    endpoint,
+		DialTimeout: dialTimeout,
+	})
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	// runs until all steps are done or os signal, each step has its own timeout
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	results := scenario.NewRunner(cli).Run(ctx, sc)
+	cancel()
+
+	if junitReport != "" {
+		f, err := os.Create(junitReport)
+		if err != nil {
+			return err
+		}
+		err = scenario.WriteJUnitReport(f, sc.Name, results)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		color.Outf("{{green}}JUnit report written to:{{/}} %s\n", junitReport)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil && !result.Skipped {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d steps failed", failed, len(results))
+	}
+	color.Outf("{{green}}all %d steps of scenario %q succeeded{{/}}\n", len(results), sc.Name)
+	return nil
+}
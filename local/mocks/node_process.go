@@ -9,6 +9,41 @@ type NodeProcess struct {
 	mock.Mock
 }
 
+// Exited provides a mock function with given fields:
+func (_m *NodeProcess) Exited() (bool, error) {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PID provides a mock function with given fields:
+func (_m *NodeProcess) PID() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields:
 func (_m *NodeProcess) Start() error {
 	ret := _m.Called()
@@ -22,6 +22,7 @@ import (
 	"github.com/axiacoin/axia-network-runner/network/node"
 	"github.com/axiacoin/axia-network-runner/utils"
 	"github.com/axiacoin/axia/config"
+	"github.com/axiacoin/axia/ids"
 	"github.com/axiacoin/axia/staking"
 	"github.com/axiacoin/axia/utils/beacon"
 	"github.com/axiacoin/axia/utils/constants"
@@ -36,6 +37,7 @@ import (
 const (
 	defaultNodeNamePrefix = "node"
 	configFileName        = "config.json"
+	upgradeFileName       = "upgrade.json"
 	stakingKeyFileName    = "staking.key"
 	stakingCertFileName   = "staking.crt"
 	genesisFileName       = "genesis.json"
@@ -46,6 +48,7 @@ const (
 	rootDirPrefix         = "axia-network-runner-"
 	defaultDbSubdir       = "db"
 	defaultLogsSubdir     = "logs"
+	defaultProfilesSubdir = "profiles"
 )
 
 // interface compliance
@@ -59,7 +62,7 @@ var (
 		config.BootstrapIDsKey: {},
 	}
 	chainConfigSubDir  = "chainConfigs"
-	cChainConfigSubDir = filepath.Join(chainConfigSubDir, "C")
+	subnetConfigSubDir = "subnetConfigs"
 
 	snapshotsRelPath = filepath.Join(".axia-network-runner", "snapshots")
 )
@@ -91,6 +94,10 @@ type localNetwork struct {
 	rootDir string
 	// Flags to apply to all nodes if not present
 	flags map[string]interface{}
+	// Chain config, upgrade and subnet config files to write for all nodes if not present
+	chainConfigFiles   map[string]string
+	upgradeConfigFiles map[string]string
+	subnetConfigFiles  map[string]string
 	// directory where networks can be persistently saved
 	snapshotsDir string
 }
@@ -185,24 +192,25 @@ func (npc *nodeProcessCreator) NewNodeProcess(config node.Config, args ...string
 	cmd := exec.Command(config.BinaryPath, args...)
 	// assign a new color to this process (might not be used if the config isn't set for it)
 	color := npc.colorPicker.NextColor()
-	// Optionally redirect stdout and stderr
+	// Optionally redirect stdout and stderr.
+	// The output is copied through pipes that are closed once the process
+	// has been reaped, so nothing it writes before exiting is lost.
+	var outputs []io.Closer
 	if config.RedirectStdout {
-		stdout, err := cmd.StdoutPipe()
-		if err != nil {
-			return nil, fmt.Errorf("couldn't create stdout pipe: %s", err)
-		}
+		stdoutReader, stdoutWriter := io.Pipe()
+		cmd.Stdout = stdoutWriter
+		outputs = append(outputs, stdoutWriter)
 		// redirect stdout and assign a color to the text
-		utils.ColorAndPrepend(stdout, npc.stdout, config.Name, color)
+		utils.ColorAndPrepend(stdoutReader, npc.stdout, config.Name, color)
 	}
 	if config.RedirectStderr {
-		stderr, err := cmd.StderrPipe()
-		if err != nil {
-			return nil, fmt.Errorf("couldn't create stderr pipe: %s", err)
-		}
+		stderrReader, stderrWriter := io.Pipe()
+		cmd.Stderr = stderrWriter
+		outputs = append(outputs, stderrWriter)
 		// redirect stderr and assign a color to the text
-		utils.ColorAndPrepend(stderr, npc.stderr, config.Name, color)
+		utils.ColorAndPrepend(stderrReader, npc.stderr, config.Name, color)
 	}
-	return &nodeProcessImpl{cmd: cmd}, nil
+	return newNodeProcessImpl(cmd, outputs...), nil
 }
 
 // NewNetwork returns a new network that uses the given log.
@@ -268,12 +276,14 @@ func newNetwork(
 	return net, nil
 }
 
-// NewNetwork returns a new network from the given snapshot
+// NewNetwork returns a new network from the given snapshot.
+// If [binaryPath] is not empty, it overrides the binary saved for each node.
 func NewNetworkFromSnapshot(
 	log logging.Logger,
 	snapshotName string,
 	rootDir string,
 	snapshotsDir string,
+	binaryPath string,
 ) (network.Network, error) {
 	net, err := newNetwork(
 		log,
@@ -289,7 +299,7 @@ func NewNetworkFromSnapshot(
 	if err != nil {
 		return net, err
 	}
-	err = net.loadSnapshot(context.Background(), snapshotName)
+	err = net.loadSnapshot(context.Background(), snapshotName, binaryPath)
 	return net, err
 }
 
@@ -378,6 +388,9 @@ func (ln *localNetwork) loadConfig(ctx context.Context, networkConfig network.Co
 	}
 
 	ln.flags = networkConfig.Flags
+	ln.chainConfigFiles = networkConfig.ChainConfigFiles
+	ln.upgradeConfigFiles = networkConfig.UpgradeConfigFiles
+	ln.subnetConfigFiles = networkConfig.SubnetConfigFiles
 
 	// Sort node configs so beacons start first
 	var nodeConfigs []node.Config
@@ -440,7 +453,7 @@ func (ln *localNetwork) addNode(nodeConfig node.Config) (node.Node, error) {
 		}
 	}
 
-	flags, apiPort, p2pPort, dbDir, logsDir, err := ln.buildFlags(configFile, nodeDir, &nodeConfig)
+	flags, apiPort, p2pPort, dbDir, logsDir, profilesDir, err := ln.buildFlags(configFile, nodeDir, &nodeConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -473,7 +486,9 @@ func (ln *localNetwork) addNode(nodeConfig node.Config) (node.Node, error) {
 		getConnFunc: defaultGetConnFunc,
 		dbDir:       dbDir,
 		logsDir:     logsDir,
+		profilesDir: profilesDir,
 		config:      nodeConfig,
+		args:        flags,
 	}
 	ln.nodes[node.name] = node
 	// If this node is a beacon, add its IP/ID to the beacon lists.
@@ -738,9 +753,12 @@ func (ln *localNetwork) SaveSnapshot(ctx context.Context, snapshotName string) (
 	}
 	// save network conf
 	networkConfig := network.Config{
-		Genesis:     string(ln.genesis),
-		Flags:       networkConfigFlags,
-		NodeConfigs: []node.Config{},
+		Genesis:            string(ln.genesis),
+		Flags:              networkConfigFlags,
+		NodeConfigs:        []node.Config{},
+		ChainConfigFiles:   ln.chainConfigFiles,
+		UpgradeConfigFiles: ln.upgradeConfigFiles,
+		SubnetConfigFiles:  ln.subnetConfigFiles,
 	}
 	for _, nodeConfig := range nodesConfig {
 		// no need to save this, will be generated automatically on snapshot load
@@ -758,39 +776,55 @@ func (ln *localNetwork) SaveSnapshot(ctx context.Context, snapshotName string) (
 }
 
 // start network from snapshot
-func (ln *localNetwork) loadSnapshot(ctx context.Context, snapshotName string) error {
+func (ln *localNetwork) loadSnapshot(ctx context.Context, snapshotName string, binaryPath string) error {
 	ln.lock.Lock()
 	defer ln.lock.Unlock()
-	snapshotDir := filepath.Join(ln.snapshotsDir, snapshotPrefix+snapshotName)
-	snapshotDbDir := filepath.Join(filepath.Join(snapshotDir, defaultDbSubdir))
+	networkConfig, err := GetSnapshotConfig(ln.snapshotsDir, snapshotName)
+	if err != nil {
+		return err
+	}
+	// load db
+	for i, nodeConfig := range networkConfig.NodeConfigs {
+		sourceDbDir := GetSnapshotDbDir(ln.snapshotsDir, snapshotName, nodeConfig.Name)
+		targetDbDir := filepath.Join(filepath.Join(ln.rootDir, nodeConfig.Name), defaultDbSubdir)
+		if err := dircopy.Copy(sourceDbDir, targetDbDir); err != nil {
+			return fmt.Errorf("failure loading node %q db dir: %w", nodeConfig.Name, err)
+		}
+		nodeConfig.Flags[config.DBPathKey] = targetDbDir
+		if binaryPath != "" {
+			networkConfig.NodeConfigs[i].BinaryPath = binaryPath
+		}
+	}
+	return ln.loadConfig(ctx, networkConfig)
+}
+
+// GetSnapshotConfig returns the network config saved in snapshot [snapshotName]
+func GetSnapshotConfig(snapshotsDir string, snapshotName string) (network.Config, error) {
+	snapshotDir := filepath.Join(snapshotsDir, snapshotPrefix+snapshotName)
 	_, err := os.Stat(snapshotDir)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("snapshot %q does not exists", snapshotName)
+			return network.Config{}, fmt.Errorf("snapshot %q does not exists", snapshotName)
 		} else {
-			return fmt.Errorf("failure accessing snapshot %q: %w", snapshotName, err)
+			return network.Config{}, fmt.Errorf("failure accessing snapshot %q: %w", snapshotName, err)
 		}
 	}
 	// load network config
 	networkConfigJSON, err := os.ReadFile(filepath.Join(snapshotDir, "network.json"))
 	if err != nil {
-		return fmt.Errorf("failure reading network config file from snapshot: %w", err)
+		return network.Config{}, fmt.Errorf("failure reading network config file from snapshot: %w", err)
 	}
 	networkConfig := network.Config{}
 	err = json.Unmarshal(networkConfigJSON, &networkConfig)
 	if err != nil {
-		return fmt.Errorf("failure unmarshaling network config from snapshot: %w", err)
+		return network.Config{}, fmt.Errorf("failure unmarshaling network config from snapshot: %w", err)
 	}
-	// load db
-	for _, nodeConfig := range networkConfig.NodeConfigs {
-		sourceDbDir := filepath.Join(snapshotDbDir, nodeConfig.Name)
-		targetDbDir := filepath.Join(filepath.Join(ln.rootDir, nodeConfig.Name), defaultDbSubdir)
-		if err := dircopy.Copy(sourceDbDir, targetDbDir); err != nil {
-			return fmt.Errorf("failure loading node %q db dir: %w", nodeConfig.Name, err)
-		}
-		nodeConfig.Flags[config.DBPathKey] = targetDbDir
-	}
-	return ln.loadConfig(ctx, networkConfig)
+	return networkConfig, nil
+}
+
+// GetSnapshotDbDir returns the db dir of node [nodeName] saved in snapshot [snapshotName]
+func GetSnapshotDbDir(snapshotsDir string, snapshotName string, nodeName string) string {
+	return filepath.Join(snapshotsDir, snapshotPrefix+snapshotName, defaultDbSubdir, nodeName)
 }
 
 // Remove network snapshot
@@ -974,6 +1008,9 @@ func getPort(
 // 1) Flags
 // 2) API port
 // 3) P2P port
+// 4) DB dir
+// 5) Logs dir
+// 6) Profiles dir
 // of the node being added with config [nodeConfig], config file [configFile],
 // and directory at [nodeDir].
 // [nodeConfig.Flags] must not be nil
@@ -981,34 +1018,44 @@ func (ln *localNetwork) buildFlags(
 	configFile map[string]interface{},
 	nodeDir string,
 	nodeConfig *node.Config,
-) ([]string, uint16, uint16, string, string, error) {
+) ([]string, uint16, uint16, string, string, string, error) {
 	// Add flags in [ln.Flags] to [nodeConfig.Flags]
 	// Assumes [nodeConfig.Flags] is non-nil
 	addNetworkFlags(ln.log, ln.flags, nodeConfig.Flags)
+	// Add the config files of the network to the ones of the node
+	nodeConfig.ChainConfigFiles = mergeConfigFiles(ln.chainConfigFiles, nodeConfig.ChainConfigFiles)
+	nodeConfig.UpgradeConfigFiles = mergeConfigFiles(ln.upgradeConfigFiles, nodeConfig.UpgradeConfigFiles)
+	nodeConfig.SubnetConfigFiles = mergeConfigFiles(ln.subnetConfigFiles, nodeConfig.SubnetConfigFiles)
 
 	// Tell the node to put the database in [nodeDir] unless given in config file
 	dbDir, err := getConfigEntry(nodeConfig.Flags, configFile, config.DBPathKey, filepath.Join(nodeDir, defaultDbSubdir))
 	if err != nil {
-		return nil, 0, 0, "", "", err
+		return nil, 0, 0, "", "", "", err
 	}
 
 	// Tell the node to put the log directory in [nodeDir/logs] unless given in config file
 	logsDir, err := getConfigEntry(nodeConfig.Flags, configFile, config.LogsDirKey, filepath.Join(nodeDir, defaultLogsSubdir))
 	if err != nil {
-		return nil, 0, 0, "", "", err
+		return nil, 0, 0, "", "", "", err
+	}
+
+	// Tell the node to write its profiles in [nodeDir/profiles] unless given in config file
+	profilesDir, err := getConfigEntry(nodeConfig.Flags, configFile, config.ProfileDirKey, filepath.Join(nodeDir, defaultProfilesSubdir))
+	if err != nil {
+		return nil, 0, 0, "", "", "", err
 	}
 
 	// Use random free API port unless given in config file
 	apiPort, err := getPort(nodeConfig.Flags, configFile, config.HTTPPortKey)
 	if err != nil {
-		return nil, 0, 0, "", "", err
+		return nil, 0, 0, "", "", "", err
 	}
 
 	// Use a random free P2P (staking) port unless given in config file
 	// Use random free API port unless given in config file
 	p2pPort, err := getPort(nodeConfig.Flags, configFile, config.StakingPortKey)
 	if err != nil {
-		return nil, 0, 0, "", "", err
+		return nil, 0, 0, "", "", "", err
 	}
 
 	// Flags for Axia
@@ -1016,6 +1063,7 @@ func (ln *localNetwork) buildFlags(
 		fmt.Sprintf("--%s=%d", config.NetworkNameKey, ln.networkID),
 		fmt.Sprintf("--%s=%s", config.DBPathKey, dbDir),
 		fmt.Sprintf("--%s=%s", config.LogsDirKey, logsDir),
+		fmt.Sprintf("--%s=%s", config.ProfileDirKey, profilesDir),
 		fmt.Sprintf("--%s=%d", config.HTTPPortKey, apiPort),
 		fmt.Sprintf("--%s=%d", config.StakingPortKey, p2pPort),
 		fmt.Sprintf("--%s=%s", config.BootstrapIPsKey, ln.bootstraps.IPsArg()),
@@ -1025,7 +1073,7 @@ func (ln *localNetwork) buildFlags(
 	// and get flag that point the node to those files
 	fileFlags, err := writeFiles(ln.genesis, nodeDir, nodeConfig)
 	if err != nil {
-		return nil, 0, 0, "", "", err
+		return nil, 0, 0, "", "", "", err
 	}
 	flags = append(flags, fileFlags...)
 
@@ -1042,7 +1090,24 @@ func (ln *localNetwork) buildFlags(
 		"adding node %q with tmp dir at %s, logs at %s, DB at %s, P2P port %d, API port %d",
 		nodeConfig.Name, nodeDir, logsDir, dbDir, p2pPort, apiPort,
 	)
-	return flags, apiPort, p2pPort, dbDir, logsDir, nil
+	return flags, apiPort, p2pPort, dbDir, logsDir, profilesDir, nil
+}
+
+// mergeConfigFiles returns the union of [networkFiles] and [nodeFiles],
+// the file in [nodeFiles] taking precedence if a key is in both.
+// Returns nil if both are empty.
+func mergeConfigFiles(networkFiles map[string]string, nodeFiles map[string]string) map[string]string {
+	if len(networkFiles) == 0 && len(nodeFiles) == 0 {
+		return nil
+	}
+	files := make(map[string]string, len(networkFiles)+len(nodeFiles))
+	for k, contents := range networkFiles {
+		files[k] = contents
+	}
+	for k, contents := range nodeFiles {
+		files[k] = contents
+	}
+	return files
 }
 
 // writeFiles writes the files a node needs on startup.
@@ -1082,17 +1147,49 @@ func writeFiles(genesis []byte, nodeRootDir string, nodeConfig *node.Config) ([]
 			contents:  []byte(nodeConfig.ConfigFile),
 		})
 	}
-	if len(nodeConfig.CChainConfigFile) != 0 {
+	chainConfigFiles := nodeConfig.ChainConfigFiles
+	if _, ok := chainConfigFiles["C"]; !ok && len(nodeConfig.CChainConfigFile) != 0 {
+		chainConfigFiles = mergeConfigFiles(map[string]string{"C": nodeConfig.CChainConfigFile}, chainConfigFiles)
+	}
+	for _, chainFiles := range []struct {
+		files    map[string]string
+		fileName string
+	}{
+		{files: chainConfigFiles, fileName: configFileName},
+		{files: nodeConfig.UpgradeConfigFiles, fileName: upgradeFileName},
+	} {
+		for chain, contents := range chainFiles.files {
+			if chain == "" || filepath.Base(chain) != chain {
+				return nil, fmt.Errorf("invalid chain %q for chain config files", chain)
+			}
+			files = append(files, file{
+				flagValue: filepath.Join(nodeRootDir, chainConfigSubDir),
+				path:      filepath.Join(nodeRootDir, chainConfigSubDir, chain, chainFiles.fileName),
+				pathKey:   config.ChainConfigDirKey,
+				contents:  []byte(contents),
+			})
+		}
+	}
+	for subnet, contents := range nodeConfig.SubnetConfigFiles {
+		if _, err := ids.FromString(subnet); err != nil {
+			return nil, fmt.Errorf("invalid subnet ID %q for subnet config files: %w", subnet, err)
+		}
 		files = append(files, file{
-			flagValue: filepath.Join(nodeRootDir, chainConfigSubDir),
-			path:      filepath.Join(nodeRootDir, cChainConfigSubDir, configFileName),
-			pathKey:   config.ChainConfigDirKey,
-			contents:  []byte(nodeConfig.CChainConfigFile),
+			flagValue: filepath.Join(nodeRootDir, subnetConfigSubDir),
+			path:      filepath.Join(nodeRootDir, subnetConfigSubDir, subnet+".json"),
+			pathKey:   config.SubnetConfigDirKey,
+			contents:  []byte(contents),
 		})
 	}
 	flags := []string{}
+	// the config dirs are shared by several files
+	addedFlags := map[string]struct{}{}
 	for _, f := range files {
-		flags = append(flags, fmt.Sprintf("--%s=%s", f.pathKey, f.flagValue))
+		flag := fmt.Sprintf("--%s=%s", f.pathKey, f.flagValue)
+		if _, ok := addedFlags[flag]; !ok {
+			addedFlags[flag] = struct{}{}
+			flags = append(flags, flag)
+		}
 		if err := createFileAndWrite(f.path, f.contents); err != nil {
 			return nil, fmt.Errorf("couldn't write file at %q: %w", f.path, err)
 		}
@@ -1229,6 +1229,72 @@ func TestWriteFiles(t *testing.T) {
 	}
 }
 
+func TestWriteChainConfigFiles(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+	tmpDir := t.TempDir()
+	blockchainID := ids.GenerateTestID().String()
+	subnetID := ids.GenerateTestID().String()
+	nodeConfig := node.Config{
+		StakingKey:       "stakingKey",
+		StakingCert:      "stakingCert",
+		CChainConfigFile: "axchain config file",
+		ChainConfigFiles: map[string]string{
+			"X":          "swapchain config file",
+			blockchainID: "blockchain config file",
+		},
+		UpgradeConfigFiles: map[string]string{blockchainID: "blockchain upgrade file"},
+		SubnetConfigFiles:  map[string]string{subnetID: "subnet config file"},
+	}
+	flags, err := writeFiles([]byte("genesis"), tmpDir, &nodeConfig)
+	assert.NoError(err)
+	assert.ElementsMatch([]string{
+		fmt.Sprintf("--%s=%v", config.StakingKeyPathKey, filepath.Join(tmpDir, stakingKeyFileName)),
+		fmt.Sprintf("--%s=%v", config.StakingCertPathKey, filepath.Join(tmpDir, stakingCertFileName)),
+		fmt.Sprintf("--%s=%v", config.GenesisConfigFileKey, filepath.Join(tmpDir, genesisFileName)),
+		fmt.Sprintf("--%s=%v", config.ChainConfigDirKey, filepath.Join(tmpDir, chainConfigSubDir)),
+		fmt.Sprintf("--%s=%v", config.SubnetConfigDirKey, filepath.Join(tmpDir, subnetConfigSubDir)),
+	}, flags)
+	for path, contents := range map[string]string{
+		filepath.Join(tmpDir, chainConfigSubDir, "C", configFileName):           "axchain config file",
+		filepath.Join(tmpDir, chainConfigSubDir, "X", configFileName):           "swapchain config file",
+		filepath.Join(tmpDir, chainConfigSubDir, blockchainID, configFileName):  "blockchain config file",
+		filepath.Join(tmpDir, chainConfigSubDir, blockchainID, upgradeFileName): "blockchain upgrade file",
+		filepath.Join(tmpDir, subnetConfigSubDir, subnetID+".json"):             "subnet config file",
+	} {
+		got, err := os.ReadFile(path)
+		assert.NoError(err)
+		assert.Equal(contents, string(got))
+	}
+
+	// the C-Chain config in the chain config files takes precedence
+	nodeConfig.ChainConfigFiles["C"] = "other axchain config file"
+	_, err = writeFiles([]byte("genesis"), tmpDir, &nodeConfig)
+	assert.NoError(err)
+	got, err := os.ReadFile(filepath.Join(tmpDir, chainConfigSubDir, "C", configFileName))
+	assert.NoError(err)
+	assert.Equal("other axchain config file", string(got))
+
+	_, err = writeFiles([]byte("genesis"), tmpDir, &node.Config{ChainConfigFiles: map[string]string{"../C": "{}"}})
+	assert.Error(err)
+	_, err = writeFiles([]byte("genesis"), tmpDir, &node.Config{SubnetConfigFiles: map[string]string{"subnet": "{}"}})
+	assert.Error(err)
+}
+
+func TestMergeConfigFiles(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	assert.Nil(mergeConfigFiles(nil, map[string]string{}))
+	networkFiles := map[string]string{"C": "network C", "X": "network X"}
+	assert.Equal(
+		map[string]string{"C": "node C", "X": "network X", "P": "node P"},
+		mergeConfigFiles(networkFiles, map[string]string{"C": "node C", "P": "node P"}),
+	)
+	// the network files are not modified
+	assert.Equal(map[string]string{"C": "network C", "X": "network X"}, networkFiles)
+}
+
 func TestRemoveBeacon(t *testing.T) {
 	t.Parallel()
 	assert := assert.New(t)
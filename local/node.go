@@ -3,8 +3,11 @@ package local
 import (
 	"context"
 	"crypto"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"os"
 	"os/exec"
 	"syscall"
 	"time"
@@ -46,6 +49,11 @@ type NodeProcess interface {
 	Stop() error
 	// Returns when the process finishes exiting
 	Wait() error
+	// Returns true if the process has exited, along with
+	// the error it exited with (nil if it exited cleanly)
+	Exited() (bool, error)
+	// Returns the process ID, or 0 if the process wasn't started
+	PID() int
 }
 
 const (
@@ -55,18 +63,71 @@ const (
 
 type nodeProcessImpl struct {
 	cmd *exec.Cmd
+	// Closed when the process has been reaped
+	exitedCh chan struct{}
+	// The error the process exited with. Set before [exitedCh] is closed.
+	exitErr error
+	// Closed once the process has been reaped, so that
+	// readers of its redirected output get an EOF
+	outputs []io.Closer
 }
 
+func newNodeProcessImpl(cmd *exec.Cmd, outputs ...io.Closer) *nodeProcessImpl {
+	return &nodeProcessImpl{
+		cmd:      cmd,
+		exitedCh: make(chan struct{}),
+		outputs:  outputs,
+	}
+}
+
+// Starts the process and reaps it in the background as soon as it
+// exits, so that nodes which crash on their own report an exit status
 func (p *nodeProcessImpl) Start() error {
-	return p.cmd.Start()
+	if err := p.cmd.Start(); err != nil {
+		p.exitErr = err
+		close(p.exitedCh)
+		return err
+	}
+	go func() {
+		p.exitErr = p.cmd.Wait()
+		for _, output := range p.outputs {
+			_ = output.Close()
+		}
+		close(p.exitedCh)
+	}()
+	return nil
 }
 
 func (p *nodeProcessImpl) Wait() error {
-	return p.cmd.Wait()
+	<-p.exitedCh
+	return p.exitErr
 }
 
 func (p *nodeProcessImpl) Stop() error {
-	return p.cmd.Process.Signal(syscall.SIGTERM)
+	if exited, _ := p.Exited(); exited {
+		return nil
+	}
+	err := p.cmd.Process.Signal(syscall.SIGTERM)
+	if errors.Is(err, os.ErrProcessDone) {
+		return nil
+	}
+	return err
+}
+
+func (p *nodeProcessImpl) PID() int {
+	if p.cmd.Process == nil {
+		return 0
+	}
+	return p.cmd.Process.Pid
+}
+
+func (p *nodeProcessImpl) Exited() (bool, error) {
+	select {
+	case <-p.exitedCh:
+		return true, p.exitErr
+	default:
+		return false, nil
+	}
 }
 
 // Gives access to basic node info, and to most axia apis
@@ -92,8 +153,12 @@ type localNode struct {
 	dbDir string
 	// The logs dir of the node
 	logsDir string
+	// The dir the node writes its profiles to
+	profilesDir string
 	// The node config
 	config node.Config
+	// The arguments the node's binary was started with
+	args []string
 }
 
 func defaultGetConnFunc(ctx context.Context, node node.Node) (net.Conn, error) {
@@ -237,7 +302,27 @@ func (node *localNode) GetLogsDir() string {
 	return node.logsDir
 }
 
+// See node.Node
+func (node *localNode) GetProfilesDir() string {
+	return node.profilesDir
+}
+
 // See node.Node
 func (node *localNode) GetConfigFile() string {
 	return node.config.ConfigFile
 }
+
+// See node.Node
+func (node *localNode) GetCommandLine() []string {
+	return append([]string{node.config.BinaryPath}, node.args...)
+}
+
+// See node.Node
+func (node *localNode) GetPID() int {
+	return node.process.PID()
+}
+
+// See node.Node
+func (node *localNode) GetExitStatus() (bool, error) {
+	return node.process.Exited()
+}
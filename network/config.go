@@ -55,6 +55,12 @@ type Config struct {
 	// and the node's config file has flag W set to Z,
 	// then the node will be started with flag W set to Y.
 	Flags map[string]interface{} `json:"flags"`
+	// Chain config, upgrade and subnet config files that will be written for each node
+	// in this network, as in node.Config. They can be empty.
+	// The files defined in a node's node.Config take precedence.
+	ChainConfigFiles   map[string]string `json:"chainConfigFiles"`
+	UpgradeConfigFiles map[string]string `json:"upgradeConfigFiles"`
+	SubnetConfigFiles  map[string]string `json:"subnetConfigFiles"`
 }
 
 // Validate returns an error if this config is invalid
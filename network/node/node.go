@@ -40,8 +40,18 @@ type Node interface {
 	GetDbDir() string
 	// Return this node's logs dir
 	GetLogsDir() string
+	// Return the dir this node writes its profiles to
+	GetProfilesDir() string
 	// Return this node's config file contents
 	GetConfigFile() string
+	// Return the command line (binary path followed by
+	// its arguments) this node's process was started with
+	GetCommandLine() []string
+	// Return true if this node's process has exited, along with
+	// the error it exited with (nil if it exited cleanly)
+	GetExitStatus() (bool, error)
+	// Return the ID of this node's process
+	GetPID() int
 }
 
 // Config encapsulates an axia configuration
@@ -61,6 +71,15 @@ type Config struct {
 	ConfigFile string `json:"configFile"`
 	// May be nil.
 	CChainConfigFile string `json:"cChainConfigFile"`
+	// Map from chain alias (X, P, C) or blockchain ID to the contents of its config file.
+	// May be nil. For the C-Chain, takes precedence over CChainConfigFile.
+	ChainConfigFiles map[string]string `json:"chainConfigFiles"`
+	// Map from chain alias or blockchain ID to the contents of its upgrade file.
+	// May be nil.
+	UpgradeConfigFiles map[string]string `json:"upgradeConfigFiles"`
+	// Map from subnet ID to the contents of its config file.
+	// May be nil.
+	SubnetConfigFiles map[string]string `json:"subnetConfigFiles"`
 	// Flags can hold additional flags for the node.
 	// It can be empty.
 	// The precedence of flags handling is:
@@ -123,6 +123,34 @@ type ControlServiceClient interface {
 	LoadSnapshot(ctx context.Context, in *LoadSnapshotRequest, opts ...grpc.CallOption) (*LoadSnapshotResponse, error)
 	RemoveSnapshot(ctx context.Context, in *RemoveSnapshotRequest, opts ...grpc.CallOption) (*RemoveSnapshotResponse, error)
 	GetSnapshotNames(ctx context.Context, in *GetSnapshotNamesRequest, opts ...grpc.CallOption) (*GetSnapshotNamesResponse, error)
+	CollectArtifacts(ctx context.Context, in *CollectArtifactsRequest, opts ...grpc.CallOption) (ControlService_CollectArtifactsClient, error)
+	CollectProfiles(ctx context.Context, in *CollectProfilesRequest, opts ...grpc.CallOption) (*CollectProfilesResponse, error)
+	SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error)
+	Apply(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error)
+	EnableChaos(ctx context.Context, in *EnableChaosRequest, opts ...grpc.CallOption) (*EnableChaosResponse, error)
+	DisableChaos(ctx context.Context, in *DisableChaosRequest, opts ...grpc.CallOption) (*DisableChaosResponse, error)
+	UpgradeNetwork(ctx context.Context, in *UpgradeNetworkRequest, opts ...grpc.CallOption) (*UpgradeNetworkResponse, error)
+	RegisterBinary(ctx context.Context, in *RegisterBinaryRequest, opts ...grpc.CallOption) (*RegisterBinaryResponse, error)
+	ListBinaries(ctx context.Context, in *ListBinariesRequest, opts ...grpc.CallOption) (*ListBinariesResponse, error)
+	CreateSubnet(ctx context.Context, in *CreateSubnetRequest, opts ...grpc.CallOption) (*CreateSubnetResponse, error)
+	AddSubnetValidators(ctx context.Context, in *AddSubnetValidatorsRequest, opts ...grpc.CallOption) (*AddSubnetValidatorsResponse, error)
+	CreateBlockchain(ctx context.Context, in *CreateBlockchainRequest, opts ...grpc.CallOption) (*CreateBlockchainResponse, error)
+	InstallCustomVMs(ctx context.Context, in *InstallCustomVMsRequest, opts ...grpc.CallOption) (*InstallCustomVMsResponse, error)
+	Fund(ctx context.Context, in *FundRequest, opts ...grpc.CallOption) (*FundResponse, error)
+	CreateKey(ctx context.Context, in *CreateKeyRequest, opts ...grpc.CallOption) (*CreateKeyResponse, error)
+	ImportKey(ctx context.Context, in *ImportKeyRequest, opts ...grpc.CallOption) (*ImportKeyResponse, error)
+	ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error)
+	AddValidator(ctx context.Context, in *AddValidatorRequest, opts ...grpc.CallOption) (*AddValidatorResponse, error)
+	AddDelegator(ctx context.Context, in *AddDelegatorRequest, opts ...grpc.CallOption) (*AddDelegatorResponse, error)
+	CrossChainTransfer(ctx context.Context, in *CrossChainTransferRequest, opts ...grpc.CallOption) (*CrossChainTransferResponse, error)
+	CreateAsset(ctx context.Context, in *CreateAssetRequest, opts ...grpc.CallOption) (*CreateAssetResponse, error)
+	MintAsset(ctx context.Context, in *MintAssetRequest, opts ...grpc.CallOption) (*MintAssetResponse, error)
+	TransferAsset(ctx context.Context, in *TransferAssetRequest, opts ...grpc.CallOption) (*TransferAssetResponse, error)
+	StartLoad(ctx context.Context, in *StartLoadRequest, opts ...grpc.CallOption) (*StartLoadResponse, error)
+	StopLoad(ctx context.Context, in *StopLoadRequest, opts ...grpc.CallOption) (*StopLoadResponse, error)
+	LoadStatus(ctx context.Context, in *LoadStatusRequest, opts ...grpc.CallOption) (*LoadStatusResponse, error)
+	CheckConsistency(ctx context.Context, in *CheckConsistencyRequest, opts ...grpc.CallOption) (*CheckConsistencyResponse, error)
+	StreamBlocks(ctx context.Context, in *StreamBlocksRequest, opts ...grpc.CallOption) (ControlService_StreamBlocksClient, error)
 }
 
 type controlServiceClient struct {
@@ -291,6 +319,304 @@ func (c *controlServiceClient) GetSnapshotNames(ctx context.Context, in *GetSnap
 	return out, nil
 }
 
+func (c *controlServiceClient) CollectArtifacts(ctx context.Context, in *CollectArtifactsRequest, opts ...grpc.CallOption) (ControlService_CollectArtifactsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ControlService_ServiceDesc.Streams[1], "/rpcpb.ControlService/CollectArtifacts", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlServiceCollectArtifactsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ControlService_CollectArtifactsClient interface {
+	Recv() (*CollectArtifactsResponse, error)
+	grpc.ClientStream
+}
+
+type controlServiceCollectArtifactsClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlServiceCollectArtifactsClient) Recv() (*CollectArtifactsResponse, error) {
+	m := new(CollectArtifactsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlServiceClient) CollectProfiles(ctx context.Context, in *CollectProfilesRequest, opts ...grpc.CallOption) (*CollectProfilesResponse, error) {
+	out := new(CollectProfilesResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/CollectProfiles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error) {
+	out := new(SetLogLevelResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/SetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) Apply(ctx context.Context, in *ApplyRequest, opts ...grpc.CallOption) (*ApplyResponse, error) {
+	out := new(ApplyResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/Apply", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) EnableChaos(ctx context.Context, in *EnableChaosRequest, opts ...grpc.CallOption) (*EnableChaosResponse, error) {
+	out := new(EnableChaosResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/EnableChaos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) DisableChaos(ctx context.Context, in *DisableChaosRequest, opts ...grpc.CallOption) (*DisableChaosResponse, error) {
+	out := new(DisableChaosResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/DisableChaos", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) UpgradeNetwork(ctx context.Context, in *UpgradeNetworkRequest, opts ...grpc.CallOption) (*UpgradeNetworkResponse, error) {
+	out := new(UpgradeNetworkResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/UpgradeNetwork", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) RegisterBinary(ctx context.Context, in *RegisterBinaryRequest, opts ...grpc.CallOption) (*RegisterBinaryResponse, error) {
+	out := new(RegisterBinaryResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/RegisterBinary", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ListBinaries(ctx context.Context, in *ListBinariesRequest, opts ...grpc.CallOption) (*ListBinariesResponse, error) {
+	out := new(ListBinariesResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/ListBinaries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) CreateSubnet(ctx context.Context, in *CreateSubnetRequest, opts ...grpc.CallOption) (*CreateSubnetResponse, error) {
+	out := new(CreateSubnetResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/CreateSubnet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) AddSubnetValidators(ctx context.Context, in *AddSubnetValidatorsRequest, opts ...grpc.CallOption) (*AddSubnetValidatorsResponse, error) {
+	out := new(AddSubnetValidatorsResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/AddSubnetValidators", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) CreateBlockchain(ctx context.Context, in *CreateBlockchainRequest, opts ...grpc.CallOption) (*CreateBlockchainResponse, error) {
+	out := new(CreateBlockchainResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/CreateBlockchain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) InstallCustomVMs(ctx context.Context, in *InstallCustomVMsRequest, opts ...grpc.CallOption) (*InstallCustomVMsResponse, error) {
+	out := new(InstallCustomVMsResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/InstallCustomVMs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) Fund(ctx context.Context, in *FundRequest, opts ...grpc.CallOption) (*FundResponse, error) {
+	out := new(FundResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/Fund", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) CreateKey(ctx context.Context, in *CreateKeyRequest, opts ...grpc.CallOption) (*CreateKeyResponse, error) {
+	out := new(CreateKeyResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/CreateKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ImportKey(ctx context.Context, in *ImportKeyRequest, opts ...grpc.CallOption) (*ImportKeyResponse, error) {
+	out := new(ImportKeyResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/ImportKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error) {
+	out := new(ListKeysResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/ListKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) AddValidator(ctx context.Context, in *AddValidatorRequest, opts ...grpc.CallOption) (*AddValidatorResponse, error) {
+	out := new(AddValidatorResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/AddValidator", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) AddDelegator(ctx context.Context, in *AddDelegatorRequest, opts ...grpc.CallOption) (*AddDelegatorResponse, error) {
+	out := new(AddDelegatorResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/AddDelegator", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) CrossChainTransfer(ctx context.Context, in *CrossChainTransferRequest, opts ...grpc.CallOption) (*CrossChainTransferResponse, error) {
+	out := new(CrossChainTransferResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/CrossChainTransfer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) CreateAsset(ctx context.Context, in *CreateAssetRequest, opts ...grpc.CallOption) (*CreateAssetResponse, error) {
+	out := new(CreateAssetResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/CreateAsset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) MintAsset(ctx context.Context, in *MintAssetRequest, opts ...grpc.CallOption) (*MintAssetResponse, error) {
+	out := new(MintAssetResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/MintAsset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) TransferAsset(ctx context.Context, in *TransferAssetRequest, opts ...grpc.CallOption) (*TransferAssetResponse, error) {
+	out := new(TransferAssetResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/TransferAsset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) StartLoad(ctx context.Context, in *StartLoadRequest, opts ...grpc.CallOption) (*StartLoadResponse, error) {
+	out := new(StartLoadResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/StartLoad", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) StopLoad(ctx context.Context, in *StopLoadRequest, opts ...grpc.CallOption) (*StopLoadResponse, error) {
+	out := new(StopLoadResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/StopLoad", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) LoadStatus(ctx context.Context, in *LoadStatusRequest, opts ...grpc.CallOption) (*LoadStatusResponse, error) {
+	out := new(LoadStatusResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/LoadStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) CheckConsistency(ctx context.Context, in *CheckConsistencyRequest, opts ...grpc.CallOption) (*CheckConsistencyResponse, error) {
+	out := new(CheckConsistencyResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ControlService/CheckConsistency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlServiceClient) StreamBlocks(ctx context.Context, in *StreamBlocksRequest, opts ...grpc.CallOption) (ControlService_StreamBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ControlService_ServiceDesc.Streams[2], "/rpcpb.ControlService/StreamBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlServiceStreamBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ControlService_StreamBlocksClient interface {
+	Recv() (*StreamBlocksResponse, error)
+	grpc.ClientStream
+}
+
+type controlServiceStreamBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlServiceStreamBlocksClient) Recv() (*StreamBlocksResponse, error) {
+	m := new(StreamBlocksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ControlServiceServer is the server API for ControlService service.
 // All implementations must embed UnimplementedControlServiceServer
 // for forward compatibility
@@ -310,6 +636,34 @@ type ControlServiceServer interface {
 	LoadSnapshot(context.Context, *LoadSnapshotRequest) (*LoadSnapshotResponse, error)
 	RemoveSnapshot(context.Context, *RemoveSnapshotRequest) (*RemoveSnapshotResponse, error)
 	GetSnapshotNames(context.Context, *GetSnapshotNamesRequest) (*GetSnapshotNamesResponse, error)
+	CollectArtifacts(*CollectArtifactsRequest, ControlService_CollectArtifactsServer) error
+	CollectProfiles(context.Context, *CollectProfilesRequest) (*CollectProfilesResponse, error)
+	SetLogLevel(context.Context, *SetLogLevelRequest) (*SetLogLevelResponse, error)
+	Apply(context.Context, *ApplyRequest) (*ApplyResponse, error)
+	EnableChaos(context.Context, *EnableChaosRequest) (*EnableChaosResponse, error)
+	DisableChaos(context.Context, *DisableChaosRequest) (*DisableChaosResponse, error)
+	UpgradeNetwork(context.Context, *UpgradeNetworkRequest) (*UpgradeNetworkResponse, error)
+	RegisterBinary(context.Context, *RegisterBinaryRequest) (*RegisterBinaryResponse, error)
+	ListBinaries(context.Context, *ListBinariesRequest) (*ListBinariesResponse, error)
+	CreateSubnet(context.Context, *CreateSubnetRequest) (*CreateSubnetResponse, error)
+	AddSubnetValidators(context.Context, *AddSubnetValidatorsRequest) (*AddSubnetValidatorsResponse, error)
+	CreateBlockchain(context.Context, *CreateBlockchainRequest) (*CreateBlockchainResponse, error)
+	InstallCustomVMs(context.Context, *InstallCustomVMsRequest) (*InstallCustomVMsResponse, error)
+	Fund(context.Context, *FundRequest) (*FundResponse, error)
+	CreateKey(context.Context, *CreateKeyRequest) (*CreateKeyResponse, error)
+	ImportKey(context.Context, *ImportKeyRequest) (*ImportKeyResponse, error)
+	ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error)
+	AddValidator(context.Context, *AddValidatorRequest) (*AddValidatorResponse, error)
+	AddDelegator(context.Context, *AddDelegatorRequest) (*AddDelegatorResponse, error)
+	CrossChainTransfer(context.Context, *CrossChainTransferRequest) (*CrossChainTransferResponse, error)
+	CreateAsset(context.Context, *CreateAssetRequest) (*CreateAssetResponse, error)
+	MintAsset(context.Context, *MintAssetRequest) (*MintAssetResponse, error)
+	TransferAsset(context.Context, *TransferAssetRequest) (*TransferAssetResponse, error)
+	StartLoad(context.Context, *StartLoadRequest) (*StartLoadResponse, error)
+	StopLoad(context.Context, *StopLoadRequest) (*StopLoadResponse, error)
+	LoadStatus(context.Context, *LoadStatusRequest) (*LoadStatusResponse, error)
+	CheckConsistency(context.Context, *CheckConsistencyRequest) (*CheckConsistencyResponse, error)
+	StreamBlocks(*StreamBlocksRequest, ControlService_StreamBlocksServer) error
 	mustEmbedUnimplementedControlServiceServer()
 }
 
@@ -362,6 +716,90 @@ func (UnimplementedControlServiceServer) RemoveSnapshot(context.Context, *Remove
 func (UnimplementedControlServiceServer) GetSnapshotNames(context.Context, *GetSnapshotNamesRequest) (*GetSnapshotNamesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSnapshotNames not implemented")
 }
+func (UnimplementedControlServiceServer) CollectArtifacts(*CollectArtifactsRequest, ControlService_CollectArtifactsServer) error {
+	return status.Errorf(codes.Unimplemented, "method CollectArtifacts not implemented")
+}
+func (UnimplementedControlServiceServer) CollectProfiles(context.Context, *CollectProfilesRequest) (*CollectProfilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CollectProfiles not implemented")
+}
+func (UnimplementedControlServiceServer) SetLogLevel(context.Context, *SetLogLevelRequest) (*SetLogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (UnimplementedControlServiceServer) Apply(context.Context, *ApplyRequest) (*ApplyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Apply not implemented")
+}
+func (UnimplementedControlServiceServer) EnableChaos(context.Context, *EnableChaosRequest) (*EnableChaosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableChaos not implemented")
+}
+func (UnimplementedControlServiceServer) DisableChaos(context.Context, *DisableChaosRequest) (*DisableChaosResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableChaos not implemented")
+}
+func (UnimplementedControlServiceServer) UpgradeNetwork(context.Context, *UpgradeNetworkRequest) (*UpgradeNetworkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpgradeNetwork not implemented")
+}
+func (UnimplementedControlServiceServer) RegisterBinary(context.Context, *RegisterBinaryRequest) (*RegisterBinaryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterBinary not implemented")
+}
+func (UnimplementedControlServiceServer) ListBinaries(context.Context, *ListBinariesRequest) (*ListBinariesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListBinaries not implemented")
+}
+func (UnimplementedControlServiceServer) CreateSubnet(context.Context, *CreateSubnetRequest) (*CreateSubnetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSubnet not implemented")
+}
+func (UnimplementedControlServiceServer) AddSubnetValidators(context.Context, *AddSubnetValidatorsRequest) (*AddSubnetValidatorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSubnetValidators not implemented")
+}
+func (UnimplementedControlServiceServer) CreateBlockchain(context.Context, *CreateBlockchainRequest) (*CreateBlockchainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateBlockchain not implemented")
+}
+func (UnimplementedControlServiceServer) InstallCustomVMs(context.Context, *InstallCustomVMsRequest) (*InstallCustomVMsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstallCustomVMs not implemented")
+}
+func (UnimplementedControlServiceServer) Fund(context.Context, *FundRequest) (*FundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Fund not implemented")
+}
+func (UnimplementedControlServiceServer) CreateKey(context.Context, *CreateKeyRequest) (*CreateKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateKey not implemented")
+}
+func (UnimplementedControlServiceServer) ImportKey(context.Context, *ImportKeyRequest) (*ImportKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportKey not implemented")
+}
+func (UnimplementedControlServiceServer) ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeys not implemented")
+}
+func (UnimplementedControlServiceServer) AddValidator(context.Context, *AddValidatorRequest) (*AddValidatorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddValidator not implemented")
+}
+func (UnimplementedControlServiceServer) AddDelegator(context.Context, *AddDelegatorRequest) (*AddDelegatorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddDelegator not implemented")
+}
+func (UnimplementedControlServiceServer) CrossChainTransfer(context.Context, *CrossChainTransferRequest) (*CrossChainTransferResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CrossChainTransfer not implemented")
+}
+func (UnimplementedControlServiceServer) CreateAsset(context.Context, *CreateAssetRequest) (*CreateAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAsset not implemented")
+}
+func (UnimplementedControlServiceServer) MintAsset(context.Context, *MintAssetRequest) (*MintAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MintAsset not implemented")
+}
+func (UnimplementedControlServiceServer) TransferAsset(context.Context, *TransferAssetRequest) (*TransferAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferAsset not implemented")
+}
+func (UnimplementedControlServiceServer) StartLoad(context.Context, *StartLoadRequest) (*StartLoadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartLoad not implemented")
+}
+func (UnimplementedControlServiceServer) StopLoad(context.Context, *StopLoadRequest) (*StopLoadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopLoad not implemented")
+}
+func (UnimplementedControlServiceServer) LoadStatus(context.Context, *LoadStatusRequest) (*LoadStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LoadStatus not implemented")
+}
+func (UnimplementedControlServiceServer) CheckConsistency(context.Context, *CheckConsistencyRequest) (*CheckConsistencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckConsistency not implemented")
+}
+func (UnimplementedControlServiceServer) StreamBlocks(*StreamBlocksRequest, ControlService_StreamBlocksServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBlocks not implemented")
+}
 func (UnimplementedControlServiceServer) mustEmbedUnimplementedControlServiceServer() {}
 
 // UnsafeControlServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -648,30 +1086,540 @@ func _ControlService_GetSnapshotNames_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
-// ControlService_ServiceDesc is the grpc.ServiceDesc for ControlService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var ControlService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "rpcpb.ControlService",
-	HandlerType: (*ControlServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Start",
-			Handler:    _ControlService_Start_Handler,
-		},
-		{
-			MethodName: "Health",
-			Handler:    _ControlService_Health_Handler,
-		},
-		{
-			MethodName: "URIs",
-			Handler:    _ControlService_URIs_Handler,
-		},
-		{
-			MethodName: "Status",
-			Handler:    _ControlService_Status_Handler,
-		},
-		{
+func _ControlService_CollectArtifacts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CollectArtifactsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServiceServer).CollectArtifacts(m, &controlServiceCollectArtifactsServer{stream})
+}
+
+type ControlService_CollectArtifactsServer interface {
+	Send(*CollectArtifactsResponse) error
+	grpc.ServerStream
+}
+
+type controlServiceCollectArtifactsServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlServiceCollectArtifactsServer) Send(m *CollectArtifactsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ControlService_CollectProfiles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectProfilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CollectProfiles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/CollectProfiles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CollectProfiles(ctx, req.(*CollectProfilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/SetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).SetLogLevel(ctx, req.(*SetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_Apply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).Apply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/Apply",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).Apply(ctx, req.(*ApplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_EnableChaos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableChaosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).EnableChaos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/EnableChaos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).EnableChaos(ctx, req.(*EnableChaosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_DisableChaos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableChaosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).DisableChaos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/DisableChaos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).DisableChaos(ctx, req.(*DisableChaosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_UpgradeNetwork_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpgradeNetworkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).UpgradeNetwork(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/UpgradeNetwork",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).UpgradeNetwork(ctx, req.(*UpgradeNetworkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_RegisterBinary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterBinaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).RegisterBinary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/RegisterBinary",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).RegisterBinary(ctx, req.(*RegisterBinaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ListBinaries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListBinariesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ListBinaries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/ListBinaries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ListBinaries(ctx, req.(*ListBinariesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateSubnet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSubnetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateSubnet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/CreateSubnet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateSubnet(ctx, req.(*CreateSubnetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_AddSubnetValidators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSubnetValidatorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).AddSubnetValidators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/AddSubnetValidators",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).AddSubnetValidators(ctx, req.(*AddSubnetValidatorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateBlockchain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateBlockchainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateBlockchain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/CreateBlockchain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateBlockchain(ctx, req.(*CreateBlockchainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_InstallCustomVMs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstallCustomVMsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).InstallCustomVMs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/InstallCustomVMs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).InstallCustomVMs(ctx, req.(*InstallCustomVMsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_Fund_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).Fund(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/Fund",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).Fund(ctx, req.(*FundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/CreateKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateKey(ctx, req.(*CreateKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ImportKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ImportKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/ImportKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ImportKey(ctx, req.(*ImportKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).ListKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/ListKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).ListKeys(ctx, req.(*ListKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_AddValidator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddValidatorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).AddValidator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/AddValidator",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).AddValidator(ctx, req.(*AddValidatorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_AddDelegator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddDelegatorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).AddDelegator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/AddDelegator",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).AddDelegator(ctx, req.(*AddDelegatorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CrossChainTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CrossChainTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CrossChainTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/CrossChainTransfer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CrossChainTransfer(ctx, req.(*CrossChainTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CreateAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CreateAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/CreateAsset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CreateAsset(ctx, req.(*CreateAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_MintAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MintAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).MintAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/MintAsset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).MintAsset(ctx, req.(*MintAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_TransferAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).TransferAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/TransferAsset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).TransferAsset(ctx, req.(*TransferAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_StartLoad_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartLoadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).StartLoad(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/StartLoad",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).StartLoad(ctx, req.(*StartLoadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_StopLoad_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopLoadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).StopLoad(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/StopLoad",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).StopLoad(ctx, req.(*StopLoadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_LoadStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoadStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).LoadStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/LoadStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).LoadStatus(ctx, req.(*LoadStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_CheckConsistency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckConsistencyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServiceServer).CheckConsistency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ControlService/CheckConsistency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServiceServer).CheckConsistency(ctx, req.(*CheckConsistencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ControlService_StreamBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServiceServer).StreamBlocks(m, &controlServiceStreamBlocksServer{stream})
+}
+
+type ControlService_StreamBlocksServer interface {
+	Send(*StreamBlocksResponse) error
+	grpc.ServerStream
+}
+
+type controlServiceStreamBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlServiceStreamBlocksServer) Send(m *StreamBlocksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ControlService_ServiceDesc is the grpc.ServiceDesc for ControlService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ControlService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rpcpb.ControlService",
+	HandlerType: (*ControlServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Start",
+			Handler:    _ControlService_Start_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _ControlService_Health_Handler,
+		},
+		{
+			MethodName: "URIs",
+			Handler:    _ControlService_URIs_Handler,
+		},
+		{
+			MethodName: "Status",
+			Handler:    _ControlService_Status_Handler,
+		},
+		{
 			MethodName: "RemoveNode",
 			Handler:    _ControlService_RemoveNode_Handler,
 		},
@@ -711,6 +1659,110 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSnapshotNames",
 			Handler:    _ControlService_GetSnapshotNames_Handler,
 		},
+		{
+			MethodName: "CollectProfiles",
+			Handler:    _ControlService_CollectProfiles_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _ControlService_SetLogLevel_Handler,
+		},
+		{
+			MethodName: "Apply",
+			Handler:    _ControlService_Apply_Handler,
+		},
+		{
+			MethodName: "EnableChaos",
+			Handler:    _ControlService_EnableChaos_Handler,
+		},
+		{
+			MethodName: "DisableChaos",
+			Handler:    _ControlService_DisableChaos_Handler,
+		},
+		{
+			MethodName: "UpgradeNetwork",
+			Handler:    _ControlService_UpgradeNetwork_Handler,
+		},
+		{
+			MethodName: "RegisterBinary",
+			Handler:    _ControlService_RegisterBinary_Handler,
+		},
+		{
+			MethodName: "ListBinaries",
+			Handler:    _ControlService_ListBinaries_Handler,
+		},
+		{
+			MethodName: "CreateSubnet",
+			Handler:    _ControlService_CreateSubnet_Handler,
+		},
+		{
+			MethodName: "AddSubnetValidators",
+			Handler:    _ControlService_AddSubnetValidators_Handler,
+		},
+		{
+			MethodName: "CreateBlockchain",
+			Handler:    _ControlService_CreateBlockchain_Handler,
+		},
+		{
+			MethodName: "InstallCustomVMs",
+			Handler:    _ControlService_InstallCustomVMs_Handler,
+		},
+		{
+			MethodName: "Fund",
+			Handler:    _ControlService_Fund_Handler,
+		},
+		{
+			MethodName: "CreateKey",
+			Handler:    _ControlService_CreateKey_Handler,
+		},
+		{
+			MethodName: "ImportKey",
+			Handler:    _ControlService_ImportKey_Handler,
+		},
+		{
+			MethodName: "ListKeys",
+			Handler:    _ControlService_ListKeys_Handler,
+		},
+		{
+			MethodName: "AddValidator",
+			Handler:    _ControlService_AddValidator_Handler,
+		},
+		{
+			MethodName: "AddDelegator",
+			Handler:    _ControlService_AddDelegator_Handler,
+		},
+		{
+			MethodName: "CrossChainTransfer",
+			Handler:    _ControlService_CrossChainTransfer_Handler,
+		},
+		{
+			MethodName: "CreateAsset",
+			Handler:    _ControlService_CreateAsset_Handler,
+		},
+		{
+			MethodName: "MintAsset",
+			Handler:    _ControlService_MintAsset_Handler,
+		},
+		{
+			MethodName: "TransferAsset",
+			Handler:    _ControlService_TransferAsset_Handler,
+		},
+		{
+			MethodName: "StartLoad",
+			Handler:    _ControlService_StartLoad_Handler,
+		},
+		{
+			MethodName: "StopLoad",
+			Handler:    _ControlService_StopLoad_Handler,
+		},
+		{
+			MethodName: "LoadStatus",
+			Handler:    _ControlService_LoadStatus_Handler,
+		},
+		{
+			MethodName: "CheckConsistency",
+			Handler:    _ControlService_CheckConsistency_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -718,6 +1770,16 @@ var ControlService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _ControlService_StreamStatus_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "CollectArtifacts",
+			Handler:       _ControlService_CollectArtifacts_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamBlocks",
+			Handler:       _ControlService_StreamBlocks_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "rpcpb/rpc.proto",
 }
@@ -0,0 +1,174 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/api"
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+	"github.com/axiacoin/coreth/core/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// AXChain ID of the default network genesis
+	defaultCChainID = 43112
+	// AXChain key pre-funded by the default network genesis
+	defaultCChainPrivateKey = "56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027"
+	// gas of a plain AXC transfer
+	transferGas = 21000
+	// interval between receipt polls while waiting for a tx to be accepted
+	receiptPollInterval = time.Second
+)
+
+// newEthClient returns an AXChain client to node [nodeName], or to the first node if empty
+func (r *Runner) newEthClient(ctx context.Context, nodeName string) (api.EthClient, error) {
+	resp, err := r.cli.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodeInfos := resp.ClusterInfo.NodeInfos
+	if nodeName == "" {
+		nodeNames := make([]string, 0, len(nodeInfos))
+		for name := range nodeInfos {
+			nodeNames = append(nodeNames, name)
+		}
+		if len(nodeNames) == 0 {
+			return nil, fmt.Errorf("%w: network has no nodes", ErrNodeNotFound)
+		}
+		sort.Strings(nodeNames)
+		nodeName = nodeNames[0]
+	}
+	nodeInfo, ok := nodeInfos[nodeName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNodeNotFound, nodeName)
+	}
+	uri, err := url.Parse(nodeInfo.Uri)
+	if err != nil {
+		return nil, err
+	}
+	port, err := strconv.ParseUint(uri.Port(), 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse port of node %q URI %q: %w", nodeName, nodeInfo.Uri, err)
+	}
+	return api.NewEthClient(uri.Hostname(), uint(port)), nil
+}
+
+// sendCChainTx transfers AXC on the AXChain, and waits for the tx to be accepted
+func (r *Runner) sendCChainTx(ctx context.Context, step Step) error {
+	privateKey := step.PrivateKey
+	if privateKey == "" {
+		privateKey = defaultCChainPrivateKey
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+	if err != nil {
+		return fmt.Errorf("%w: privateKey: %v", ErrInvalidParameter, err)
+	}
+	chainID := step.ChainID
+	if chainID == 0 {
+		chainID = defaultCChainID
+	}
+	amount, err := parseWei(step.Amount)
+	if err != nil {
+		return err
+	}
+
+	ethClient, err := r.newEthClient(ctx, step.NodeName)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	nonce, err := ethClient.NonceAt(ctx, from, nil)
+	if err != nil {
+		return err
+	}
+	gasPrice, err := ethClient.SuggestGasPrice(ctx)
+	if err != nil {
+		return err
+	}
+	tx := types.NewTransaction(nonce, common.HexToAddress(step.To), amount, transferGas, gasPrice, nil)
+	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(chainID)), key)
+	if err != nil {
+		return err
+	}
+	if err := ethClient.SendTransaction(ctx, signedTx); err != nil {
+		return err
+	}
+	color.Outf("{{cyan}}sent tx %s from %s to %s{{/}}\n", signedTx.Hash(), from, step.To)
+
+	for {
+		receipt, err := ethClient.TransactionReceipt(ctx, signedTx.Hash())
+		if err == nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return fmt.Errorf("tx %s failed", signedTx.Hash())
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("tx %s not accepted: %w (last error: %v)", signedTx.Hash(), ctx.Err(), err)
+		case <-time.After(receiptPollInterval):
+		}
+	}
+}
+
+func (r *Runner) assertBalance(ctx context.Context, step Step) error {
+	ethClient, err := r.newEthClient(ctx, step.NodeName)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	balance, err := ethClient.BalanceAt(ctx, common.HexToAddress(step.Address), nil)
+	if err != nil {
+		return err
+	}
+	if step.Balance != "" {
+		expected, err := parseWei(step.Balance)
+		if err != nil {
+			return err
+		}
+		if balance.Cmp(expected) != 0 {
+			return fmt.Errorf("balance of %s is %s, expected %s", step.Address, balance, expected)
+		}
+	}
+	if step.MinBalance != "" {
+		minBalance, err := parseWei(step.MinBalance)
+		if err != nil {
+			return err
+		}
+		if balance.Cmp(minBalance) < 0 {
+			return fmt.Errorf("balance of %s is %s, expected at least %s", step.Address, balance, minBalance)
+		}
+	}
+	return nil
+}
+
+func (r *Runner) assertBlockHeight(ctx context.Context, step Step) error {
+	ethClient, err := r.newEthClient(ctx, step.NodeName)
+	if err != nil {
+		return err
+	}
+	defer ethClient.Close()
+
+	height, err := ethClient.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if height < step.MinHeight {
+		return fmt.Errorf("block height is %d, expected at least %d", height, step.MinHeight)
+	}
+	return nil
+}
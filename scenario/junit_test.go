@@ -0,0 +1,41 @@
+package scenario
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteJUnitReport(t *testing.T) {
+	assert := assert.New(t)
+
+	results := []StepResult{
+		{Name: "1-start", Action: ActionStart, Duration: 1500 * time.Millisecond},
+		{Name: "2-remove-node", Action: ActionRemoveNode, Duration: time.Second, Err: errors.New("node not found")},
+		{Name: "3-sleep", Action: ActionSleep, Skipped: true, Err: ErrSkipped},
+	}
+	buf := &bytes.Buffer{}
+	assert.NoError(WriteJUnitReport(buf, "flow", results))
+	assert.Contains(buf.String(), xml.Header)
+
+	report := junitTestSuites{}
+	assert.NoError(xml.Unmarshal(buf.Bytes(), &report))
+	assert.Len(report.Suites, 1)
+	suite := report.Suites[0]
+	assert.Equal("flow", suite.Name)
+	assert.Equal(3, suite.Tests)
+	assert.Equal(1, suite.Failures)
+	assert.Equal(1, suite.Skipped)
+	assert.Equal("2.500", suite.Time)
+	assert.Len(suite.TestCases, 3)
+	assert.Nil(suite.TestCases[0].Failure)
+	assert.Nil(suite.TestCases[0].Skipped)
+	assert.Equal("1.500", suite.TestCases[0].Time)
+	assert.Equal("node not found", suite.TestCases[1].Failure.Message)
+	assert.Equal(string(ActionRemoveNode), suite.TestCases[1].Failure.Type)
+	assert.NotNil(suite.TestCases[2].Skipped)
+}
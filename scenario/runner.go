@@ -0,0 +1,211 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package scenario
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/client"
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+)
+
+// interval between cluster status polls while waiting for health
+const healthPollInterval = 5 * time.Second
+
+var (
+	ErrSkipped      = errors.New("skipped after a previous failure")
+	ErrNoPeer       = errors.New("no peer attached to node")
+	ErrNodeNotFound = errors.New("node not found")
+)
+
+// StepResult is the outcome of a step
+type StepResult struct {
+	Name     string
+	Action   Action
+	Duration time.Duration
+	// nil if the step succeeded
+	Err     error
+	Skipped bool
+}
+
+// Runner runs scenarios against the network runner server
+type Runner struct {
+	cli client.Client
+	// maps from node name to the ID of the last peer attached to it
+	attachedPeerIDs map[string]string
+}
+
+func NewRunner(cli client.Client) *Runner {
+	return &Runner{
+		cli:             cli,
+		attachedPeerIDs: make(map[string]string),
+	}
+}
+
+// Run runs the steps of [sc] in order, each within its timeout, and returns their results.
+// Once a step fails, the following steps are skipped, unless they are marked "always".
+func (r *Runner) Run(ctx context.Context, sc *Scenario) []StepResult {
+	results := make([]StepResult, 0, len(sc.Steps))
+	failed := false
+	for i, step := range sc.Steps {
+		result := StepResult{
+			Name:   sc.GetStepName(i),
+			Action: step.Action,
+		}
+		if failed && !step.Always {
+			color.Outf("{{yellow}}skipping step %q{{/}}\n", result.Name)
+			result.Skipped = true
+			result.Err = ErrSkipped
+			results = append(results, result)
+			continue
+		}
+
+		color.Outf("{{blue}}{{bold}}running step %q (%s){{/}}\n", result.Name, step.Action)
+		start := time.Now()
+		stepCtx, cancel := context.WithTimeout(ctx, sc.GetStepTimeout(i))
+		result.Err = r.runStep(stepCtx, step)
+		cancel()
+		result.Duration = time.Since(start)
+		if result.Err != nil {
+			color.Outf("{{red}}step %q failed after %v:{{/}} %v\n", result.Name, result.Duration, result.Err)
+			failed = true
+		} else {
+			color.Outf("{{green}}step %q succeeded after %v{{/}}\n", result.Name, result.Duration)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func (r *Runner) runStep(ctx context.Context, step Step) error {
+	switch step.Action {
+	case ActionStart:
+		r.resetAttachedPeers()
+		return r.start(ctx, step)
+	case ActionWaitHealthy:
+		return r.waitHealthy(ctx, step)
+	case ActionAddNode:
+		// the client takes the binary from the options, the network binary if empty
+		_, err := r.cli.AddNode(ctx, step.NodeName, step.ExecPath, client.WithExecPath(step.ExecPath))
+		return err
+	case ActionRemoveNode:
+		_, err := r.cli.RemoveNode(ctx, step.NodeName)
+		delete(r.attachedPeerIDs, step.NodeName)
+		return err
+	case ActionRestartNode:
+		opts := []client.OpOption{}
+		if step.ExecPath != "" {
+			opts = append(opts, client.WithExecPath(step.ExecPath))
+		}
+		_, err := r.cli.RestartNode(ctx, step.NodeName, opts...)
+		delete(r.attachedPeerIDs, step.NodeName)
+		return err
+	case ActionSaveSnapshot:
+		_, err := r.cli.SaveSnapshot(ctx, step.SnapshotName)
+		return err
+	case ActionLoadSnapshot:
+		// the peers were attached to the previous network
+		r.resetAttachedPeers()
+		_, err := r.cli.LoadSnapshot(ctx, step.SnapshotName)
+		return err
+	case ActionSendCChainTx:
+		return r.sendCChainTx(ctx, step)
+	case ActionAssertBalance:
+		return r.assertBalance(ctx, step)
+	case ActionAssertBlockHeight:
+		return r.assertBlockHeight(ctx, step)
+	case ActionSleep:
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(step.Duration):
+			return nil
+		}
+	case ActionAttachPeer:
+		resp, err := r.cli.AttachPeer(ctx, step.NodeName)
+		if err != nil {
+			return err
+		}
+		r.attachedPeerIDs[step.NodeName] = resp.AttachedPeerInfo.Id
+		return nil
+	case ActionSendMessage:
+		peerID := step.PeerID
+		if peerID == "" {
+			peerID = r.attachedPeerIDs[step.NodeName]
+		}
+		if peerID == "" {
+			return fmt.Errorf("%w %q", ErrNoPeer, step.NodeName)
+		}
+		resp, err := r.cli.SendOutboundMessage(ctx, step.NodeName, peerID, step.Op, []byte(step.Message))
+		if err != nil {
+			return err
+		}
+		if !resp.Sent {
+			return fmt.Errorf("message to node %q was not sent", step.NodeName)
+		}
+		return nil
+	case ActionStop:
+		r.resetAttachedPeers()
+		_, err := r.cli.Stop(ctx)
+		return err
+	default:
+		return fmt.Errorf("%w %q", ErrInvalidAction, step.Action)
+	}
+}
+
+// resetAttachedPeers forgets the peers attached to the nodes, once the network is stopped or replaced
+func (r *Runner) resetAttachedPeers() {
+	r.attachedPeerIDs = make(map[string]string)
+}
+
+func (r *Runner) start(ctx context.Context, step Step) error {
+	opts := []client.OpOption{}
+	if step.NumNodes > 0 {
+		opts = append(opts, client.WithNumNodes(step.NumNodes))
+	}
+	if step.WhitelistedSubnets != "" {
+		opts = append(opts, client.WithWhitelistedSubnets(step.WhitelistedSubnets))
+	}
+	if len(step.GlobalNodeConfig) > 0 {
+		globalNodeConfig, err := json.Marshal(step.GlobalNodeConfig)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, client.WithGlobalNodeConfig(string(globalNodeConfig)))
+	}
+	if step.PluginDir != "" {
+		opts = append(opts, client.WithPluginDir(step.PluginDir))
+	}
+	if len(step.CustomVMs) > 0 {
+		opts = append(opts, client.WithCustomVMs(step.CustomVMs))
+	}
+	_, err := r.cli.Start(ctx, step.ExecPath, opts...)
+	return err
+}
+
+// waitHealthy polls the cluster status until it is healthy,
+// then waits for all the nodes to report healthy
+func (r *Runner) waitHealthy(ctx context.Context, step Step) error {
+	for {
+		resp, err := r.cli.Status(ctx)
+		if err == nil && resp.ClusterInfo.Healthy &&
+			(!step.CustomVMsHealthy || resp.ClusterInfo.CustomVmsHealthy) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+			}
+			return ctx.Err()
+		case <-time.After(healthPollInterval):
+		}
+	}
+	_, err := r.cli.Health(ctx)
+	return err
+}
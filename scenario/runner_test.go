@@ -0,0 +1,116 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/client"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClient records the requests of the steps under test,
+// calls to other methods panic
+type fakeClient struct {
+	client.Client
+
+	removeErr error
+	stopped   bool
+	sentTo    string
+}
+
+func (c *fakeClient) RemoveNode(ctx context.Context, name string) (*rpcpb.RemoveNodeResponse, error) {
+	return &rpcpb.RemoveNodeResponse{}, c.removeErr
+}
+
+func (c *fakeClient) AttachPeer(ctx context.Context, nodeName string) (*rpcpb.AttachPeerResponse, error) {
+	return &rpcpb.AttachPeerResponse{AttachedPeerInfo: &rpcpb.AttachedPeerInfo{Id: "peer-" + nodeName}}, nil
+}
+
+func (c *fakeClient) SendOutboundMessage(ctx context.Context, nodeName string, peerID string, op uint32, msgBody []byte) (*rpcpb.SendOutboundMessageResponse, error) {
+	c.sentTo = peerID
+	return &rpcpb.SendOutboundMessageResponse{Sent: true}, nil
+}
+
+func (c *fakeClient) LoadSnapshot(ctx context.Context, snapshotName string, opts ...client.OpOption) (*rpcpb.LoadSnapshotResponse, error) {
+	return &rpcpb.LoadSnapshotResponse{}, nil
+}
+
+func (c *fakeClient) Stop(ctx context.Context) (*rpcpb.StopResponse, error) {
+	c.stopped = true
+	return &rpcpb.StopResponse{}, nil
+}
+
+func TestRun(t *testing.T) {
+	assert := assert.New(t)
+
+	cli := &fakeClient{}
+	sc := &Scenario{
+		Name: "peers",
+		Steps: []Step{
+			{Action: ActionAttachPeer, NodeName: "node1"},
+			{Action: ActionSendMessage, NodeName: "node1", Op: 1, Message: "hello"},
+			{Action: ActionSleep, Duration: time.Millisecond},
+			{Action: ActionStop},
+		},
+	}
+	results := NewRunner(cli).Run(context.Background(), sc)
+	assert.Len(results, 4)
+	for _, result := range results {
+		assert.NoError(result.Err)
+		assert.False(result.Skipped)
+	}
+	assert.Equal("peer-node1", cli.sentTo)
+	assert.True(cli.stopped)
+
+	// steps after a failure are skipped, unless marked "always"
+	cli = &fakeClient{removeErr: errors.New("node not found")}
+	sc = &Scenario{
+		Name: "failure",
+		Steps: []Step{
+			{Action: ActionRemoveNode, NodeName: "node1"},
+			{Action: ActionSendMessage, NodeName: "node1"},
+			{Action: ActionStop, Always: true},
+		},
+	}
+	results = NewRunner(cli).Run(context.Background(), sc)
+	assert.Len(results, 3)
+	assert.Error(results[0].Err)
+	assert.True(results[1].Skipped)
+	assert.ErrorIs(results[1].Err, ErrSkipped)
+	assert.NoError(results[2].Err)
+	assert.True(cli.stopped)
+
+	// sleeping is bound by the step timeout
+	sc = &Scenario{
+		Name:  "timeout",
+		Steps: []Step{{Action: ActionSleep, Duration: time.Minute, Timeout: time.Millisecond}},
+	}
+	results = NewRunner(cli).Run(context.Background(), sc)
+	assert.ErrorIs(results[0].Err, context.DeadlineExceeded)
+
+	// sending a message needs an attached peer
+	sc = &Scenario{
+		Name:  "no peer",
+		Steps: []Step{{Action: ActionSendMessage, NodeName: "node2"}},
+	}
+	results = NewRunner(cli).Run(context.Background(), sc)
+	assert.ErrorIs(results[0].Err, ErrNoPeer)
+
+	// the peers are forgotten once a snapshot is loaded, or the network stopped
+	for _, action := range []Action{ActionLoadSnapshot, ActionStop} {
+		sc = &Scenario{
+			Name: "reset peers",
+			Steps: []Step{
+				{Action: ActionAttachPeer, NodeName: "node1"},
+				{Action: action, SnapshotName: "snapshot"},
+				{Action: ActionSendMessage, NodeName: "node1"},
+			},
+		}
+		results = NewRunner(cli).Run(context.Background(), sc)
+		assert.NoError(results[1].Err)
+		assert.ErrorIs(results[2].Err, ErrNoPeer)
+	}
+}
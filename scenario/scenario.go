@@ -0,0 +1,236 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package scenario runs scripted test flows against the network runner server.
+package scenario
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// Action is what a step does
+type Action string
+
+const (
+	// Starts a network. Returns before the network is healthy.
+	ActionStart Action = "start"
+	// Waits for the network (and optionally its custom VMs) to be healthy.
+	ActionWaitHealthy  Action = "wait-healthy"
+	ActionAddNode      Action = "add-node"
+	ActionRemoveNode   Action = "remove-node"
+	ActionRestartNode  Action = "restart-node"
+	ActionSaveSnapshot Action = "save-snapshot"
+	ActionLoadSnapshot Action = "load-snapshot"
+	// Sends AXC on the AXChain, and waits for the tx to be accepted.
+	ActionSendCChainTx Action = "send-cchain-tx"
+	// Asserts the AXC balance of an AXChain address.
+	ActionAssertBalance Action = "assert-balance"
+	// Asserts the AXChain height.
+	ActionAssertBlockHeight Action = "assert-block-height"
+	ActionSleep             Action = "sleep"
+	// Attaches a test peer to a node.
+	ActionAttachPeer Action = "attach-peer"
+	// Sends a message to a node from an attached test peer.
+	ActionSendMessage Action = "send-message"
+	ActionStop        Action = "stop"
+)
+
+// DefaultStepTimeout is the step timeout if neither the step nor the scenario set one
+const DefaultStepTimeout = 2 * time.Minute
+
+var (
+	ErrNoSteps          = errors.New("scenario has no steps")
+	ErrInvalidAction    = errors.New("invalid action")
+	ErrMissingParameter = errors.New("missing parameter")
+	ErrInvalidParameter = errors.New("invalid parameter")
+)
+
+// Scenario is a list of steps run in order
+type Scenario struct {
+	Name string `yaml:"name"`
+	// Default timeout of the steps.
+	Timeout time.Duration `yaml:"timeout"`
+	Steps   []Step        `yaml:"steps"`
+}
+
+// Step is a single action of a scenario.
+// Only the parameters of its action are used.
+type Step struct {
+	// Defaults to the step index and action.
+	Name   string `yaml:"name"`
+	Action Action `yaml:"action"`
+	// Overrides the scenario timeout.
+	Timeout time.Duration `yaml:"timeout"`
+	// If true, the step runs even if a previous step failed,
+	// e.g. to stop the network at the end of the scenario.
+	Always bool `yaml:"always"`
+
+	// start, add-node, restart-node
+	ExecPath string `yaml:"execPath"`
+	// start
+	NumNodes           uint32                 `yaml:"numNodes"`
+	WhitelistedSubnets string                 `yaml:"whitelistedSubnets"`
+	GlobalNodeConfig   map[string]interface{} `yaml:"globalNodeConfig"`
+	PluginDir          string                 `yaml:"pluginDir"`
+	CustomVMs          map[string]string      `yaml:"customVMs"`
+
+	// wait-healthy: also wait for the custom VMs
+	CustomVMsHealthy bool `yaml:"customVMsHealthy"`
+
+	// add-node, remove-node, restart-node, attach-peer, send-message.
+	// Node to query for send-cchain-tx, assert-balance and assert-block-height,
+	// the first node if empty.
+	NodeName string `yaml:"nodeName"`
+
+	// save-snapshot, load-snapshot
+	SnapshotName string `yaml:"snapshotName"`
+
+	// sleep
+	Duration time.Duration `yaml:"duration"`
+
+	// send-cchain-tx: hex-encoded sender key, the pre-funded key if empty
+	PrivateKey string `yaml:"privateKey"`
+	// send-cchain-tx: defaults to the chain ID of the default network
+	ChainID int64 `yaml:"chainID"`
+	// send-cchain-tx
+	To string `yaml:"to"`
+	// send-cchain-tx, in wei
+	Amount string `yaml:"amount"`
+
+	// assert-balance
+	Address string `yaml:"address"`
+	// assert-balance, in wei. At least one of them must be set.
+	Balance    string `yaml:"balance"`
+	MinBalance string `yaml:"minBalance"`
+
+	// assert-block-height
+	MinHeight uint64 `yaml:"minHeight"`
+
+	// send-message: defaults to the last peer attached to the node
+	PeerID string `yaml:"peerID"`
+	// send-message
+	Op      uint32 `yaml:"op"`
+	Message string `yaml:"message"`
+}
+
+// Parse parses a YAML scenario, and validates it.
+// Unknown fields are rejected, to catch typos.
+func Parse(b []byte) (*Scenario, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	sc := &Scenario{}
+	if err := dec.Decode(sc); err != nil {
+		return nil, fmt.Errorf("couldn't parse scenario: %w", err)
+	}
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// Validate returns an error if this scenario is invalid
+func (sc *Scenario) Validate() error {
+	if len(sc.Steps) == 0 {
+		return ErrNoSteps
+	}
+	for i := range sc.Steps {
+		if err := sc.Steps[i].Validate(); err != nil {
+			return fmt.Errorf("step %q: %w", sc.GetStepName(i), err)
+		}
+	}
+	return nil
+}
+
+// GetStepName returns the name of step [i]
+func (sc *Scenario) GetStepName(i int) string {
+	step := sc.Steps[i]
+	if step.Name != "" {
+		return step.Name
+	}
+	return fmt.Sprintf("%d-%s", i+1, step.Action)
+}
+
+// GetStepTimeout returns the timeout of step [i]
+func (sc *Scenario) GetStepTimeout(i int) time.Duration {
+	if timeout := sc.Steps[i].Timeout; timeout > 0 {
+		return timeout
+	}
+	if sc.Timeout > 0 {
+		return sc.Timeout
+	}
+	return DefaultStepTimeout
+}
+
+// Validate returns an error if the parameters of this step's action are invalid
+func (s *Step) Validate() error {
+	switch s.Action {
+	case ActionWaitHealthy, ActionStop:
+	case ActionStart:
+		if s.ExecPath == "" {
+			return fmt.Errorf("%w: execPath", ErrMissingParameter)
+		}
+	case ActionAddNode, ActionRemoveNode, ActionRestartNode, ActionAttachPeer:
+		if s.NodeName == "" {
+			return fmt.Errorf("%w: nodeName", ErrMissingParameter)
+		}
+	case ActionSaveSnapshot, ActionLoadSnapshot:
+		if s.SnapshotName == "" {
+			return fmt.Errorf("%w: snapshotName", ErrMissingParameter)
+		}
+	case ActionSendCChainTx:
+		if !common.IsHexAddress(s.To) {
+			return fmt.Errorf("%w: to %q is not an address", ErrInvalidParameter, s.To)
+		}
+		if _, err := parseWei(s.Amount); err != nil {
+			return fmt.Errorf("%w: amount: %v", ErrInvalidParameter, err)
+		}
+	case ActionAssertBalance:
+		if !common.IsHexAddress(s.Address) {
+			return fmt.Errorf("%w: address %q is not an address", ErrInvalidParameter, s.Address)
+		}
+		if s.Balance == "" && s.MinBalance == "" {
+			return fmt.Errorf("%w: balance or minBalance", ErrMissingParameter)
+		}
+		if s.Balance != "" {
+			if _, err := parseWei(s.Balance); err != nil {
+				return fmt.Errorf("%w: balance: %v", ErrInvalidParameter, err)
+			}
+		}
+		if s.MinBalance != "" {
+			if _, err := parseWei(s.MinBalance); err != nil {
+				return fmt.Errorf("%w: minBalance: %v", ErrInvalidParameter, err)
+			}
+		}
+	case ActionAssertBlockHeight:
+		if s.MinHeight == 0 {
+			return fmt.Errorf("%w: minHeight", ErrMissingParameter)
+		}
+	case ActionSleep:
+		if s.Duration <= 0 {
+			return fmt.Errorf("%w: duration", ErrMissingParameter)
+		}
+	case ActionSendMessage:
+		if s.NodeName == "" {
+			return fmt.Errorf("%w: nodeName", ErrMissingParameter)
+		}
+	default:
+		return fmt.Errorf("%w %q", ErrInvalidAction, s.Action)
+	}
+	return nil
+}
+
+// parseWei parses a non-negative amount of wei, in base 10
+func parseWei(s string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("%q is not a non-negative integer", s)
+	}
+	return amount, nil
+}
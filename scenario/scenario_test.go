@@ -0,0 +1,124 @@
+package scenario
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	yamlScenario := `
+name: transfer
+timeout: 30s
+steps:
+  - action: start
+    execPath: /tmp/axia
+    numNodes: 3
+  - name: wait
+    action: wait-healthy
+    timeout: 5m
+  - action: send-cchain-tx
+    to: "0x0000000000000000000000000000000000000001"
+    amount: "1000000000000000000"
+  - action: assert-balance
+    address: "0x0000000000000000000000000000000000000001"
+    balance: "1000000000000000000"
+  - action: stop
+    always: true
+`
+	sc, err := Parse([]byte(yamlScenario))
+	assert.NoError(err)
+	assert.Equal("transfer", sc.Name)
+	assert.Len(sc.Steps, 5)
+	assert.Equal(ActionStart, sc.Steps[0].Action)
+	assert.EqualValues(3, sc.Steps[0].NumNodes)
+	assert.Equal("1-start", sc.GetStepName(0))
+	assert.Equal(30*time.Second, sc.GetStepTimeout(0))
+	assert.Equal("wait", sc.GetStepName(1))
+	assert.Equal(5*time.Minute, sc.GetStepTimeout(1))
+	assert.True(sc.Steps[4].Always)
+
+	// default step timeout
+	sc.Timeout = 0
+	assert.Equal(DefaultStepTimeout, sc.GetStepTimeout(0))
+
+	// unknown fields are rejected
+	_, err = Parse([]byte("steps:\n  - action: stop\n    nodename: node1\n"))
+	assert.Error(err)
+}
+
+func TestValidate(t *testing.T) {
+	type test struct {
+		name        string
+		step        Step
+		expectedErr error
+	}
+	tests := []test{
+		{
+			name:        "invalid action",
+			step:        Step{Action: "explode"},
+			expectedErr: ErrInvalidAction,
+		},
+		{
+			name:        "start without exec path",
+			step:        Step{Action: ActionStart},
+			expectedErr: ErrMissingParameter,
+		},
+		{
+			name:        "remove node without node name",
+			step:        Step{Action: ActionRemoveNode},
+			expectedErr: ErrMissingParameter,
+		},
+		{
+			name:        "load snapshot without snapshot name",
+			step:        Step{Action: ActionLoadSnapshot},
+			expectedErr: ErrMissingParameter,
+		},
+		{
+			name:        "send tx to invalid address",
+			step:        Step{Action: ActionSendCChainTx, To: "node1", Amount: "1"},
+			expectedErr: ErrInvalidParameter,
+		},
+		{
+			name:        "send tx with negative amount",
+			step:        Step{Action: ActionSendCChainTx, To: "0x0000000000000000000000000000000000000001", Amount: "-1"},
+			expectedErr: ErrInvalidParameter,
+		},
+		{
+			name:        "assert balance without balance",
+			step:        Step{Action: ActionAssertBalance, Address: "0x0000000000000000000000000000000000000001"},
+			expectedErr: ErrMissingParameter,
+		},
+		{
+			name:        "assert block height without height",
+			step:        Step{Action: ActionAssertBlockHeight},
+			expectedErr: ErrMissingParameter,
+		},
+		{
+			name:        "sleep without duration",
+			step:        Step{Action: ActionSleep},
+			expectedErr: ErrMissingParameter,
+		},
+		{
+			name:        "valid assert balance",
+			step:        Step{Action: ActionAssertBalance, Address: "0x0000000000000000000000000000000000000001", MinBalance: "10"},
+			expectedErr: nil,
+		},
+		{
+			name:        "valid sleep",
+			step:        Step{Action: ActionSleep, Duration: time.Second},
+			expectedErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.step.Validate()
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+
+	assert.ErrorIs(t, (&Scenario{}).Validate(), ErrNoSteps)
+}
@@ -105,7 +105,8 @@ echo "running e2e tests"
 --grpc-endpoint="0.0.0.0:8080" \
 --grpc-gateway-endpoint="0.0.0.0:8081" \
 --axia-path-1=/tmp/axia-v${VERSION_1}/axia \
---axia-path-2=/tmp/axia-v${VERSION_2}/axia
+--axia-path-2=/tmp/axia-v${VERSION_2}/axia \
+--artifacts-dir=${ARTIFACTS_DIR:-/tmp/network-runner-artifacts}
 
 kill -9 ${PID}
 echo "ALL SUCCESS!"
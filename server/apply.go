@@ -0,0 +1,421 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/axiacoin/axia-network-runner/network/node"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia-network-runner/spec"
+	"github.com/axiacoin/axia-network-runner/utils"
+	"github.com/axiacoin/axia/staking"
+	"go.uber.org/zap"
+)
+
+func (s *server) Apply(ctx context.Context, req *rpcpb.ApplyRequest) (*rpcpb.ApplyResponse, error) {
+	zap.L().Info("received apply request", zap.Bool("dry-run", req.DryRun))
+
+	netSpec, err := spec.Parse([]byte(req.Spec))
+	if err != nil {
+		return nil, err
+	}
+	// exec paths may be registered aliases
+	if netSpec.ExecPath, err = s.resolveExecPath(netSpec.ExecPath); err != nil {
+		return nil, err
+	}
+	for i, nodeSpec := range netSpec.Nodes {
+		if netSpec.Nodes[i].ExecPath, err = s.resolveExecPath(nodeSpec.ExecPath); err != nil {
+			return nil, fmt.Errorf("node %q: %w", nodeSpec.Name, err)
+		}
+	}
+	for _, nodeSpec := range netSpec.Nodes {
+		if err := utils.CheckExecPluginPaths(nodeSpec.GetExecPath(netSpec), "", ""); err != nil {
+			return nil, fmt.Errorf("invalid exec path for node %q: %w", nodeSpec.Name, err)
+		}
+	}
+
+	if info := s.getClusterInfo(); info == nil {
+		return s.applyStart(ctx, netSpec, req.DryRun)
+	}
+	return s.applyChanges(ctx, netSpec, req.DryRun)
+}
+
+// applyStart starts a new network from [netSpec], and waits for it
+// (and its custom VMs, if any) to be ready.
+func (s *server) applyStart(ctx context.Context, netSpec *spec.Spec, dryRun bool) (*rpcpb.ApplyResponse, error) {
+	resp := &rpcpb.ApplyResponse{
+		Started:       true,
+		AttachedPeers: make(map[string]uint32),
+	}
+	nodeSpecs := make([]spec.Node, 0, len(netSpec.Nodes))
+	customNodeConfigs := make(map[string]string)
+	for _, nodeSpec := range netSpec.Nodes {
+		nodeSpec.ExecPath = nodeSpec.GetExecPath(netSpec)
+		nodeSpecs = append(nodeSpecs, nodeSpec)
+		resp.AddedNodes = append(resp.AddedNodes, nodeSpec.Name)
+		if len(nodeSpec.Config) > 0 {
+			customNodeConfig, err := json.Marshal(nodeSpec.Config)
+			if err != nil {
+				return nil, err
+			}
+			customNodeConfigs[nodeSpec.Name] = string(customNodeConfig)
+		}
+	}
+	for nodeName, numPeers := range netSpec.AttachedPeers {
+		if numPeers > 0 {
+			resp.AttachedPeers[nodeName] = numPeers
+		}
+	}
+	if dryRun {
+		return resp, nil
+	}
+
+	req := &rpcpb.StartRequest{
+		ExecPath:          nodeSpecs[0].ExecPath,
+		CustomVms:         netSpec.CustomVMs,
+		CustomNodeConfigs: customNodeConfigs,
+	}
+	if whitelistedSubnets := mergeWhitelistedSubnets(netSpec.WhitelistedSubnets); whitelistedSubnets != "" {
+		req.WhitelistedSubnets = &whitelistedSubnets
+	}
+	if len(netSpec.GlobalNodeConfig) > 0 {
+		globalNodeConfig, err := json.Marshal(netSpec.GlobalNodeConfig)
+		if err != nil {
+			return nil, err
+		}
+		globalNodeConfigStr := string(globalNodeConfig)
+		req.GlobalNodeConfig = &globalNodeConfigStr
+	}
+	if netSpec.PluginDir != "" {
+		req.PluginDir = &netSpec.PluginDir
+	}
+
+	// as for "Start", the network keeps starting if this request is canceled
+	startCtx, cancel := context.WithTimeout(context.Background(), DefaultStartTimeout)
+	_ = cancel
+	if _, err := s.start(startCtx, req, nodeSpecs); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	lc := s.network
+	s.mu.RUnlock()
+	readyCh := lc.localClusterReadyCh
+	if len(netSpec.CustomVMs) > 0 {
+		readyCh = lc.customVMsReadyCh
+	}
+	zap.L().Info("waiting for the network to be ready")
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-lc.stopCh:
+		return nil, errAborted
+	case <-readyCh:
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// stopped while waiting for the lock
+	if s.network != lc {
+		return nil, errAborted
+	}
+	s.clusterInfo.NodeNames = s.network.nodeNames
+	s.clusterInfo.NodeInfos = s.network.nodeInfos
+	s.clusterInfo.Healthy = true
+	if len(netSpec.CustomVMs) > 0 {
+		s.updateCustomVMsInfo()
+	}
+	if err := s.attachPeers(ctx, resp.AttachedPeers); err != nil {
+		return nil, err
+	}
+
+	resp.ClusterInfo = s.clusterInfo
+	return resp, nil
+}
+
+// applyChanges converges the running network to [netSpec].
+// Nodes not in the spec are removed, missing nodes are added,
+// and changed nodes are restarted according to their restart policy.
+func (s *server) applyChanges(ctx context.Context, netSpec *spec.Spec, dryRun bool) (*rpcpb.ApplyResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.clusterInfo == nil || s.network == nil {
+		return nil, ErrNotBootstrapped
+	}
+	if s.network.nw == nil || !s.clusterInfo.Healthy ||
+		(len(s.network.customVMNameToGenesis) > 0 && !s.clusterInfo.CustomVmsHealthy) {
+		return nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	// the nodes removed by the faults would be added back by the changes
+	if s.chaos != nil && s.chaos.running() {
+		return nil, ErrChaosEnabled
+	}
+
+	// custom VMs are not installed by apply, so they must not change
+	installedVMNames := make(map[string]struct{})
+	for _, vmInfo := range s.network.customVMIDToInfo {
+		installedVMNames[vmInfo.info.VmName] = struct{}{}
+	}
+	if len(installedVMNames) != len(netSpec.CustomVMs) {
+		return nil, ErrCustomVMsChanged
+	}
+	for vmName := range netSpec.CustomVMs {
+		if _, ok := installedVMNames[vmName]; !ok {
+			return nil, fmt.Errorf("%w: %q is not installed", ErrCustomVMsChanged, vmName)
+		}
+	}
+	buildDir, err := getBuildDir(netSpec.PluginDir)
+	if err != nil {
+		return nil, err
+	}
+
+	runningNodes, err := s.network.nw.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	resp := &rpcpb.ApplyResponse{AttachedPeers: make(map[string]uint32)}
+	specNodeNames := make(map[string]struct{}, len(netSpec.Nodes))
+	for _, nodeSpec := range netSpec.Nodes {
+		specNodeNames[nodeSpec.Name] = struct{}{}
+	}
+	for nodeName := range runningNodes {
+		if _, ok := specNodeNames[nodeName]; !ok {
+			resp.RemovedNodes = append(resp.RemovedNodes, nodeName)
+		}
+	}
+	sort.Strings(resp.RemovedNodes)
+
+	var restartConfigs, addConfigs []node.Config
+	for _, nodeSpec := range netSpec.Nodes {
+		execPath := nodeSpec.GetExecPath(netSpec)
+		whitelistedSubnets := s.network.specWhitelistedSubnets(netSpec.WhitelistedSubnets, nodeSpec.Name)
+		configFile, err := s.specNodeConfigFile(netSpec, nodeSpec, buildDir, whitelistedSubnets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate json node config string for node %q: %w", nodeSpec.Name, err)
+		}
+
+		if _, ok := runningNodes[nodeSpec.Name]; !ok {
+			stakingCert, stakingKey, err := staking.NewCertAndKeyBytes()
+			if err != nil {
+				return nil, fmt.Errorf("couldn't generate staking Cert/Key: %w", err)
+			}
+			addConfigs = append(addConfigs, node.Config{
+				Name:           nodeSpec.Name,
+				ConfigFile:     configFile,
+				StakingKey:     string(stakingKey),
+				StakingCert:    string(stakingCert),
+				BinaryPath:     execPath,
+				IsBeacon:       nodeSpec.IsBeacon,
+				RedirectStdout: s.cfg.RedirectNodesOutput,
+				RedirectStderr: s.cfg.RedirectNodesOutput,
+			})
+			resp.AddedNodes = append(resp.AddedNodes, nodeSpec.Name)
+			continue
+		}
+
+		nodeConfig, ok := s.network.getNodeConfig(nodeSpec.Name)
+		if !ok {
+			return nil, fmt.Errorf("%w: no config for node %q", ErrNodeNotFound, nodeSpec.Name)
+		}
+		sameConfig, err := sameConfigFile(nodeConfig.ConfigFile, configFile)
+		if err != nil {
+			return nil, err
+		}
+		changed := nodeConfig.BinaryPath != execPath || nodeConfig.IsBeacon != nodeSpec.IsBeacon || !sameConfig
+		switch nodeSpec.GetRestartPolicy() {
+		case spec.RestartNever:
+			if changed {
+				resp.DriftedNodes = append(resp.DriftedNodes, nodeSpec.Name)
+			}
+			continue
+		case spec.RestartOnChange:
+			if !changed {
+				continue
+			}
+		}
+		nodeConfig.ConfigFile = configFile
+		nodeConfig.BinaryPath = execPath
+		nodeConfig.IsBeacon = nodeSpec.IsBeacon
+		nodeConfig.RedirectStdout = s.cfg.RedirectNodesOutput
+		nodeConfig.RedirectStderr = s.cfg.RedirectNodesOutput
+		restartConfigs = append(restartConfigs, nodeConfig)
+		resp.RestartedNodes = append(resp.RestartedNodes, nodeSpec.Name)
+	}
+
+	// peers of removed or restarted nodes are disconnected
+	droppedPeers := make(map[string]struct{})
+	for _, nodeName := range append(resp.RemovedNodes, resp.RestartedNodes...) {
+		droppedPeers[nodeName] = struct{}{}
+	}
+	for nodeName, numPeers := range netSpec.AttachedPeers {
+		var attached uint32
+		if _, ok := droppedPeers[nodeName]; !ok {
+			attached = uint32(len(s.network.attachedPeers[nodeName]))
+		}
+		if numPeers > attached {
+			resp.AttachedPeers[nodeName] = numPeers - attached
+		}
+	}
+
+	// the versions reported in the infos of the restarted and added nodes
+	execPaths := []string{}
+	for _, nodeConfig := range restartConfigs {
+		execPaths = append(execPaths, nodeConfig.BinaryPath)
+	}
+	for _, nodeConfig := range addConfigs {
+		execPaths = append(execPaths, nodeConfig.BinaryPath)
+	}
+	binaryVersions := detectBinaryVersions(execPaths...)
+
+	zap.L().Info("computed changes to apply",
+		zap.Strings("removed-nodes", resp.RemovedNodes),
+		zap.Strings("restarted-nodes", resp.RestartedNodes),
+		zap.Strings("added-nodes", resp.AddedNodes),
+		zap.Strings("drifted-nodes", resp.DriftedNodes),
+	)
+	if dryRun {
+		resp.ClusterInfo = s.clusterInfo
+		return resp, nil
+	}
+
+	for _, nodeName := range resp.RemovedNodes {
+		zap.L().Info("removing the node", zap.String("node-name", nodeName))
+		if err := s.network.nw.RemoveNode(nodeName); err != nil {
+			return nil, err
+		}
+		s.network.removeNodeConfig(nodeName)
+		s.dropAttachedPeers(nodeName)
+	}
+	for _, nodeConfig := range restartConfigs {
+		zap.L().Info("restarting the node", zap.String("node-name", nodeConfig.Name))
+		if err := s.network.nw.RemoveNode(nodeConfig.Name); err != nil {
+			return nil, err
+		}
+		s.network.removeNodeConfig(nodeConfig.Name)
+		s.dropAttachedPeers(nodeConfig.Name)
+		if _, err := s.network.nw.AddNode(nodeConfig); err != nil {
+			return nil, err
+		}
+		s.network.cfg.NodeConfigs = append(s.network.cfg.NodeConfigs, nodeConfig)
+	}
+	for _, nodeConfig := range addConfigs {
+		zap.L().Info("adding the node", zap.String("node-name", nodeConfig.Name))
+		if _, err := s.network.nw.AddNode(nodeConfig); err != nil {
+			return nil, err
+		}
+		s.network.cfg.NodeConfigs = append(s.network.cfg.NodeConfigs, nodeConfig)
+	}
+
+	s.network.setBinaryVersions(binaryVersions)
+
+	zap.L().Info("waiting for local cluster readiness")
+	if err := s.network.waitForLocalClusterReady(ctx); err != nil {
+		return nil, err
+	}
+	s.clusterInfo.NodeNames = s.network.nodeNames
+	s.clusterInfo.NodeInfos = s.network.nodeInfos
+
+	if err := s.attachPeers(ctx, resp.AttachedPeers); err != nil {
+		return nil, err
+	}
+
+	resp.ClusterInfo = s.clusterInfo
+	return resp, nil
+}
+
+// specNodeConfigFile returns the config file of node [nodeSpec],
+// merging the default node config, the spec global config and the node config
+func (s *server) specNodeConfigFile(
+	netSpec *spec.Spec,
+	nodeSpec spec.Node,
+	buildDir string,
+	whitelistedSubnets string,
+) (string, error) {
+	var defaultConfig map[string]interface{}
+	if err := json.Unmarshal([]byte(defaultNodeConfig), &defaultConfig); err != nil {
+		return "", err
+	}
+	customConfig := ""
+	if len(nodeSpec.Config) > 0 {
+		b, err := json.Marshal(nodeSpec.Config)
+		if err != nil {
+			return "", err
+		}
+		customConfig = string(b)
+	}
+	mergedConfig, err := mergeNodeConfig(defaultConfig, netSpec.GlobalNodeConfig, customConfig)
+	if err != nil {
+		return "", fmt.Errorf("failed merging provided configs: %w", err)
+	}
+	logDir := filepath.Join(s.clusterInfo.RootDataDir, nodeSpec.Name, "log")
+	dbDir := filepath.Join(s.clusterInfo.RootDataDir, nodeSpec.Name, "db-dir")
+	return createConfigFileString(mergedConfig, logDir, dbDir, buildDir, whitelistedSubnets)
+}
+
+// specWhitelistedSubnets returns the subnets whitelisted by node [nodeName] of a spec
+// whitelisting [specSubnets]: these, with the custom VM subnets the node tracks if running,
+// or with all of them if added
+func (lc *localNetwork) specWhitelistedSubnets(specSubnets string, nodeName string) string {
+	nodeInfo, running := lc.nodeInfos[nodeName]
+	subnetIDs := []string{}
+	for _, vmInfo := range lc.customVMIDToInfo {
+		subnetID := vmInfo.subnetID.String()
+		if !running || tracksSubnet(nodeInfo, subnetID) {
+			subnetIDs = append(subnetIDs, subnetID)
+		}
+	}
+	return mergeWhitelistedSubnets(specSubnets, strings.Join(subnetIDs, ","))
+}
+
+// sameConfigFile returns true if the JSON config files [a] and [b]
+// have the same entries, regardless of their order and formatting
+func sameConfigFile(a string, b string) (bool, error) {
+	var aMap, bMap map[string]interface{}
+	if err := json.Unmarshal([]byte(a), &aMap); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal([]byte(b), &bMap); err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(aMap, bMap), nil
+}
+
+// attachPeers attaches [numPeers] new test peers to each node.
+// Assumes [s.mu] is held.
+func (s *server) attachPeers(ctx context.Context, numPeers map[string]uint32) error {
+	nodeNames := make([]string, 0, len(numPeers))
+	for nodeName := range numPeers {
+		nodeNames = append(nodeNames, nodeName)
+	}
+	sort.Strings(nodeNames)
+	for _, nodeName := range nodeNames {
+		for i := uint32(0); i < numPeers[nodeName]; i++ {
+			if _, err := s.attachPeer(ctx, nodeName); err != nil {
+				return fmt.Errorf("failed to attach peer to node %q: %w", nodeName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// dropAttachedPeers forgets the peers attached to node [nodeName].
+// Assumes [s.mu] is held.
+func (s *server) dropAttachedPeers(nodeName string) {
+	delete(s.network.attachedPeers, nodeName)
+	if s.clusterInfo.AttachedPeerInfos != nil {
+		delete(s.clusterInfo.AttachedPeerInfos, nodeName)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/network/node"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"go.uber.org/zap"
+)
+
+const (
+	// size of the tar.gz chunks sent on the stream
+	artifactsChunkSize = 1024 * 1024
+	// timeout for each health/info API dump
+	artifactsAPITimeout = 10 * time.Second
+
+	clusterInfoArtifact = "cluster-info.json"
+	processArtifact     = "process.json"
+	healthArtifact      = "health.json"
+	infoArtifact        = "info.json"
+	logsArtifactDir     = "logs"
+)
+
+var stakingArtifacts = map[string]struct{}{
+	"staking.key": {},
+	"staking.crt": {},
+}
+
+// processInfo is what gets recorded about each node's process
+type processInfo struct {
+	CommandLine []string `json:"commandLine"`
+	Exited      bool     `json:"exited"`
+	ExitError   string   `json:"exitError,omitempty"`
+}
+
+// chunkSender sends everything written to it on a CollectArtifacts stream.
+// Wrap it in a buffered writer to control the size of the chunks.
+type chunkSender struct {
+	stream rpcpb.ControlService_CollectArtifactsServer
+}
+
+func (cs *chunkSender) Write(b []byte) (int, error) {
+	// the message may be kept by the gRPC layer after Send returns
+	chunk := make([]byte, len(b))
+	copy(chunk, b)
+	if err := cs.stream.Send(&rpcpb.CollectArtifactsResponse{Chunk: chunk}); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (s *server) CollectArtifacts(req *rpcpb.CollectArtifactsRequest, stream rpcpb.ControlService_CollectArtifactsServer) error {
+	zap.L().Info("received collect artifacts request", zap.Strings("node-names", req.NodeNames))
+	info := s.getClusterInfo()
+	if info == nil {
+		return ErrNotBootstrapped
+	}
+
+	// a copy of the cluster info, that can be marshaled without holding the lock
+	clusterInfoBytes, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	nodes, err := s.getNodes(req.NodeNames)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriterSize(&chunkSender{stream: stream}, artifactsChunkSize)
+	gw := gzip.NewWriter(bw)
+	tw := tar.NewWriter(gw)
+
+	if err := addArtifactBytes(tw, clusterInfoArtifact, clusterInfoBytes); err != nil {
+		return err
+	}
+	for _, nd := range nodes {
+		zap.L().Info("collecting node artifacts", zap.String("node-name", nd.GetName()))
+		nodeDir := filepath.Join(info.RootDataDir, nd.GetName())
+		if err := addNodeArtifacts(stream.Context(), tw, nd, nodeDir, req.ExcludeStakingCerts); err != nil {
+			return fmt.Errorf("failed to collect artifacts for node %q: %w", nd.GetName(), err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// addNodeArtifacts adds to [tw], under a directory named after the node,
+// the contents of [nodeDir] except for the database, the node's logs dir,
+// and dumps of its process status and health/info APIs.
+func addNodeArtifacts(ctx context.Context, tw *tar.Writer, nd node.Node, nodeDir string, excludeStakingCerts bool) error {
+	name := nd.GetName()
+
+	exited, exitErr := nd.GetExitStatus()
+	pInfo := processInfo{
+		CommandLine: nd.GetCommandLine(),
+		Exited:      exited,
+	}
+	if exitErr != nil {
+		pInfo.ExitError = exitErr.Error()
+	}
+	if err := addArtifactJSON(tw, filepath.Join(name, processArtifact), pInfo); err != nil {
+		return err
+	}
+
+	// dump the APIs unless the node is known to be down,
+	// failures are recorded in the dumps themselves
+	if !exited {
+		if err := addArtifactJSON(tw, filepath.Join(name, healthArtifact), dumpHealth(ctx, nd)); err != nil {
+			return err
+		}
+		if err := addArtifactJSON(tw, filepath.Join(name, infoArtifact), dumpInfo(ctx, nd)); err != nil {
+			return err
+		}
+	}
+
+	// generated config files, genesis, staking certs, chain configs, ...
+	skip := func(path string, d fs.DirEntry) bool {
+		if d.IsDir() {
+			return isSubPath(path, nd.GetDbDir()) || isSubPath(path, nd.GetLogsDir())
+		}
+		_, isStaking := stakingArtifacts[d.Name()]
+		return excludeStakingCerts && isStaking
+	}
+	if err := addArtifactDir(tw, nodeDir, name, skip); err != nil {
+		return err
+	}
+	return addArtifactDir(tw, nd.GetLogsDir(), filepath.Join(name, logsArtifactDir), nil)
+}
+
+func dumpHealth(ctx context.Context, nd node.Node) interface{} {
+	ctx, cancel := context.WithTimeout(ctx, artifactsAPITimeout)
+	defer cancel()
+	health, err := nd.GetAPIClient().HealthAPI().Health(ctx)
+	if err != nil {
+		return map[string]string{"error": err.Error()}
+	}
+	return health
+}
+
+func dumpInfo(ctx context.Context, nd node.Node) interface{} {
+	ctx, cancel := context.WithTimeout(ctx, artifactsAPITimeout)
+	defer cancel()
+	infoClient := nd.GetAPIClient().InfoAPI()
+	dump := map[string]interface{}{}
+	record := func(key string, v interface{}, err error) {
+		if err != nil {
+			dump[key] = map[string]string{"error": err.Error()}
+			return
+		}
+		dump[key] = v
+	}
+	nodeID, err := infoClient.GetNodeID(ctx)
+	record("nodeID", nodeID, err)
+	nodeVersion, err := infoClient.GetNodeVersion(ctx)
+	record("nodeVersion", nodeVersion, err)
+	networkID, err := infoClient.GetNetworkID(ctx)
+	record("networkID", networkID, err)
+	peers, err := infoClient.Peers(ctx)
+	record("peers", peers, err)
+	for _, chain := range []string{"P", "X", "C"} {
+		bootstrapped, err := infoClient.IsBootstrapped(ctx, chain)
+		record("bootstrapped"+chain, bootstrapped, err)
+	}
+	return dump
+}
+
+// addArtifactDir adds the files under [dir] to [tw], under [tarDir].
+// Files and directories for which [skip] returns true are left out.
+// A missing [dir] is ignored.
+func addArtifactDir(tw *tar.Writer, dir string, tarDir string, skip func(string, fs.DirEntry) bool) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if skip != nil && skip(path, d) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		return addArtifactFile(tw, path, filepath.Join(tarDir, rel))
+	})
+}
+
+func addArtifactFile(tw *tar.Writer, path string, tarPath string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = filepath.ToSlash(tarPath)
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	// logs may keep growing while they're copied,
+	// only take what was there when the header was written
+	_, err = io.CopyN(tw, f, hdr.Size)
+	return err
+}
+
+func addArtifactJSON(tw *tar.Writer, tarPath string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return addArtifactBytes(tw, tarPath, b)
+}
+
+func addArtifactBytes(tw *tar.Writer, tarPath string, b []byte) error {
+	hdr := &tar.Header{
+		Name:    filepath.ToSlash(tarPath),
+		Mode:    0o644,
+		Size:    int64(len(b)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(b)
+	return err
+}
+
+// isSubPath returns true if [path] is [dir] or is inside of it
+func isSubPath(path string, dir string) bool {
+	if dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,300 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia/ids"
+	"github.com/axiacoin/axia/utils/crypto"
+	"github.com/axiacoin/axia/vms/components/axc"
+	"github.com/axiacoin/axia/vms/components/verify"
+	"github.com/axiacoin/axia/vms/secp256k1fx"
+	"github.com/axiacoin/axia/wallet/subnet/primary/common"
+	"go.uber.org/zap"
+)
+
+const (
+	maxAssetNameLen      = 128
+	maxAssetSymbolLen    = 4
+	maxAssetDenomination = 32
+	// index of secp256k1fx in the fxs of the SwapChain
+	secp256k1fxIndex = 0
+)
+
+// assetHolder is an address holding an amount of an asset
+type assetHolder struct {
+	addr   ids.ShortID
+	amount uint64
+}
+
+func (s *server) CreateAsset(ctx context.Context, req *rpcpb.CreateAssetRequest) (*rpcpb.CreateAssetResponse, error) {
+	zap.L().Info("received create asset request",
+		zap.String("name", req.Name),
+		zap.String("symbol", req.Symbol),
+		zap.Uint32("denomination", req.Denomination),
+		zap.Bool("variable-cap", req.VariableCap),
+		zap.Int("initial-holders", len(req.InitialHolders)),
+	)
+	holders, err := checkCreateAssetRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	assetID, err := s.network.createAsset(ctx, req.Name, req.Symbol, byte(req.Denomination), req.VariableCap, holders)
+	if err != nil {
+		return nil, err
+	}
+	zap.L().Info("created asset", zap.String("name", req.Name), zap.String("asset-id", assetID.String()))
+
+	if s.clusterInfo.Assets == nil {
+		s.clusterInfo.Assets = make(map[string]*rpcpb.AssetInfo)
+	}
+	s.clusterInfo.Assets[assetID.String()] = &rpcpb.AssetInfo{
+		AssetId:      assetID.String(),
+		Name:         req.Name,
+		Symbol:       req.Symbol,
+		Denomination: req.Denomination,
+		VariableCap:  req.VariableCap,
+	}
+	return &rpcpb.CreateAssetResponse{ClusterInfo: s.clusterInfo, AssetId: assetID.String()}, nil
+}
+
+// checkCreateAssetRequest checks the name, symbol and denomination of the asset,
+// and that a fixed-cap asset has a supply, and returns its initial holders
+func checkCreateAssetRequest(req *rpcpb.CreateAssetRequest) ([]assetHolder, error) {
+	if req.Name == "" || len(req.Name) > maxAssetNameLen {
+		return nil, fmt.Errorf("%w: name %q must have 1 to %d characters", ErrInvalidAsset, req.Name, maxAssetNameLen)
+	}
+	if len(req.Symbol) > maxAssetSymbolLen {
+		return nil, fmt.Errorf("%w: symbol %q must have up to %d characters", ErrInvalidAsset, req.Symbol, maxAssetSymbolLen)
+	}
+	for _, r := range req.Symbol {
+		if r < 'A' || r > 'Z' {
+			return nil, fmt.Errorf("%w: symbol %q must only have upper case letters", ErrInvalidAsset, req.Symbol)
+		}
+	}
+	if req.Denomination > maxAssetDenomination {
+		return nil, fmt.Errorf("%w: denomination %d over %d", ErrInvalidAsset, req.Denomination, maxAssetDenomination)
+	}
+	holders := make([]assetHolder, 0, len(req.InitialHolders))
+	for _, h := range req.InitialHolders {
+		holder, err := parseAssetHolder(h)
+		if err != nil {
+			return nil, err
+		}
+		holders = append(holders, holder)
+	}
+	if !req.VariableCap && len(holders) == 0 {
+		return nil, fmt.Errorf("%w: fixed-cap asset without initial holders", ErrInvalidAsset)
+	}
+	return holders, nil
+}
+
+// parseAssetHolder parses the SwapChain address of [h], and checks its amount
+func parseAssetHolder(h *rpcpb.AssetHolder) (assetHolder, error) {
+	target, err := parseFundTarget(h.GetAddress())
+	if err != nil {
+		return assetHolder{}, err
+	}
+	if target.chain != "X" {
+		return assetHolder{}, fmt.Errorf("%w: %q is not a SwapChain address", ErrInvalidAddress, h.GetAddress())
+	}
+	if h.GetAmount() == 0 {
+		return assetHolder{}, ErrInvalidAmount
+	}
+	return assetHolder{addr: target.addr, amount: h.GetAmount()}, nil
+}
+
+func (s *server) MintAsset(ctx context.Context, req *rpcpb.MintAssetRequest) (*rpcpb.MintAssetResponse, error) {
+	zap.L().Info("received mint asset request",
+		zap.String("asset-id", req.AssetId),
+		zap.String("address", req.GetHolder().GetAddress()),
+		zap.Uint64("amount", req.GetHolder().GetAmount()),
+	)
+	assetID, err := ids.FromString(req.AssetId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidAsset, req.AssetId, err)
+	}
+	holder, err := parseAssetHolder(req.Holder)
+	if err != nil {
+		return nil, err
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	assetInfo, ok := s.clusterInfo.Assets[req.AssetId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAssetNotFound, req.AssetId)
+	}
+	if !assetInfo.VariableCap {
+		return nil, fmt.Errorf("%w: %s is fixed-cap", ErrInvalidAsset, req.AssetId)
+	}
+	txID, err := s.network.mintAsset(ctx, assetID, holder)
+	if err != nil {
+		return nil, err
+	}
+	zap.L().Info("minted asset", zap.String("asset-id", req.AssetId), zap.String("tx-id", txID.String()))
+	return &rpcpb.MintAssetResponse{ClusterInfo: s.clusterInfo, TxId: txID.String()}, nil
+}
+
+func (s *server) TransferAsset(ctx context.Context, req *rpcpb.TransferAssetRequest) (*rpcpb.TransferAssetResponse, error) {
+	zap.L().Info("received transfer asset request",
+		zap.String("asset-id", req.AssetId),
+		zap.String("key-name", req.KeyName),
+		zap.String("address", req.GetRecipient().GetAddress()),
+		zap.Uint64("amount", req.GetRecipient().GetAmount()),
+	)
+	assetID, err := ids.FromString(req.AssetId)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %v", ErrInvalidAsset, req.AssetId, err)
+	}
+	recipient, err := parseAssetHolder(req.Recipient)
+	if err != nil {
+		return nil, err
+	}
+	keyName := req.KeyName
+	if keyName == "" {
+		keyName = prefundedKeyName
+	}
+	key, ok := s.keys.get(keyName)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, keyName)
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	txID, err := s.network.transferAsset(ctx, key, assetID, recipient)
+	if err != nil {
+		return nil, err
+	}
+	zap.L().Info("transferred asset", zap.String("asset-id", req.AssetId), zap.String("tx-id", txID.String()))
+	return &rpcpb.TransferAssetResponse{ClusterInfo: s.clusterInfo, TxId: txID.String()}, nil
+}
+
+// createAsset issues a tx from the pre-funded test key creating a SwapChain asset held by [holders],
+// and mintable by the test key if [variableCap]
+func (lc *localNetwork) createAsset(
+	ctx context.Context,
+	name string,
+	symbol string,
+	denomination byte,
+	variableCap bool,
+	holders []assetHolder,
+) (ids.ID, error) {
+	baseWallet, _, testKeyAddr, err := lc.setupWallet(ctx, lc.nodeInfos[lc.nodeNames[0]].Uri)
+	if err != nil {
+		return ids.Empty, err
+	}
+	outs := make([]verify.State, 0, len(holders)+1)
+	for _, holder := range holders {
+		outs = append(outs, &secp256k1fx.TransferOutput{
+			Amt: holder.amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{holder.addr},
+			},
+		})
+	}
+	if variableCap {
+		outs = append(outs, &secp256k1fx.MintOutput{
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{testKeyAddr},
+			},
+		})
+	}
+
+	println()
+	color.Outf("{{green}}creating asset %q (%s) on the SwapChain{{/}}\n", name, symbol)
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	return baseWallet.X().IssueCreateAssetTx(
+		name,
+		symbol,
+		denomination,
+		map[uint32][]verify.State{secp256k1fxIndex: outs},
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+}
+
+// mintAsset issues a tx from the pre-funded test key minting the variable-cap asset [assetID] to [holder]
+func (lc *localNetwork) mintAsset(ctx context.Context, assetID ids.ID, holder assetHolder) (ids.ID, error) {
+	baseWallet, _, _, err := lc.setupWallet(ctx, lc.nodeInfos[lc.nodeNames[0]].Uri)
+	if err != nil {
+		return ids.Empty, err
+	}
+
+	println()
+	color.Outf("{{green}}minting %d of asset %s to %s{{/}}\n", holder.amount, assetID, holder.addr)
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	return baseWallet.X().IssueOperationTxMintFT(
+		map[ids.ID]*secp256k1fx.TransferOutput{
+			assetID: {
+				Amt: holder.amount,
+				OutputOwners: secp256k1fx.OutputOwners{
+					Threshold: 1,
+					Addrs:     []ids.ShortID{holder.addr},
+				},
+			},
+		},
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+}
+
+// transferAsset issues a tx from [key] sending asset [assetID] to [recipient], paying the fee from [key]
+func (lc *localNetwork) transferAsset(ctx context.Context, key *crypto.PrivateKeySECP256K1R, assetID ids.ID, recipient assetHolder) (ids.ID, error) {
+	wallet, err := createRefreshableWallet(ctx, lc.nodeInfos[lc.nodeNames[0]].Uri, secp256k1fx.NewKeychain(key))
+	if err != nil {
+		return ids.Empty, err
+	}
+
+	println()
+	color.Outf("{{green}}sending %d of asset %s to %s{{/}}\n", recipient.amount, assetID, recipient.addr)
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	return wallet.X().IssueBaseTx(
+		[]*axc.TransferableOutput{transferableOutput(assetID, recipient.amount, recipient.addr)},
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+}
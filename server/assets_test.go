@@ -0,0 +1,53 @@
+package server
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCreateAssetRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	const addr = "X-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p"
+	holders, err := checkCreateAssetRequest(&rpcpb.CreateAssetRequest{
+		Name:           "Fixed",
+		Symbol:         "FIX",
+		Denomination:   9,
+		InitialHolders: []*rpcpb.AssetHolder{{Address: addr, Amount: 1000}},
+	})
+	assert.NoError(err)
+	assert.Len(holders, 1)
+	assert.Equal("6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV", holders[0].addr.String())
+	assert.Equal(uint64(1000), holders[0].amount)
+
+	// a variable-cap asset can be created without supply
+	holders, err = checkCreateAssetRequest(&rpcpb.CreateAssetRequest{Name: "Variable", VariableCap: true})
+	assert.NoError(err)
+	assert.Empty(holders)
+
+	for _, req := range []*rpcpb.CreateAssetRequest{
+		{Symbol: "FIX", VariableCap: true},
+		{Name: strings.Repeat("a", maxAssetNameLen+1), VariableCap: true},
+		{Name: "Fixed", Symbol: "FIXED", VariableCap: true},
+		{Name: "Fixed", Symbol: "fix", VariableCap: true},
+		{Name: "Fixed", Denomination: maxAssetDenomination + 1, VariableCap: true},
+		{Name: "Fixed"},
+	} {
+		_, err := checkCreateAssetRequest(req)
+		assert.True(errors.Is(err, ErrInvalidAsset), req.String())
+	}
+	_, err = checkCreateAssetRequest(&rpcpb.CreateAssetRequest{
+		Name:           "Fixed",
+		InitialHolders: []*rpcpb.AssetHolder{{Address: "P-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p", Amount: 1000}},
+	})
+	assert.True(errors.Is(err, ErrInvalidAddress))
+	_, err = checkCreateAssetRequest(&rpcpb.CreateAssetRequest{
+		Name:           "Fixed",
+		InitialHolders: []*rpcpb.AssetHolder{{Address: addr}},
+	})
+	assert.True(errors.Is(err, ErrInvalidAmount))
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia-network-runner/utils"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+func (s *server) RegisterBinary(ctx context.Context, req *rpcpb.RegisterBinaryRequest) (*rpcpb.RegisterBinaryResponse, error) {
+	zap.L().Info("received register binary request",
+		zap.String("alias", req.Alias),
+		zap.String("exec-path", req.ExecPath),
+	)
+	if req.Alias == "" {
+		return nil, ErrNoBinaryAlias
+	}
+	if err := utils.CheckExecPluginPaths(req.ExecPath, "", ""); err != nil {
+		return nil, err
+	}
+	execPath, err := filepath.Abs(req.ExecPath)
+	if err != nil {
+		return nil, err
+	}
+	checksum, err := fileSHA256(execPath)
+	if err != nil {
+		return nil, err
+	}
+	version, err := utils.GetBinaryVersion(execPath)
+	if err != nil {
+		return nil, err
+	}
+	binary := &rpcpb.BinaryInfo{
+		Alias:      req.Alias,
+		ExecPath:   execPath,
+		Sha256:     checksum,
+		AppVersion: version.App,
+		DbVersion:  version.Database,
+	}
+
+	s.binariesMu.Lock()
+	defer s.binariesMu.Unlock()
+	if prev, ok := s.binaries[req.Alias]; ok {
+		zap.L().Info("replacing registered binary",
+			zap.String("alias", req.Alias),
+			zap.String("exec-path", prev.ExecPath),
+		)
+	}
+	s.binaries[req.Alias] = binary
+	return &rpcpb.RegisterBinaryResponse{Binary: proto.Clone(binary).(*rpcpb.BinaryInfo)}, nil
+}
+
+func (s *server) ListBinaries(ctx context.Context, req *rpcpb.ListBinariesRequest) (*rpcpb.ListBinariesResponse, error) {
+	zap.L().Debug("received list binaries request")
+
+	s.binariesMu.Lock()
+	defer s.binariesMu.Unlock()
+	binaries := make([]*rpcpb.BinaryInfo, 0, len(s.binaries))
+	for _, binary := range s.binaries {
+		binaries = append(binaries, proto.Clone(binary).(*rpcpb.BinaryInfo))
+	}
+	sort.Slice(binaries, func(i, j int) bool {
+		return binaries[i].Alias < binaries[j].Alias
+	})
+	return &rpcpb.ListBinariesResponse{Binaries: binaries}, nil
+}
+
+// resolveExecPath returns the path of the binary registered as [execPath],
+// after checking that it didn't change since it was registered.
+// Returns [execPath] if it isn't a registered alias.
+func (s *server) resolveExecPath(execPath string) (string, error) {
+	s.binariesMu.Lock()
+	binary, ok := s.binaries[execPath]
+	s.binariesMu.Unlock()
+	if !ok {
+		return execPath, nil
+	}
+	checksum, err := fileSHA256(binary.ExecPath)
+	if err != nil {
+		return "", fmt.Errorf("binary %q: %w", binary.Alias, err)
+	}
+	if checksum != binary.Sha256 {
+		return "", fmt.Errorf("%w: %q at %q has checksum %s, registered with %s", ErrBinaryChanged, binary.Alias, binary.ExecPath, checksum, binary.Sha256)
+	}
+	return binary.ExecPath, nil
+}
+
+// resolveNodeExecPaths resolves the aliases in the values of [nodeExecPaths]
+func (s *server) resolveNodeExecPaths(nodeExecPaths map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(nodeExecPaths))
+	for nodeName, execPath := range nodeExecPaths {
+		path, err := s.resolveExecPath(execPath)
+		if err != nil {
+			return nil, fmt.Errorf("node %q: %w", nodeName, err)
+		}
+		resolved[nodeName] = path
+	}
+	return resolved, nil
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 of file [path]
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
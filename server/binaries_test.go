@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinaryRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	execPath := filepath.Join(t.TempDir(), "axia")
+	script := "#!/bin/sh\necho 'axia/1.7.9 [database=v1.4.5, commit=abc]'\n"
+	assert.NoError(os.WriteFile(execPath, []byte(script), 0o755))
+
+	s := &server{binaries: make(map[string]*rpcpb.BinaryInfo)}
+	ctx := context.Background()
+
+	_, err := s.RegisterBinary(ctx, &rpcpb.RegisterBinaryRequest{ExecPath: execPath})
+	assert.True(errors.Is(err, ErrNoBinaryAlias))
+
+	resp, err := s.RegisterBinary(ctx, &rpcpb.RegisterBinaryRequest{Alias: "axia@1.7.9", ExecPath: execPath})
+	assert.NoError(err)
+	assert.Equal("axia/1.7.9", resp.Binary.AppVersion)
+	assert.Equal("v1.4.5", resp.Binary.DbVersion)
+	assert.Len(resp.Binary.Sha256, 64)
+
+	list, err := s.ListBinaries(ctx, &rpcpb.ListBinariesRequest{})
+	assert.NoError(err)
+	assert.Len(list.Binaries, 1)
+	assert.Equal("axia@1.7.9", list.Binaries[0].Alias)
+
+	// aliases resolve to their path, other exec paths are unchanged
+	resolved, err := s.resolveExecPath("axia@1.7.9")
+	assert.NoError(err)
+	assert.Equal(execPath, resolved)
+	resolved, err = s.resolveExecPath("/other/axia")
+	assert.NoError(err)
+	assert.Equal("/other/axia", resolved)
+
+	// a rebuilt binary is refused
+	assert.NoError(os.WriteFile(execPath, []byte(script+"\n"), 0o755))
+	_, err = s.resolveExecPath("axia@1.7.9")
+	assert.True(errors.Is(err, ErrBinaryChanged))
+}
@@ -0,0 +1,294 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia/ids"
+	"github.com/axiacoin/axia/indexer"
+	"github.com/axiacoin/axia/snow/engine/axia/vertex"
+	"github.com/axiacoin/axia/utils/hashing"
+	"github.com/axiacoin/axia/vms/platformvm"
+	"github.com/axiacoin/coreth/core/types"
+	"github.com/axiacoin/coreth/ethclient"
+	"github.com/axiacoin/coreth/interfaces"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+)
+
+const (
+	// interval between polls of the index of a node for new accepted containers
+	blockPollInterval = 500 * time.Millisecond
+	// endpoint of the index of the vertices accepted by the SwapChain
+	xChainVertexIndexEndpoint = "/ext/index/X/vtx"
+)
+
+// blockStreamer streams the blocks accepted by a chain on a node
+type blockStreamer interface {
+	// streamBlocks calls [send] with each block accepted from now on,
+	// until [ctx] is done or [send] fails
+	streamBlocks(ctx context.Context, send func(*rpcpb.AcceptedBlock) error) error
+}
+
+func (s *server) StreamBlocks(req *rpcpb.StreamBlocksRequest, stream rpcpb.ControlService_StreamBlocksServer) error {
+	zap.L().Info("received stream blocks request", zap.String("chain", req.Chain), zap.String("node-name", req.NodeName))
+	if info := s.getClusterInfo(); info == nil {
+		return ErrNotBootstrapped
+	}
+
+	s.mu.RLock()
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		s.mu.RUnlock()
+		return ErrNetworkNotReady
+	}
+	streamer, nodeName, closeStreamer, err := s.network.blockStreamer(req.Chain, req.NodeName)
+	stopCh := s.network.stopCh
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	defer closeStreamer()
+
+	// the stream ends with the network
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+		case <-s.closed:
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	zap.L().Info("streaming accepted blocks", zap.String("chain", req.Chain), zap.String("node-name", nodeName))
+	err = streamer.streamBlocks(ctx, func(blk *rpcpb.AcceptedBlock) error {
+		blk.Chain = req.Chain
+		blk.NodeName = nodeName
+		return stream.Send(&rpcpb.StreamBlocksResponse{Block: blk})
+	})
+	if errors.Is(err, context.Canceled) && stream.Context().Err() == nil {
+		zap.L().Info("network stopped, ending block stream", zap.String("chain", req.Chain))
+		return nil
+	}
+	return err
+}
+
+// blockStreamer returns a streamer of the blocks accepted by [chain] on node [nodeName],
+// or on the first node running [chain] if empty, with the name of the node
+// and a function closing the clients it uses
+func (lc *localNetwork) blockStreamer(chain string, nodeName string) (blockStreamer, string, func(), error) {
+	subnetID, blockchainID := "", ""
+	switch chain {
+	case "X", "P", "C":
+	default:
+		var ok bool
+		subnetID, blockchainID, ok = lc.customVMBlockchain(chain)
+		if !ok {
+			return nil, "", nil, fmt.Errorf("%w: no chain or custom VM blockchain %q", ErrInvalidChain, chain)
+		}
+	}
+
+	if nodeName == "" {
+		for _, name := range lc.nodeNames {
+			if subnetID == "" || tracksSubnet(lc.nodeInfos[name], subnetID) {
+				nodeName = name
+				break
+			}
+		}
+		if nodeName == "" {
+			return nil, "", nil, fmt.Errorf("%w: no node runs the blockchain of %q", ErrInvalidChain, chain)
+		}
+	}
+	nodeInfo, ok := lc.nodeInfos[nodeName]
+	if !ok {
+		return nil, "", nil, fmt.Errorf("%w: %q", ErrNodeNotFound, nodeName)
+	}
+	if subnetID != "" && !tracksSubnet(nodeInfo, subnetID) {
+		return nil, "", nil, fmt.Errorf("%w: node %q does not run the blockchain of %q", ErrInvalidChain, nodeName, chain)
+	}
+	nd, err := lc.nw.GetNode(nodeName)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	switch chain {
+	case "X":
+		client := indexer.NewClient(nodeInfo.Uri, xChainVertexIndexEndpoint)
+		return newIndexBlockStreamer(client, parseXChainVertex), nodeName, func() {}, nil
+	case "P":
+		return newIndexBlockStreamer(nd.GetAPIClient().PChainIndexAPI(), parsePChainBlock), nodeName, func() {}, nil
+	case "C":
+		// the client is closed with the node
+		return &evmBlockStreamer{client: nd.GetAPIClient().CChainEthAPI()}, nodeName, func() {}, nil
+	}
+	wsURI := strings.Replace(nodeInfo.Uri, "http://", "ws://", 1)
+	client, err := ethclient.Dial(fmt.Sprintf("%s/ext/bc/%s/ws", wsURI, blockchainID))
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return &evmBlockStreamer{client: client}, nodeName, client.Close, nil
+}
+
+// indexBlockStreamer streams the containers accepted by a chain by polling the index of a node
+type indexBlockStreamer struct {
+	// returns the index of the last accepted container, or an error if none
+	lastAcceptedIndex func(context.Context) (uint64, error)
+	// returns the block of the container accepted at an index
+	blockAt      func(context.Context, uint64) (*rpcpb.AcceptedBlock, error)
+	pollInterval time.Duration
+}
+
+func newIndexBlockStreamer(client indexer.Client, parse func(indexer.Container) (*rpcpb.AcceptedBlock, error)) *indexBlockStreamer {
+	return &indexBlockStreamer{
+		lastAcceptedIndex: func(ctx context.Context) (uint64, error) {
+			_, index, err := client.GetLastAccepted(ctx)
+			return index, err
+		},
+		blockAt: func(ctx context.Context, index uint64) (*rpcpb.AcceptedBlock, error) {
+			container, err := client.GetContainerByIndex(ctx, index)
+			if err != nil {
+				return nil, err
+			}
+			return parse(container)
+		},
+		pollInterval: blockPollInterval,
+	}
+}
+
+func (bs *indexBlockStreamer) streamBlocks(ctx context.Context, send func(*rpcpb.AcceptedBlock) error) error {
+	// index of the next container to send, the first one if none was accepted yet
+	next := uint64(0)
+	if index, err := bs.lastAcceptedIndex(ctx); err == nil {
+		next = index + 1
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(bs.pollInterval):
+		}
+		index, err := bs.lastAcceptedIndex(ctx)
+		if err != nil {
+			if next == 0 {
+				// still nothing accepted
+				continue
+			}
+			return err
+		}
+		for ; next <= index; next++ {
+			blk, err := bs.blockAt(ctx, next)
+			if err != nil {
+				return err
+			}
+			if err := send(blk); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parsePChainBlock returns the CoreChain block of [container]
+func parsePChainBlock(container indexer.Container) (*rpcpb.AcceptedBlock, error) {
+	var blk platformvm.Block
+	if _, err := platformvm.Codec.Unmarshal(container.Bytes, &blk); err != nil {
+		return nil, fmt.Errorf("failed to parse block %s: %w", container.ID, err)
+	}
+	var txs []*platformvm.Tx
+	switch blk := blk.(type) {
+	case *platformvm.StandardBlock:
+		txs = blk.Txs
+	case *platformvm.ProposalBlock:
+		txs = []*platformvm.Tx{&blk.Tx}
+	case *platformvm.AtomicBlock:
+		txs = []*platformvm.Tx{&blk.Tx}
+	}
+	txIDs := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		// sets the ID of the tx, as when the block is parsed by the VM
+		if err := tx.Sign(platformvm.Codec, nil); err != nil {
+			return nil, err
+		}
+		txIDs = append(txIDs, tx.ID().String())
+	}
+	return &rpcpb.AcceptedBlock{
+		Height:    blk.Height(),
+		BlockId:   container.ID.String(),
+		Timestamp: container.Timestamp,
+		NumTxs:    uint32(len(txIDs)),
+		TxIds:     txIDs,
+	}, nil
+}
+
+// parseXChainVertex returns the SwapChain vertex of [container]
+func parseXChainVertex(container indexer.Container) (*rpcpb.AcceptedBlock, error) {
+	vtx, err := vertex.Parse(container.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse vertex %s: %w", container.ID, err)
+	}
+	txIDs := make([]string, 0, len(vtx.Txs()))
+	for _, txBytes := range vtx.Txs() {
+		txIDs = append(txIDs, ids.ID(hashing.ComputeHash256Array(txBytes)).String())
+	}
+	return &rpcpb.AcceptedBlock{
+		Height:    vtx.Height(),
+		BlockId:   container.ID.String(),
+		Timestamp: container.Timestamp,
+		NumTxs:    uint32(len(txIDs)),
+		TxIds:     txIDs,
+	}, nil
+}
+
+// evmBlockClient is the subset of the AXChain and EVM subnet clients used to stream blocks
+type evmBlockClient interface {
+	SubscribeNewHead(context.Context, chan<- *types.Header) (interfaces.Subscription, error)
+	BlockByHash(context.Context, ethcommon.Hash) (*types.Block, error)
+}
+
+// evmBlockStreamer streams the blocks accepted by an EVM chain through a subscription to its new heads
+type evmBlockStreamer struct {
+	client evmBlockClient
+}
+
+func (bs *evmBlockStreamer) streamBlocks(ctx context.Context, send func(*rpcpb.AcceptedBlock) error) error {
+	headers := make(chan *types.Header)
+	sub, err := bs.client.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		case header := <-headers:
+			block, err := bs.client.BlockByHash(ctx, header.Hash())
+			if err != nil {
+				return err
+			}
+			txIDs := make([]string, 0, len(block.Transactions()))
+			for _, tx := range block.Transactions() {
+				txIDs = append(txIDs, tx.Hash().Hex())
+			}
+			blk := &rpcpb.AcceptedBlock{
+				Height:    block.NumberU64(),
+				BlockId:   block.Hash().Hex(),
+				Timestamp: int64(block.Time()) * int64(time.Second),
+				NumTxs:    uint32(len(txIDs)),
+				TxIds:     txIDs,
+			}
+			if err := send(blk); err != nil {
+				return err
+			}
+		}
+	}
+}
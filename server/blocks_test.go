@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeIndex is an index whose containers are accepted by calls to accept
+type fakeIndex struct {
+	mu       sync.Mutex
+	accepted int
+	// closed once the first call to lastAcceptedIndex read the index
+	polled     chan struct{}
+	polledOnce sync.Once
+}
+
+func newFakeIndex(accepted int) *fakeIndex {
+	return &fakeIndex{accepted: accepted, polled: make(chan struct{})}
+}
+
+func (fi *fakeIndex) accept(n int) {
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	fi.accepted += n
+}
+
+func (fi *fakeIndex) streamer() *indexBlockStreamer {
+	return &indexBlockStreamer{
+		lastAcceptedIndex: func(context.Context) (uint64, error) {
+			// signals once the index was read
+			defer fi.polledOnce.Do(func() { close(fi.polled) })
+			fi.mu.Lock()
+			defer fi.mu.Unlock()
+			if fi.accepted == 0 {
+				return 0, errors.New("no containers have been accepted")
+			}
+			return uint64(fi.accepted - 1), nil
+		},
+		blockAt: func(_ context.Context, index uint64) (*rpcpb.AcceptedBlock, error) {
+			return &rpcpb.AcceptedBlock{Height: index + 1, BlockId: fmt.Sprintf("block%d", index)}, nil
+		},
+		pollInterval: time.Millisecond,
+	}
+}
+
+func TestIndexBlockStreamer(t *testing.T) {
+	tests := []struct {
+		name     string
+		accepted int
+	}{
+		{name: "empty index", accepted: 0},
+		{name: "accepted containers", accepted: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			fi := newFakeIndex(tt.accepted)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			// the containers accepted before the stream are not sent
+			received := []*rpcpb.AcceptedBlock{}
+			errc := make(chan error, 1)
+			go func() {
+				errc <- fi.streamer().streamBlocks(ctx, func(blk *rpcpb.AcceptedBlock) error {
+					received = append(received, blk)
+					if len(received) == 3 {
+						return errors.New("done")
+					}
+					return nil
+				})
+			}()
+			// accepted once the stream started from the last accepted container
+			<-fi.polled
+			fi.accept(3)
+
+			assert.EqualError(<-errc, "done")
+			for i, blk := range received {
+				assert.EqualValues(tt.accepted+i+1, blk.Height)
+				assert.Equal(fmt.Sprintf("block%d", tt.accepted+i), blk.BlockId)
+			}
+		})
+	}
+}
+
+func TestIndexBlockStreamerCanceled(t *testing.T) {
+	fi := newFakeIndex(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := fi.streamer().streamBlocks(ctx, func(*rpcpb.AcceptedBlock) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
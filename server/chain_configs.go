@@ -0,0 +1,129 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/axiacoin/axia-network-runner/network/node"
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"go.uber.org/zap"
+)
+
+// checkNodeChainConfigs checks that [nodeChainConfigs] only has files for nodes of [nodeNames]
+func checkNodeChainConfigs(nodeChainConfigs map[string]*rpcpb.ChainConfigs, nodeNames []string) error {
+	nodes := make(map[string]struct{}, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		nodes[nodeName] = struct{}{}
+	}
+	for nodeName := range nodeChainConfigs {
+		if _, ok := nodes[nodeName]; !ok {
+			return fmt.Errorf("chain configs for %q: %w", nodeName, ErrNodeNotFound)
+		}
+	}
+	return nil
+}
+
+// mergeChainConfigs returns the union of the files of [a] and [b],
+// the files of [b] taking precedence
+func mergeChainConfigs(a *rpcpb.ChainConfigs, b *rpcpb.ChainConfigs) *rpcpb.ChainConfigs {
+	merge := func(a map[string]string, b map[string]string) map[string]string {
+		if len(a) == 0 && len(b) == 0 {
+			return nil
+		}
+		files := make(map[string]string, len(a)+len(b))
+		for k, contents := range a {
+			files[k] = contents
+		}
+		for k, contents := range b {
+			files[k] = contents
+		}
+		return files
+	}
+	return &rpcpb.ChainConfigs{
+		ChainConfigs:   merge(a.GetChainConfigs(), b.GetChainConfigs()),
+		UpgradeConfigs: merge(a.GetUpgradeConfigs(), b.GetUpgradeConfigs()),
+		SubnetConfigs:  merge(a.GetSubnetConfigs(), b.GetSubnetConfigs()),
+	}
+}
+
+// setNodeConfigFiles sets the chain config, upgrade and subnet config files of [nodeConfig]
+// to the ones given for all nodes and for the node, the latter taking precedence.
+// Files given by custom VM name are keyed by the ID of its blockchain or subnet,
+// and only set once it is created and if the subnet is in [whitelistedSubnets].
+// Returns true if the files of [nodeConfig] changed.
+func (lc *localNetwork) setNodeConfigFiles(nodeConfig *node.Config, whitelistedSubnets string) bool {
+	configs := mergeChainConfigs(lc.options.chainConfigs, lc.options.nodeChainConfigs[nodeConfig.Name])
+	chainConfigFiles := lc.resolveConfigFiles(configs.ChainConfigs, false, whitelistedSubnets)
+	upgradeConfigFiles := lc.resolveConfigFiles(configs.UpgradeConfigs, false, whitelistedSubnets)
+	subnetConfigFiles := lc.resolveConfigFiles(configs.SubnetConfigs, true, whitelistedSubnets)
+	if reflect.DeepEqual(chainConfigFiles, nodeConfig.ChainConfigFiles) &&
+		reflect.DeepEqual(upgradeConfigFiles, nodeConfig.UpgradeConfigFiles) &&
+		reflect.DeepEqual(subnetConfigFiles, nodeConfig.SubnetConfigFiles) {
+		return false
+	}
+	nodeConfig.ChainConfigFiles = chainConfigFiles
+	nodeConfig.UpgradeConfigFiles = upgradeConfigFiles
+	nodeConfig.SubnetConfigFiles = subnetConfigFiles
+	return true
+}
+
+// resolveConfigFiles returns [files] with the custom VM names replaced by the IDs
+// of their blockchains, or of their subnets if [bySubnet].
+// Skips the custom VMs not created yet, or whose subnet is not in [whitelistedSubnets].
+// Returns nil if there are no files left.
+func (lc *localNetwork) resolveConfigFiles(files map[string]string, bySubnet bool, whitelistedSubnets string) map[string]string {
+	vmNameToInfo := make(map[string]vmInfo)
+	for _, vmInfo := range lc.customVMIDToInfo {
+		vmNameToInfo[vmInfo.info.VmName] = vmInfo
+	}
+	resolved := make(map[string]string)
+	for k, contents := range files {
+		vmInfo, created := vmNameToInfo[k]
+		if _, ok := lc.customVMNameToGenesis[k]; !ok && !created {
+			// alias or ID
+			resolved[k] = contents
+			continue
+		}
+		if !created || !containsSubnet(whitelistedSubnets, vmInfo.info.SubnetId) {
+			continue
+		}
+		switch {
+		case bySubnet:
+			resolved[vmInfo.info.SubnetId] = contents
+		case vmInfo.info.BlockchainId != "":
+			resolved[vmInfo.info.BlockchainId] = contents
+		}
+	}
+	if len(resolved) == 0 {
+		return nil
+	}
+	return resolved
+}
+
+// restartNodesWithConfigFiles restarts the nodes whose chain config, upgrade
+// or subnet config files changed, e.g. once the custom VMs they are given for are created
+func (lc *localNetwork) restartNodesWithConfigFiles(ctx context.Context) error {
+	restartedNodeConfigs := []int{}
+	for i := range lc.cfg.NodeConfigs {
+		nodeName := lc.cfg.NodeConfigs[i].Name
+		whitelistedSubnets := lc.options.whitelistedSubnets
+		if nodeInfo, ok := lc.nodeInfos[nodeName]; ok {
+			whitelistedSubnets = nodeInfo.WhitelistedSubnets
+		}
+		if lc.setNodeConfigFiles(&lc.cfg.NodeConfigs[i], whitelistedSubnets) {
+			zap.L().Info("updating node config files", zap.String("node-name", nodeName))
+			restartedNodeConfigs = append(restartedNodeConfigs, i)
+		}
+	}
+	if len(restartedNodeConfigs) == 0 {
+		return nil
+	}
+	println()
+	color.Outf("{{green}}restarting nodes with updated chain config files{{/}}\n")
+	return lc.restartNodes(ctx, restartedNodeConfigs)
+}
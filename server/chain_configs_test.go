@@ -0,0 +1,89 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/axiacoin/axia-network-runner/network/node"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia/ids"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeChainConfigs(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(mergeChainConfigs(nil, nil).ChainConfigs)
+	a := &rpcpb.ChainConfigs{
+		ChainConfigs:  map[string]string{"C": "a C", "X": "a X"},
+		SubnetConfigs: map[string]string{"subnetevm": "a subnet"},
+	}
+	b := &rpcpb.ChainConfigs{
+		ChainConfigs:   map[string]string{"C": "b C"},
+		UpgradeConfigs: map[string]string{"subnetevm": "b upgrade"},
+	}
+	merged := mergeChainConfigs(a, b)
+	assert.Equal(map[string]string{"C": "b C", "X": "a X"}, merged.ChainConfigs)
+	assert.Equal(map[string]string{"subnetevm": "b upgrade"}, merged.UpgradeConfigs)
+	assert.Equal(map[string]string{"subnetevm": "a subnet"}, merged.SubnetConfigs)
+	// the inputs are not modified
+	assert.Equal(map[string]string{"C": "a C", "X": "a X"}, a.ChainConfigs)
+}
+
+func TestCheckNodeChainConfigs(t *testing.T) {
+	assert := assert.New(t)
+
+	nodeNames := []string{"node1", "node2"}
+	assert.NoError(checkNodeChainConfigs(nil, nodeNames))
+	assert.NoError(checkNodeChainConfigs(map[string]*rpcpb.ChainConfigs{"node2": {}}, nodeNames))
+	err := checkNodeChainConfigs(map[string]*rpcpb.ChainConfigs{"node3": {}}, nodeNames)
+	assert.True(errors.Is(err, ErrNodeNotFound))
+}
+
+func TestSetNodeConfigFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	vmID := ids.GenerateTestID()
+	subnetID := ids.GenerateTestID().String()
+	lc := &localNetwork{
+		options: localNetworkOptions{
+			chainConfigs: &rpcpb.ChainConfigs{
+				ChainConfigs:  map[string]string{"C": "C config", "subnetevm": "subnetevm config"},
+				SubnetConfigs: map[string]string{"subnetevm": "subnetevm subnet config"},
+			},
+			nodeChainConfigs: map[string]*rpcpb.ChainConfigs{
+				"node2": {ChainConfigs: map[string]string{"subnetevm": "node2 subnetevm config"}},
+			},
+		},
+		customVMNameToGenesis: map[string][]byte{"subnetevm": nil},
+		customVMIDToInfo:      map[ids.ID]vmInfo{},
+	}
+
+	// the custom VM is not created yet
+	nodeConfig := &node.Config{Name: "node1"}
+	assert.True(lc.setNodeConfigFiles(nodeConfig, ""))
+	assert.Equal(map[string]string{"C": "C config"}, nodeConfig.ChainConfigFiles)
+	assert.Nil(nodeConfig.UpgradeConfigFiles)
+	assert.Nil(nodeConfig.SubnetConfigFiles)
+	assert.False(lc.setNodeConfigFiles(nodeConfig, ""))
+
+	// the subnet is created
+	lc.customVMIDToInfo[vmID] = vmInfo{
+		info: &rpcpb.CustomVmInfo{VmName: "subnetevm", VmId: vmID.String(), SubnetId: subnetID},
+	}
+	assert.False(lc.setNodeConfigFiles(nodeConfig, ""))
+	assert.True(lc.setNodeConfigFiles(nodeConfig, subnetID))
+	assert.Equal(map[string]string{"C": "C config"}, nodeConfig.ChainConfigFiles)
+	assert.Equal(map[string]string{subnetID: "subnetevm subnet config"}, nodeConfig.SubnetConfigFiles)
+
+	// the blockchain is created
+	blockchainID := ids.GenerateTestID().String()
+	lc.customVMIDToInfo[vmID].info.BlockchainId = blockchainID
+	assert.True(lc.setNodeConfigFiles(nodeConfig, subnetID))
+	assert.Equal(map[string]string{"C": "C config", blockchainID: "subnetevm config"}, nodeConfig.ChainConfigFiles)
+
+	// the node files take precedence
+	nodeConfig = &node.Config{Name: "node2"}
+	assert.True(lc.setNodeConfigFiles(nodeConfig, subnetID))
+	assert.Equal(map[string]string{"C": "C config", blockchainID: "node2 subnetevm config"}, nodeConfig.ChainConfigFiles)
+}
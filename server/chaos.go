@@ -0,0 +1,446 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/network/node"
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia/ids"
+	"github.com/axiacoin/axia/utils/constants"
+	"go.uber.org/zap"
+)
+
+const (
+	chaosFaultKill    = "kill"
+	chaosFaultRestart = "restart"
+	chaosFaultPause   = "pause"
+	chaosFaultRemove  = "remove"
+	chaosRecover      = "recover"
+	chaosSkip         = "skip"
+
+	defaultChaosInterval     = 30 * time.Second
+	defaultChaosDownDuration = 10 * time.Second
+	// timeout to check the validators health before a fault
+	chaosCheckTimeout = 30 * time.Second
+	// timeout to bring a faulted node back and wait for the network health
+	chaosRecoverTimeout = 2 * time.Minute
+)
+
+// faults in the order of their weights in the schedule
+var chaosFaults = []string{chaosFaultKill, chaosFaultRestart, chaosFaultPause, chaosFaultRemove}
+
+// chaosController injects faults into the non-beacon nodes, one at a time,
+// following a schedule drawn from its seed
+type chaosController struct {
+	seed         int64
+	rng          *rand.Rand
+	interval     time.Duration
+	downDuration time.Duration
+	// aligned with [chaosFaults]
+	faultWeights         []uint32
+	minHealthyValidators uint32
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+
+	actionsMu sync.Mutex
+	actions   []*rpcpb.ChaosAction
+}
+
+func newChaosController(req *rpcpb.EnableChaosRequest) *chaosController {
+	seed := req.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	interval := time.Duration(req.Interval)
+	if interval <= 0 {
+		interval = defaultChaosInterval
+	}
+	downDuration := time.Duration(req.DownDuration)
+	if downDuration <= 0 {
+		downDuration = defaultChaosDownDuration
+	}
+	mix := req.GetFaultMix()
+	return &chaosController{
+		seed:                 seed,
+		rng:                  rand.New(rand.NewSource(seed)),
+		interval:             interval,
+		downDuration:         downDuration,
+		faultWeights:         []uint32{mix.GetKill(), mix.GetRestart(), mix.GetPause(), mix.GetRemove()},
+		minHealthyValidators: req.MinHealthyValidators,
+		doneCh:               make(chan struct{}),
+	}
+}
+
+// pickFault draws a fault according to the weights,
+// all faults being equally likely if all weights are zero
+func (cc *chaosController) pickFault() string {
+	var total uint64
+	for _, weight := range cc.faultWeights {
+		total += uint64(weight)
+	}
+	if total == 0 {
+		return chaosFaults[cc.rng.Intn(len(chaosFaults))]
+	}
+	n := uint64(cc.rng.Int63n(int64(total)))
+	for i, weight := range cc.faultWeights {
+		if n < uint64(weight) {
+			return chaosFaults[i]
+		}
+		n -= uint64(weight)
+	}
+	return chaosFaults[len(chaosFaults)-1]
+}
+
+// record logs an action with its timestamp, so that a failing run can be replayed with the same seed
+func (cc *chaosController) record(nodeName string, fault string, err error) {
+	action := &rpcpb.ChaosAction{
+		Timestamp: time.Now().UnixNano(),
+		NodeName:  nodeName,
+		Fault:     fault,
+	}
+	if err != nil {
+		action.Error = err.Error()
+	}
+	zap.L().Info("chaos action",
+		zap.Int64("seed", cc.seed),
+		zap.Time("timestamp", time.Unix(0, action.Timestamp)),
+		zap.String("node-name", nodeName),
+		zap.String("fault", fault),
+		zap.Error(err),
+	)
+	cc.actionsMu.Lock()
+	cc.actions = append(cc.actions, action)
+	cc.actionsMu.Unlock()
+}
+
+func (cc *chaosController) getActions() []*rpcpb.ChaosAction {
+	cc.actionsMu.Lock()
+	defer cc.actionsMu.Unlock()
+	return append([]*rpcpb.ChaosAction{}, cc.actions...)
+}
+
+func (cc *chaosController) running() bool {
+	select {
+	case <-cc.doneCh:
+		return false
+	default:
+		return true
+	}
+}
+
+func (s *server) EnableChaos(ctx context.Context, req *rpcpb.EnableChaosRequest) (*rpcpb.EnableChaosResponse, error) {
+	zap.L().Info("received enable chaos request", zap.Int64("seed", req.Seed))
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil {
+		return nil, ErrNetworkNotReady
+	}
+	// the faults would fail the restarts of the install
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	if s.chaos != nil && s.chaos.running() {
+		return nil, ErrChaosEnabled
+	}
+	targets := s.network.getChaosTargets()
+	if len(targets) == 0 {
+		return nil, ErrNoChaosTargets
+	}
+
+	cc := newChaosController(req)
+	// the run outlives this request
+	var chaosCtx context.Context
+	if req.Duration > 0 {
+		chaosCtx, cc.cancel = context.WithTimeout(context.Background(), time.Duration(req.Duration))
+	} else {
+		chaosCtx, cc.cancel = context.WithCancel(context.Background())
+	}
+	s.chaos = cc
+	go s.runChaos(chaosCtx, cc, s.network)
+
+	return &rpcpb.EnableChaosResponse{Seed: cc.seed, NodeNames: targets}, nil
+}
+
+func (s *server) DisableChaos(ctx context.Context, req *rpcpb.DisableChaosRequest) (*rpcpb.DisableChaosResponse, error) {
+	zap.L().Info("received disable chaos request")
+
+	s.mu.RLock()
+	cc := s.chaos
+	s.mu.RUnlock()
+	if cc == nil {
+		return nil, ErrChaosNotEnabled
+	}
+
+	// a faulted node is recovered before the run ends
+	cc.cancel()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-cc.doneCh:
+	}
+
+	s.mu.Lock()
+	if s.chaos == cc {
+		s.chaos = nil
+	}
+	s.mu.Unlock()
+
+	return &rpcpb.DisableChaosResponse{Seed: cc.seed, Actions: cc.getActions()}, nil
+}
+
+// runChaos injects a fault into network [lc] every interval, until [ctx] is done
+// or [lc] is stopped
+func (s *server) runChaos(ctx context.Context, cc *chaosController, lc *localNetwork) {
+	defer close(cc.doneCh)
+	color.Outf("{{magenta}}{{bold}}chaos enabled with seed %d{{/}}\n", cc.seed)
+	defer color.Outf("{{magenta}}{{bold}}chaos with seed %d done{{/}}\n", cc.seed)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-lc.stopCh:
+			return
+		case <-time.After(cc.interval):
+		}
+
+		// draw before checking the network, so that a replay with the same seed
+		// draws the same schedule whatever the state of the nodes
+		fault := cc.pickFault()
+		targetIndex := cc.rng.Int()
+
+		s.mu.Lock()
+		if s.network != lc {
+			s.mu.Unlock()
+			return
+		}
+		targets := lc.getChaosTargets()
+		if len(targets) == 0 {
+			s.mu.Unlock()
+			cc.record("", chaosSkip, ErrNoChaosTargets)
+			continue
+		}
+		nodeName := targets[targetIndex%len(targets)]
+		nodes, err := lc.nw.GetAllNodes()
+		s.mu.Unlock()
+		if err != nil {
+			cc.record(nodeName, chaosSkip, err)
+			continue
+		}
+
+		// without holding the lock, so that the network can be queried meanwhile
+		cctx, cancel := context.WithTimeout(ctx, chaosCheckTimeout)
+		err = checkHealthyValidators(cctx, nodes, nodeName, cc.minHealthyValidators)
+		cancel()
+		if err != nil {
+			cc.record(nodeName, chaosSkip, err)
+			continue
+		}
+
+		s.mu.Lock()
+		// stopped or disabled during the check
+		if s.network != lc || ctx.Err() != nil {
+			s.mu.Unlock()
+			return
+		}
+		recoverFunc, err := s.injectFault(lc, nodeName, fault)
+		s.mu.Unlock()
+		if err == nil && fault == chaosFaultRestart {
+			// without holding the lock, so that the network can be queried meanwhile
+			err = s.waitForChaosRecovery(ctx, lc)
+		}
+		cc.record(nodeName, fault, err)
+		if err != nil || recoverFunc == nil {
+			continue
+		}
+
+		// stay down, unless chaos is disabled
+		select {
+		case <-ctx.Done():
+		case <-lc.stopCh:
+		case <-time.After(cc.downDuration):
+		}
+		rctx, cancel := context.WithTimeout(context.Background(), chaosRecoverTimeout)
+		err = recoverFunc(rctx)
+		cancel()
+		cc.record(nodeName, chaosRecover, err)
+	}
+}
+
+// injectFault injects [fault] into node [nodeName], and returns the function to recover it, if any.
+// A restarted node is not waited for.
+// Assumes [s.mu] is held.
+func (s *server) injectFault(lc *localNetwork, nodeName string, fault string) (func(context.Context) error, error) {
+	nd, err := lc.nw.GetNode(nodeName)
+	if err != nil {
+		return nil, err
+	}
+	nodeConfig, ok := lc.getNodeConfig(nodeName)
+	if !ok {
+		return nil, fmt.Errorf("%w: no config for node %q", ErrNodeNotFound, nodeName)
+	}
+	pid := nd.GetPID()
+
+	switch fault {
+	case chaosFaultKill:
+		if err := syscall.Kill(pid, syscall.SIGKILL); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) error {
+			// the killed node is still registered, with its exit status
+			return s.readdChaosNode(ctx, lc, nodeConfig, true)
+		}, nil
+	case chaosFaultRestart:
+		if err := lc.nw.RemoveNode(nodeName); err != nil {
+			return nil, err
+		}
+		if _, err := lc.nw.AddNode(nodeConfig); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	case chaosFaultPause:
+		if err := syscall.Kill(pid, syscall.SIGSTOP); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) error {
+			// doesn't need the lock, so that a paused node
+			// can be resumed while the network is being stopped
+			return syscall.Kill(pid, syscall.SIGCONT)
+		}, nil
+	case chaosFaultRemove:
+		if err := lc.nw.RemoveNode(nodeName); err != nil {
+			return nil, err
+		}
+		return func(ctx context.Context) error {
+			return s.readdChaosNode(ctx, lc, nodeConfig, false)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown fault %q", fault)
+	}
+}
+
+// readdChaosNode adds back a killed or removed node, and waits for the network to recover
+func (s *server) readdChaosNode(ctx context.Context, lc *localNetwork, nodeConfig node.Config, killed bool) error {
+	if err := s.addChaosNode(lc, nodeConfig, killed); err != nil {
+		return err
+	}
+	return s.waitForChaosRecovery(ctx, lc)
+}
+
+// addChaosNode adds back a killed or removed node to network [lc], if still running
+func (s *server) addChaosNode(lc *localNetwork, nodeConfig node.Config, killed bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network != lc {
+		return errAborted
+	}
+	if killed {
+		// reports the kill, the node is removed anyway
+		if err := lc.nw.RemoveNode(nodeConfig.Name); err != nil {
+			zap.L().Debug("removed killed node", zap.String("node-name", nodeConfig.Name), zap.Error(err))
+		}
+	}
+	_, err := lc.nw.AddNode(nodeConfig)
+	return err
+}
+
+// waitForChaosRecovery waits for the network to be healthy without holding [s.mu],
+// and updates the cluster info with the new node URIs.
+func (s *server) waitForChaosRecovery(ctx context.Context, lc *localNetwork) error {
+	rctx, cancel := context.WithTimeout(ctx, chaosRecoverTimeout)
+	defer cancel()
+	if err := lc.nw.Healthy(rctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// stopped while waiting
+	if s.network != lc || s.clusterInfo == nil {
+		return errAborted
+	}
+	if err := lc.updateNodeInfo(rctx); err != nil {
+		return err
+	}
+	s.clusterInfo.NodeNames = lc.nodeNames
+	s.clusterInfo.NodeInfos = lc.nodeInfos
+	return nil
+}
+
+// getChaosTargets returns the sorted names of the running non-beacon nodes
+func (lc *localNetwork) getChaosTargets() []string {
+	nodes, err := lc.nw.GetAllNodes()
+	if err != nil {
+		return nil
+	}
+	targets := []string{}
+	for _, nodeConfig := range lc.cfg.NodeConfigs {
+		if _, ok := nodes[nodeConfig.Name]; ok && !nodeConfig.IsBeacon {
+			targets = append(targets, nodeConfig.Name)
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// checkHealthyValidators returns an error if faulting node [nodeName] of [nodes]
+// would leave less than [minHealthyValidators] healthy primary network validators
+func checkHealthyValidators(ctx context.Context, nodes map[string]node.Node, nodeName string, minHealthyValidators uint32) error {
+	if minHealthyValidators == 0 {
+		return nil
+	}
+	nodeNames := make([]string, 0, len(nodes))
+	for name := range nodes {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+
+	// ask the first node that answers
+	validatorIDs := make(map[ids.NodeID]struct{})
+	for _, name := range nodeNames {
+		vs, err := nodes[name].GetAPIClient().PChainAPI().GetCurrentValidators(ctx, constants.PrimaryNetworkID, nil)
+		if err != nil {
+			continue
+		}
+		for _, v := range vs {
+			validatorIDs[v.NodeID] = struct{}{}
+		}
+		break
+	}
+
+	var healthyValidators uint32
+	for _, name := range nodeNames {
+		if name == nodeName {
+			continue
+		}
+		nd := nodes[name]
+		if _, ok := validatorIDs[nd.GetNodeID()]; !ok {
+			continue
+		}
+		health, err := nd.GetAPIClient().HealthAPI().Health(ctx)
+		if err == nil && health.Healthy {
+			healthyValidators++
+		}
+	}
+	if healthyValidators < minHealthyValidators {
+		return fmt.Errorf("%w: %d other healthy validators, %d required", ErrNotEnoughHealthyValidators, healthyValidators, minHealthyValidators)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaosPickFault(t *testing.T) {
+	assert := assert.New(t)
+
+	// same seed, same schedule
+	req := &rpcpb.EnableChaosRequest{Seed: 42}
+	cc1, cc2 := newChaosController(req), newChaosController(req)
+	picked := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		fault := cc1.pickFault()
+		assert.Equal(fault, cc2.pickFault())
+		picked[fault]++
+	}
+	// all faults are picked if no weights are given
+	for _, fault := range chaosFaults {
+		assert.NotZero(picked[fault], fault)
+	}
+	assert.Equal(defaultChaosInterval, cc1.interval)
+	assert.Equal(defaultChaosDownDuration, cc1.downDuration)
+
+	// faults with no weight are never picked
+	cc := newChaosController(&rpcpb.EnableChaosRequest{
+		Seed:     42,
+		FaultMix: &rpcpb.ChaosFaultMix{Kill: 1, Pause: 3},
+		Interval: 1,
+		Duration: 1,
+	})
+	picked = make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		picked[cc.pickFault()]++
+	}
+	assert.Zero(picked[chaosFaultRestart])
+	assert.Zero(picked[chaosFaultRemove])
+	assert.Greater(picked[chaosFaultPause], picked[chaosFaultKill])
+
+	// a random seed is drawn if not given
+	assert.NotZero(newChaosController(&rpcpb.EnableChaosRequest{}).seed)
+}
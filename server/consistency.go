@@ -0,0 +1,248 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/api"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultConsistencyDepth  = 10
+	defaultConsistencyMaxLag = 5
+	// timeout of each query to a node
+	consistencyQueryTimeout = 10 * time.Second
+)
+
+// chainReader reads the accepted blocks of a linear chain on a node
+type chainReader interface {
+	lastAcceptedHeight(ctx context.Context) (uint64, error)
+	// blockID returns the ID of the block accepted at [height]
+	blockID(ctx context.Context, height uint64) (string, error)
+}
+
+// pChainReader reads the CoreChain, through the index of the node for the block IDs
+type pChainReader struct {
+	apiClient api.Client
+}
+
+func (r pChainReader) lastAcceptedHeight(ctx context.Context) (uint64, error) {
+	return r.apiClient.PChainAPI().GetHeight(ctx)
+}
+
+func (r pChainReader) blockID(ctx context.Context, height uint64) (string, error) {
+	// the genesis block is not indexed
+	container, err := r.apiClient.PChainIndexAPI().GetContainerByIndex(ctx, height-1)
+	if err != nil {
+		return "", err
+	}
+	return container.ID.String(), nil
+}
+
+// cChainReader reads the AXChain
+type cChainReader struct {
+	apiClient api.Client
+}
+
+func (r cChainReader) lastAcceptedHeight(ctx context.Context) (uint64, error) {
+	return r.apiClient.CChainEthAPI().BlockNumber(ctx)
+}
+
+func (r cChainReader) blockID(ctx context.Context, height uint64) (string, error) {
+	block, err := r.apiClient.CChainEthAPI().BlockByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return "", err
+	}
+	return block.Hash().Hex(), nil
+}
+
+func (s *server) CheckConsistency(ctx context.Context, req *rpcpb.CheckConsistencyRequest) (*rpcpb.CheckConsistencyResponse, error) {
+	zap.L().Info("received check consistency request", zap.Uint32("depth", req.Depth), zap.Uint64("max-lag", req.MaxLag))
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	// the readers are set up under the lock, and query the nodes without holding it
+	s.mu.RLock()
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		s.mu.RUnlock()
+		return nil, ErrNetworkNotReady
+	}
+	nodes, err := s.network.nw.GetAllNodes()
+	if err != nil {
+		s.mu.RUnlock()
+		return nil, err
+	}
+	chains := []string{"P", "C"}
+	readers := map[string]map[string]chainReader{
+		"P": make(map[string]chainReader, len(nodes)),
+		"C": make(map[string]chainReader, len(nodes)),
+	}
+	for name, nd := range nodes {
+		readers["P"][name] = pChainReader{apiClient: nd.GetAPIClient()}
+		readers["C"][name] = cChainReader{apiClient: nd.GetAPIClient()}
+	}
+	s.mu.RUnlock()
+
+	depth := req.Depth
+	if depth == 0 {
+		depth = defaultConsistencyDepth
+	}
+	maxLag := req.MaxLag
+	if maxLag == 0 {
+		maxLag = defaultConsistencyMaxLag
+	}
+	resp := &rpcpb.CheckConsistencyResponse{
+		Chains: make([]*rpcpb.ChainConsistency, len(chains)),
+	}
+	var wg sync.WaitGroup
+	for i, chain := range chains {
+		wg.Add(1)
+		go func(i int, chain string) {
+			defer wg.Done()
+			resp.Chains[i] = checkChainConsistency(ctx, chain, readers[chain], depth, maxLag)
+		}(i, chain)
+	}
+	wg.Wait()
+	resp.ClusterInfo = s.getClusterInfo()
+	resp.Consistent = true
+	for _, chain := range resp.Chains {
+		if len(chain.Disagreements) > 0 || len(chain.LaggingNodes) > 0 || len(chain.Errors) > 0 {
+			resp.Consistent = false
+		}
+		zap.L().Info("checked chain consistency",
+			zap.String("chain", chain.Chain),
+			zap.Int("disagreements", len(chain.Disagreements)),
+			zap.Strings("lagging-nodes", chain.LaggingNodes),
+			zap.Int("errors", len(chain.Errors)),
+		)
+	}
+	return resp, nil
+}
+
+// checkChainConsistency compares the blocks accepted by the nodes of [readers] at the [depth] heights
+// down from the lowest last accepted height of the nodes, and reports the nodes more than [maxLag]
+// blocks behind the most advanced one.
+// A node that fails to answer is reported with its error, and skipped afterwards.
+func checkChainConsistency(ctx context.Context, chain string, readers map[string]chainReader, depth uint32, maxLag uint64) *rpcpb.ChainConsistency {
+	result := &rpcpb.ChainConsistency{
+		Chain:   chain,
+		Heights: make(map[string]uint64, len(readers)),
+		Errors:  make(map[string]string),
+	}
+	nodeNames := make([]string, 0, len(readers))
+	for name := range readers {
+		nodeNames = append(nodeNames, name)
+	}
+	sort.Strings(nodeNames)
+
+	var mu sync.Mutex
+	heights := make(map[string]uint64, len(nodeNames))
+	errs := queryNodes(ctx, nodeNames, func(ctx context.Context, name string) error {
+		height, err := readers[name].lastAcceptedHeight(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		heights[name] = height
+		mu.Unlock()
+		return nil
+	})
+	answering := make([]string, 0, len(nodeNames))
+	for _, name := range nodeNames {
+		if err, failed := errs[name]; failed {
+			result.Errors[name] = err.Error()
+			continue
+		}
+		result.Heights[name] = heights[name]
+		answering = append(answering, name)
+	}
+	if len(answering) == 0 {
+		return result
+	}
+
+	minHeight, maxHeight := result.Heights[answering[0]], result.Heights[answering[0]]
+	for _, name := range answering {
+		height := result.Heights[name]
+		if height < minHeight {
+			minHeight = height
+		}
+		if height > maxHeight {
+			maxHeight = height
+		}
+	}
+	for _, name := range answering {
+		if maxHeight-result.Heights[name] > maxLag {
+			result.LaggingNodes = append(result.LaggingNodes, name)
+		}
+	}
+
+	// the genesis block (height 0) is the same for all the nodes
+	for height := minHeight; height > 0 && minHeight-height < uint64(depth); height-- {
+		queried := make([]string, 0, len(answering))
+		for _, name := range answering {
+			if _, failed := result.Errors[name]; !failed {
+				queried = append(queried, name)
+			}
+		}
+		blockIDs := make(map[string]string, len(queried))
+		errs := queryNodes(ctx, queried, func(ctx context.Context, name string) error {
+			blockID, err := readers[name].blockID(ctx, height)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			blockIDs[name] = blockID
+			mu.Unlock()
+			return nil
+		})
+		for name, err := range errs {
+			result.Errors[name] = err.Error()
+		}
+		distinct := make(map[string]struct{})
+		for _, blockID := range blockIDs {
+			distinct[blockID] = struct{}{}
+		}
+		if len(distinct) > 1 {
+			result.Disagreements = append(result.Disagreements, &rpcpb.BlockDisagreement{
+				Height:   height,
+				BlockIds: blockIDs,
+			})
+		}
+	}
+	return result
+}
+
+// queryNodes calls [query] concurrently for each of [nodeNames], with a [consistencyQueryTimeout] timeout,
+// and returns the errors of the nodes that failed, by node name
+func queryNodes(ctx context.Context, nodeNames []string, query func(ctx context.Context, nodeName string) error) map[string]error {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs = make(map[string]error)
+	)
+	for _, name := range nodeNames {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, consistencyQueryTimeout)
+			err := query(cctx, name)
+			cancel()
+			if err != nil {
+				mu.Lock()
+				errs[name] = err
+				mu.Unlock()
+			}
+		}(name)
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeChainReader is a node that accepted [blockIDs] in order, from height 1
+type fakeChainReader struct {
+	blockIDs []string
+	err      error
+}
+
+func (r fakeChainReader) lastAcceptedHeight(context.Context) (uint64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return uint64(len(r.blockIDs)), nil
+}
+
+func (r fakeChainReader) blockID(_ context.Context, height uint64) (string, error) {
+	if height == 0 || height > uint64(len(r.blockIDs)) {
+		return "", fmt.Errorf("no block at height %d", height)
+	}
+	return r.blockIDs[height-1], nil
+}
+
+func testBlockIDs(prefix string, n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("%s%d", prefix, i+1)
+	}
+	return out
+}
+
+func TestCheckChainConsistency(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	// consistent, with a node a few blocks behind
+	result := checkChainConsistency(ctx, "C", map[string]chainReader{
+		"node1": fakeChainReader{blockIDs: testBlockIDs("b", 20)},
+		"node2": fakeChainReader{blockIDs: testBlockIDs("b", 20)},
+		"node3": fakeChainReader{blockIDs: testBlockIDs("b", 17)},
+	}, 10, 5)
+	assert.Equal("C", result.Chain)
+	assert.Equal(map[string]uint64{"node1": 20, "node2": 20, "node3": 17}, result.Heights)
+	assert.Empty(result.Disagreements)
+	assert.Empty(result.LaggingNodes)
+	assert.Empty(result.Errors)
+
+	// fork from height 15, lagging and failing nodes
+	forked := append(testBlockIDs("b", 14), testBlockIDs("f", 18)[14:]...)
+	result = checkChainConsistency(ctx, "P", map[string]chainReader{
+		"node1": fakeChainReader{blockIDs: testBlockIDs("b", 30)},
+		"node2": fakeChainReader{blockIDs: forked},
+		"node3": fakeChainReader{blockIDs: testBlockIDs("b", 24)},
+		"node4": fakeChainReader{err: errors.New("connection refused")},
+	}, 10, 5)
+	assert.Equal([]string{"node2", "node3"}, result.LaggingNodes)
+	assert.Equal(map[string]string{"node4": "connection refused"}, result.Errors)
+	// heights 18 down to 9 are checked, and the nodes disagree from 15
+	assert.Len(result.Disagreements, 4)
+	for i, d := range result.Disagreements {
+		height := uint64(18 - i)
+		assert.Equal(height, d.Height)
+		assert.Equal(map[string]string{
+			"node1": fmt.Sprintf("b%d", height),
+			"node2": fmt.Sprintf("f%d", height),
+			"node3": fmt.Sprintf("b%d", height),
+		}, d.BlockIds)
+	}
+
+	// a shallow chain is checked down to the first block
+	result = checkChainConsistency(ctx, "C", map[string]chainReader{
+		"node1": fakeChainReader{blockIDs: testBlockIDs("b", 3)},
+		"node2": fakeChainReader{blockIDs: testBlockIDs("c", 3)},
+	}, 10, 5)
+	assert.Len(result.Disagreements, 3)
+	assert.EqualValues(1, result.Disagreements[2].Height)
+
+	// no node answers
+	result = checkChainConsistency(ctx, "C", map[string]chainReader{
+		"node1": fakeChainReader{err: errors.New("timeout")},
+	}, 10, 5)
+	assert.Empty(result.Heights)
+	assert.Len(result.Errors, 1)
+}
@@ -4,10 +4,12 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"net/http"
 	"sort"
 	"strings"
 	"time"
@@ -16,7 +18,6 @@ import (
 	"github.com/axiacoin/axia-network-runner/rpcpb"
 	"github.com/axiacoin/axia-network-runner/utils"
 	"github.com/axiacoin/axia/config"
-	"github.com/axiacoin/axia/genesis"
 	"github.com/axiacoin/axia/ids"
 	"github.com/axiacoin/axia/utils/constants"
 	"github.com/axiacoin/axia/utils/units"
@@ -27,9 +28,14 @@ import (
 	"go.uber.org/zap"
 )
 
-// provisions local cluster and install custom VMs if applicable
+// provisions local cluster and install custom VMs [customVMs] (VM name to genesis bytes),
+// validated and tracked by the nodes in [customVMValidators] if any
 // assumes the local cluster is already set up and healthy
-func (lc *localNetwork) installCustomVMs(ctx context.Context) error {
+func (lc *localNetwork) installCustomVMs(
+	ctx context.Context,
+	customVMs map[string][]byte,
+	customVMValidators map[string]*rpcpb.CustomVmValidators,
+) error {
 	println()
 	color.Outf("{{blue}}{{bold}}create and install custom VMs{{/}}\n")
 
@@ -40,14 +46,15 @@ func (lc *localNetwork) installCustomVMs(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	validatorIDs, err := lc.checkValidators(ctx, platformCli, baseWallet, testKeyAddr)
-	if err != nil {
+	// all nodes validate the primary network, to be able to validate any subnet
+	if _, err := lc.checkValidators(ctx, platformCli, baseWallet, testKeyAddr); err != nil {
 		return err
 	}
-	if err = lc.createSubnets(ctx, baseWallet, testKeyAddr); err != nil {
+	vmIDs, err := lc.createSubnets(ctx, baseWallet, testKeyAddr, customVMs)
+	if err != nil {
 		return err
 	}
-	if err = lc.restartNodesWithWhitelistedSubnets(ctx); err != nil {
+	if err = lc.restartNodesWithWhitelistedSubnets(ctx, vmIDs, customVMValidators); err != nil {
 		return err
 	}
 
@@ -60,12 +67,17 @@ func (lc *localNetwork) installCustomVMs(ctx context.Context) error {
 		zap.String("address", testKeyAddr.String()),
 	)
 
-	if err = lc.addSubnetValidators(ctx, baseWallet, validatorIDs); err != nil {
+	if err = lc.addSubnetValidators(ctx, baseWallet, vmIDs, customVMValidators); err != nil {
 		return err
 	}
-	if err = lc.createBlockchains(ctx, baseWallet, testKeyAddr); err != nil {
+	if err = lc.createBlockchains(ctx, baseWallet, vmIDs, customVMs); err != nil {
 		return err
 	}
+	// the blockchain IDs are only known now
+	if err = lc.restartNodesWithConfigFiles(ctx); err != nil {
+		return err
+	}
+	baseWallet.refresh(lc.nodeInfos[lc.nodeNames[0]].Uri)
 
 	println()
 	color.Outf("{{green}}checking the remaining balance of the base wallet{{/}}\n")
@@ -81,49 +93,31 @@ func (lc *localNetwork) installCustomVMs(ctx context.Context) error {
 	return nil
 }
 
+// waitForCustomVMsReady waits for the blockchain of each custom VM to be ready
+// on all the nodes tracking its subnet, recording the readiness of each node in the VM info,
+// for at most [customVMsReadyTimeout]
 func (lc *localNetwork) waitForCustomVMsReady(ctx context.Context) error {
 	println()
 	color.Outf("{{blue}}{{bold}}waiting for custom VMs to report healthy...{{/}}\n")
 
-	if err := lc.nw.Healthy(ctx); err != nil {
-		return err
-	}
-
-	for nodeName, nodeInfo := range lc.nodeInfos {
-		zap.L().Info("inspecting node log directory for custom VM logs",
-			zap.String("node-name", nodeName),
-			zap.String("log-dir", nodeInfo.LogDir),
-		)
-		for _, vmInfo := range lc.customVMIDToInfo {
-			p := filepath.Join(nodeInfo.LogDir, vmInfo.info.BlockchainId+".log")
-			zap.L().Info("checking log",
-				zap.String("vm-id", vmInfo.info.VmId),
-				zap.String("subnet-id", vmInfo.info.SubnetId),
-				zap.String("blockchain-id", vmInfo.info.BlockchainId),
-				zap.String("log-path", p),
-			)
-			for {
-				_, err := os.Stat(p)
-				if err == nil {
-					zap.L().Info("found the log", zap.String("log-path", p))
-					break
-				}
-
-				zap.L().Info("log not found yet, retrying...",
-					zap.String("vm-id", vmInfo.info.VmId),
-					zap.String("subnet-id", vmInfo.info.SubnetId),
-					zap.String("blockchain-id", vmInfo.info.BlockchainId),
-					zap.String("log-path", p),
-					zap.Error(err),
-				)
-				select {
-				case <-lc.stopCh:
-					return errAborted
-				case <-ctx.Done():
-					return ctx.Err()
-				case <-time.After(10 * time.Second):
-				}
+	cctx, cancel := context.WithTimeout(ctx, customVMsReadyTimeout)
+	defer cancel()
+	for {
+		// the checks are not bound to the deadline, to report why the last ones failed
+		notReady := lc.checkCustomVMsReady(ctx)
+		if len(notReady) == 0 {
+			break
+		}
+		zap.L().Info("custom VMs not ready yet, retrying...", zap.Strings("vm-names", notReady))
+		select {
+		case <-lc.stopCh:
+			return errAborted
+		case <-cctx.Done():
+			if ctx.Err() != nil {
+				return ctx.Err()
 			}
+			return fmt.Errorf("%w after %v: %s (see their node statuses)", ErrCustomVMsNotReady, customVMsReadyTimeout, strings.Join(notReady, ", "))
+		case <-time.After(customVMsReadyPollInterval):
 		}
 	}
 
@@ -132,6 +126,9 @@ func (lc *localNetwork) waitForCustomVMsReady(ctx context.Context) error {
 	for _, nodeName := range lc.nodeNames {
 		nodeInfo := lc.nodeInfos[nodeName]
 		for vmID, vmInfo := range lc.customVMIDToInfo {
+			if !tracksSubnet(nodeInfo, vmInfo.info.SubnetId) {
+				continue
+			}
 			color.Outf("{{blue}}{{bold}}[blockchain RPC for %q] \"%s/ext/bc/%s\"{{/}}\n", vmID, nodeInfo.GetUri(), vmInfo.blockchainID.String())
 		}
 	}
@@ -146,9 +143,9 @@ func (lc *localNetwork) waitForCustomVMsReady(ctx context.Context) error {
 
 func (lc *localNetwork) setupWallet(ctx context.Context, httpRPCEp string) (baseWallet *refreshableWallet, axcAssetID ids.ID, testKeyAddr ids.ShortID, err error) {
 	// "local/default/genesis.json" pre-funds "ewoq" key
-	testKey := genesis.EWOQKey
+	testKey := prefundedKey
 	testKeyAddr = testKey.PublicKey().Address()
-	testKeychain := secp256k1fx.NewKeychain(genesis.EWOQKey)
+	testKeychain := secp256k1fx.NewKeychain(testKey)
 
 	println()
 	color.Outf("{{green}}setting up the base wallet with the seed test key{{/}}\n")
@@ -220,23 +217,17 @@ func (lc *localNetwork) checkValidators(ctx context.Context, platformCli platfor
 			zap.String("node-name", nodeName),
 			zap.String("node-id", nodeID.String()),
 		)
-		cctx, cancel = createDefaultCtx(ctx)
-		txID, err := baseWallet.P().IssueAddValidatorTx(
-			&validator.Validator{
-				NodeID: nodeID,
-				Start:  uint64(time.Now().Add(10 * time.Second).Unix()),
-				End:    uint64(time.Now().Add(300 * time.Hour).Unix()),
-				Wght:   1 * units.Axc,
-			},
-			&secp256k1fx.OutputOwners{
-				Threshold: 1,
-				Addrs:     []ids.ShortID{testKeyAddr},
-			},
-			10*10000, // 10% fee percent, times 10000 to make it as shares
-			common.WithContext(cctx),
-			defaultPoll,
+		start := time.Now().Add(10 * time.Second)
+		txID, err := lc.addValidator(
+			ctx,
+			baseWallet,
+			nodeID,
+			defaultValidatorStake,
+			start,
+			start.Add(defaultValidatorDuration),
+			defaultValidatorDelegationFee,
+			testKeyAddr,
 		)
-		cancel()
 		if err != nil {
 			return nil, err
 		}
@@ -249,13 +240,16 @@ func (lc *localNetwork) checkValidators(ctx context.Context, platformCli platfor
 	return validatorIDs, nil
 }
 
-func (lc *localNetwork) createSubnets(ctx context.Context, baseWallet *refreshableWallet, testKeyAddr ids.ShortID) error {
+// createSubnets creates a subnet for each of [customVMs],
+// and returns the IDs of their VMs
+func (lc *localNetwork) createSubnets(ctx context.Context, baseWallet *refreshableWallet, testKeyAddr ids.ShortID, customVMs map[string][]byte) ([]ids.ID, error) {
 	println()
 	color.Outf("{{green}}creating subnet for each custom VM{{/}}\n")
-	for vmName := range lc.customVMNameToGenesis {
+	vmIDs := make([]ids.ID, 0, len(customVMs))
+	for vmName := range customVMs {
 		vmID, err := utils.VMID(vmName)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		zap.L().Info("creating subnet tx",
 			zap.String("vm-name", vmName),
@@ -272,7 +266,7 @@ func (lc *localNetwork) createSubnets(ctx context.Context, baseWallet *refreshab
 		)
 		cancel()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		zap.L().Info("created subnet tx",
 			zap.String("vm-name", vmName),
@@ -288,23 +282,49 @@ func (lc *localNetwork) createSubnets(ctx context.Context, baseWallet *refreshab
 			},
 			subnetID: subnetID,
 		}
+		vmIDs = append(vmIDs, vmID)
 	}
-	return nil
+	return vmIDs, nil
 }
 
 // TODO: make this "restart" pattern more generic, so it can be used for "Restart" RPC
-func (lc *localNetwork) restartNodesWithWhitelistedSubnets(ctx context.Context) (err error) {
+// restartNodesWithWhitelistedSubnets restarts the nodes tracking the subnets of VMs [vmIDs],
+// one at a time, with these subnets added to their whitelisted subnets
+func (lc *localNetwork) restartNodesWithWhitelistedSubnets(
+	ctx context.Context,
+	vmIDs []ids.ID,
+	customVMValidators map[string]*rpcpb.CustomVmValidators,
+) (err error) {
 	println()
 	color.Outf("{{green}}restarting each node with %s{{/}}\n", config.WhitelistedSubnetsKey)
-	whitelistedSubnetIDs := make([]string, 0, len(lc.customVMIDToInfo))
-	for _, vmInfo := range lc.customVMIDToInfo {
-		whitelistedSubnetIDs = append(whitelistedSubnetIDs, vmInfo.subnetID.String())
+	// the plugin dir may be given after the start
+	buildDir, err := getBuildDir(lc.options.pluginDir)
+	if err != nil {
+		return err
 	}
-	sort.Strings(whitelistedSubnetIDs)
-	whitelistedSubnets := strings.Join(whitelistedSubnetIDs, ",")
+	restartedNodeConfigs := []int{}
 	for i := range lc.cfg.NodeConfigs {
 		nodeName := lc.cfg.NodeConfigs[i].Name
 
+		whitelistedSubnetIDs := []string{}
+		for _, vmID := range vmIDs {
+			vmInfo := lc.customVMIDToInfo[vmID]
+			if tracksCustomVM(nodeName, customVMValidators[vmInfo.info.VmName]) {
+				whitelistedSubnetIDs = append(whitelistedSubnetIDs, vmInfo.subnetID.String())
+			}
+		}
+		// keep the subnets whitelisted at start, or since
+		currentSubnets := lc.options.whitelistedSubnets
+		if nodeInfo, ok := lc.nodeInfos[nodeName]; ok {
+			currentSubnets = mergeWhitelistedSubnets(currentSubnets, nodeInfo.WhitelistedSubnets)
+		} else {
+			currentSubnets = mergeWhitelistedSubnets(currentSubnets)
+		}
+		whitelistedSubnets := mergeWhitelistedSubnets(currentSubnets, strings.Join(whitelistedSubnetIDs, ","))
+		if whitelistedSubnets == currentSubnets {
+			zap.L().Info("node not tracking the new subnets; skipping", zap.String("node-name", nodeName))
+			continue
+		}
 		zap.L().Info("updating node config",
 			zap.String("node-name", nodeName),
 			zap.String("whitelisted-subnets", whitelistedSubnets),
@@ -315,15 +335,29 @@ func (lc *localNetwork) restartNodesWithWhitelistedSubnets(ctx context.Context)
 		if err != nil {
 			return err
 		}
+		if buildDir != "" {
+			lc.cfg.NodeConfigs[i].ConfigFile, err = utils.SetJSONKey(lc.cfg.NodeConfigs[i].ConfigFile, config.BuildDirKey, buildDir)
+			if err != nil {
+				return err
+			}
+		}
+		// the files for the new subnets
+		lc.setNodeConfigFiles(&lc.cfg.NodeConfigs[i], whitelistedSubnets)
+		restartedNodeConfigs = append(restartedNodeConfigs, i)
 	}
-	zap.L().Info("restarting all nodes to whitelist subnet",
-		zap.Strings("whitelisted-subnets", whitelistedSubnetIDs),
-	)
-	for _, nodeConfig := range lc.cfg.NodeConfigs {
+	zap.L().Info("restarting nodes to whitelist subnets", zap.Int("nodes", len(restartedNodeConfigs)))
+	return lc.restartNodes(ctx, restartedNodeConfigs)
+}
+
+// restartNodes restarts the nodes of [lc.cfg.NodeConfigs] at indexes [nodeConfigs],
+// one at a time, with their current config
+func (lc *localNetwork) restartNodes(ctx context.Context, nodeConfigs []int) error {
+	for _, i := range nodeConfigs {
+		nodeConfig := lc.cfg.NodeConfigs[i]
 		nodeName := nodeConfig.Name
 
 		lc.customVMRestartMu.Lock()
-		zap.L().Info("removing and adding back the node for whitelisted subnets", zap.String("node-name", nodeName))
+		zap.L().Info("removing and adding back the node with the updated config", zap.String("node-name", nodeName))
 		if err := lc.nw.RemoveNode(nodeName); err != nil {
 			lc.customVMRestartMu.Unlock()
 			return err
@@ -343,33 +377,238 @@ func (lc *localNetwork) restartNodesWithWhitelistedSubnets(ctx context.Context)
 	return nil
 }
 
-func (lc *localNetwork) addSubnetValidators(ctx context.Context, baseWallet *refreshableWallet, validatorIDs []ids.NodeID) error {
+// tracksCustomVM returns true if node [nodeName] validates or observes the subnet
+// of a custom VM with validators [vmValidators], all nodes doing so if nil
+func tracksCustomVM(nodeName string, vmValidators *rpcpb.CustomVmValidators) bool {
+	if vmValidators == nil {
+		return true
+	}
+	for _, v := range vmValidators.Validators {
+		if v.NodeName == nodeName {
+			return true
+		}
+	}
+	for _, observer := range vmValidators.Observers {
+		if observer == nodeName {
+			return true
+		}
+	}
+	return false
+}
+
+// tracksSubnet returns true if [subnetID] is in the whitelisted subnets of [nodeInfo]
+func tracksSubnet(nodeInfo *rpcpb.NodeInfo, subnetID string) bool {
+	return containsSubnet(nodeInfo.GetWhitelistedSubnets(), subnetID)
+}
+
+// containsSubnet returns true if [subnetID] is in the comma separated [whitelistedSubnets]
+func containsSubnet(whitelistedSubnets string, subnetID string) bool {
+	for _, whitelisted := range strings.Split(whitelistedSubnets, ",") {
+		if strings.TrimSpace(whitelisted) == subnetID {
+			return true
+		}
+	}
+	return false
+}
+
+// customVMBlockchain returns the subnet and blockchain IDs of custom VM [vmName],
+// if its blockchain was created
+func (lc *localNetwork) customVMBlockchain(vmName string) (string, string, bool) {
+	for _, vmInfo := range lc.customVMIDToInfo {
+		if vmInfo.info.VmName == vmName && vmInfo.info.BlockchainId != "" {
+			return vmInfo.info.SubnetId, vmInfo.info.BlockchainId, true
+		}
+	}
+	return "", "", false
+}
+
+// checkCustomVMsReady checks the readiness of the blockchain of each custom VM
+// on each node tracking its subnet, records it in the node statuses of the VM info,
+// and returns the names of the VMs not ready on all these nodes
+func (lc *localNetwork) checkCustomVMsReady(ctx context.Context) []string {
+	notReady := []string{}
+	for _, vmInfo := range lc.customVMIDToInfo {
+		nodeStatuses := make(map[string]*rpcpb.CustomVmNodeStatus)
+		ready := true
+		for _, nodeName := range lc.nodeNames {
+			// observers and validators only
+			if !tracksSubnet(lc.nodeInfos[nodeName], vmInfo.info.SubnetId) {
+				continue
+			}
+			status := lc.checkCustomVMReady(ctx, nodeName, vmInfo)
+			if !status.Ready {
+				zap.L().Info("blockchain not ready on node",
+					zap.String("vm-name", vmInfo.info.VmName),
+					zap.String("blockchain-id", vmInfo.info.BlockchainId),
+					zap.String("node-name", nodeName),
+					zap.String("reason", status.Error),
+				)
+				ready = false
+			}
+			nodeStatuses[nodeName] = status
+		}
+		// the VM info may be reported in the cluster info
+		lc.customVMRestartMu.Lock()
+		vmInfo.info.NodeStatuses = nodeStatuses
+		lc.customVMRestartMu.Unlock()
+		if !ready {
+			notReady = append(notReady, vmInfo.info.VmName)
+		}
+	}
+	sort.Strings(notReady)
+	return notReady
+}
+
+// checkCustomVMReady returns the readiness of the blockchain of [vmInfo] on node [nodeName]
+func (lc *localNetwork) checkCustomVMReady(ctx context.Context, nodeName string, vmInfo vmInfo) *rpcpb.CustomVmNodeStatus {
+	status := &rpcpb.CustomVmNodeStatus{}
+	nd, err := lc.nw.GetNode(nodeName)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	// a node that exited won't come back
+	if exited, err := nd.GetExitStatus(); exited {
+		status.Error = fmt.Sprintf("node exited: %v", err)
+		return status
+	}
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	health, err := nd.GetAPIClient().HealthAPI().Health(cctx)
+	if err != nil {
+		status.Error = fmt.Sprintf("health check failed: %v", err)
+		return status
+	}
+	status.Healthy = health.Healthy
+	if vmInfo.info.BlockchainId == "" {
+		status.Error = "blockchain not created"
+		return status
+	}
+	status.Bootstrapped, err = nd.GetAPIClient().InfoAPI().IsBootstrapped(cctx, vmInfo.info.BlockchainId)
+	if err != nil {
+		status.Error = fmt.Sprintf("bootstrap check failed: %v", err)
+		return status
+	}
+	switch {
+	case !status.Healthy:
+		status.Error = "node not healthy"
+		return status
+	case !status.Bootstrapped:
+		status.Error = "blockchain not bootstrapped"
+		return status
+	}
+	if method := lc.options.customVMReadinessProbes[vmInfo.info.VmName]; method != "" {
+		url := fmt.Sprintf("%s/ext/bc/%s/rpc", lc.nodeInfos[nodeName].GetUri(), vmInfo.info.BlockchainId)
+		if err := probeJSONRPC(cctx, url, method); err != nil {
+			status.Error = fmt.Sprintf("readiness probe %q failed: %v", method, err)
+			return status
+		}
+	}
+	status.Ready = true
+	return status
+}
+
+// probeJSONRPC calls JSON-RPC method [method] without params at [url],
+// and returns an error if the call does not return a result
+func probeJSONRPC(ctx context.Context, url string, method string) error {
+	var result json.RawMessage
+	if err := callJSONRPC(ctx, url, method, []interface{}{}, &result); err != nil {
+		return err
+	}
+	if len(result) == 0 {
+		return errors.New("no result")
+	}
+	return nil
+}
+
+// callJSONRPC calls JSON-RPC method [method] with [params] at [url],
+// and decodes its result into [result]
+func callJSONRPC(ctx context.Context, url string, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %q", resp.Status)
+	}
+	var reply struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return err
+	}
+	if reply.Error != nil {
+		return fmt.Errorf("error %d: %s", reply.Error.Code, reply.Error.Message)
+	}
+	if len(reply.Result) == 0 {
+		// [result] is left unchanged
+		return nil
+	}
+	return json.Unmarshal(reply.Result, result)
+}
+
+// addSubnetValidators adds the validators of the subnets of VMs [vmIDs],
+// all nodes for the VMs not in [customVMValidators]
+func (lc *localNetwork) addSubnetValidators(
+	ctx context.Context,
+	baseWallet *refreshableWallet,
+	vmIDs []ids.ID,
+	customVMValidators map[string]*rpcpb.CustomVmValidators,
+) error {
 	println()
-	color.Outf("{{green}}adding all nodes as subnet validator for each subnet{{/}}\n")
-	for vmID, vmInfo := range lc.customVMIDToInfo {
-		zap.L().Info("adding all nodes as subnet validator",
+	color.Outf("{{green}}adding subnet validators for each subnet{{/}}\n")
+	for _, vmID := range vmIDs {
+		vmInfo := lc.customVMIDToInfo[vmID]
+		validators := customVMValidators[vmInfo.info.VmName].GetValidators()
+		if len(validators) == 0 {
+			for _, nodeName := range lc.nodeNames {
+				validators = append(validators, &rpcpb.SubnetValidatorSpec{NodeName: nodeName})
+			}
+		}
+		zap.L().Info("adding subnet validators",
 			zap.String("vm-name", vmInfo.info.VmName),
 			zap.String("vm-id", vmID.String()),
 			zap.String("subnet-id", vmInfo.subnetID.String()),
+			zap.Int("validators", len(validators)),
 		)
-		for _, validatorID := range validatorIDs {
-			cctx, cancel := createDefaultCtx(ctx)
-			txID, err := baseWallet.P().IssueAddSubnetValidatorTx(
-				&validator.SubnetValidator{
-					Validator: validator.Validator{
-						NodeID: validatorID,
-
-						// reasonable delay in most/slow test environments
-						Start: uint64(time.Now().Add(time.Minute).Unix()),
-						End:   uint64(time.Now().Add(100 * time.Hour).Unix()),
-						Wght:  1000,
-					},
-					Subnet: vmInfo.subnetID,
-				},
-				common.WithContext(cctx),
-				defaultPoll,
+		for _, v := range validators {
+			nodeInfo, ok := lc.nodeInfos[v.NodeName]
+			if !ok {
+				return fmt.Errorf("%w: %q", ErrNodeNotFound, v.NodeName)
+			}
+			nodeID, err := ids.NodeIDFromString(nodeInfo.Id)
+			if err != nil {
+				return err
+			}
+			v = withSubnetValidatorDefaults(v, time.Now())
+			txID, err := lc.addSubnetValidator(
+				ctx,
+				baseWallet,
+				vmInfo.subnetID,
+				nodeID,
+				v.Weight,
+				time.Unix(v.StartTime, 0),
+				time.Unix(v.EndTime, 0),
 			)
-			cancel()
 			if err != nil {
 				return err
 			}
@@ -377,7 +616,9 @@ func (lc *localNetwork) addSubnetValidators(ctx context.Context, baseWallet *ref
 				zap.String("vm-name", vmInfo.info.VmName),
 				zap.String("vm-id", vmID.String()),
 				zap.String("subnet-id", vmInfo.subnetID.String()),
-				zap.String("node-id", validatorID.String()),
+				zap.String("node-name", v.NodeName),
+				zap.String("node-id", nodeID.String()),
+				zap.Uint64("weight", v.Weight),
 				zap.String("tx-id", txID.String()),
 			)
 		}
@@ -385,29 +626,22 @@ func (lc *localNetwork) addSubnetValidators(ctx context.Context, baseWallet *ref
 	return nil
 }
 
-func (lc *localNetwork) createBlockchains(ctx context.Context, baseWallet *refreshableWallet, testKeyAddr ids.ShortID) error {
+// createBlockchains creates a blockchain for each of VMs [vmIDs],
+// with its genesis in [customVMs]
+func (lc *localNetwork) createBlockchains(ctx context.Context, baseWallet *refreshableWallet, vmIDs []ids.ID, customVMs map[string][]byte) error {
 	println()
 	color.Outf("{{green}}creating blockchain for each custom VM{{/}}\n")
-	for vmID, vmInfo := range lc.customVMIDToInfo {
+	for _, vmID := range vmIDs {
+		vmInfo := lc.customVMIDToInfo[vmID]
 		vmName := vmInfo.info.VmName
-		vmGenesisBytes := lc.customVMNameToGenesis[vmName]
+		vmGenesisBytes := customVMs[vmName]
 
 		zap.L().Info("creating blockchain tx",
 			zap.String("vm-name", vmName),
 			zap.String("vm-id", vmID.String()),
 			zap.Int("genesis-bytes", len(vmGenesisBytes)),
 		)
-		cctx, cancel := createDefaultCtx(ctx)
-		blockchainID, err := baseWallet.P().IssueCreateChainTx(
-			vmInfo.subnetID,
-			vmGenesisBytes,
-			vmID,
-			nil,
-			vmName,
-			common.WithContext(cctx),
-			defaultPoll,
-		)
-		cancel()
+		blockchainID, err := lc.createBlockchain(ctx, baseWallet, vmInfo.subnetID, vmID, vmName, vmGenesisBytes)
 		if err != nil {
 			return err
 		}
@@ -425,4 +659,58 @@ func (lc *localNetwork) createBlockchains(ctx context.Context, baseWallet *refre
 	return nil
 }
 
+// addSubnetValidator issues a tx adding node [nodeID] as a validator of subnet [subnetID]
+func (lc *localNetwork) addSubnetValidator(
+	ctx context.Context,
+	baseWallet *refreshableWallet,
+	subnetID ids.ID,
+	nodeID ids.NodeID,
+	weight uint64,
+	start time.Time,
+	end time.Time,
+) (ids.ID, error) {
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	return baseWallet.P().IssueAddSubnetValidatorTx(
+		&validator.SubnetValidator{
+			Validator: validator.Validator{
+				NodeID: nodeID,
+				Start:  uint64(start.Unix()),
+				End:    uint64(end.Unix()),
+				Wght:   weight,
+			},
+			Subnet: subnetID,
+		},
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+}
+
+// createBlockchain issues a tx creating blockchain [chainName] of VM [vmID] on subnet [subnetID]
+func (lc *localNetwork) createBlockchain(
+	ctx context.Context,
+	baseWallet *refreshableWallet,
+	subnetID ids.ID,
+	vmID ids.ID,
+	chainName string,
+	genesis []byte,
+) (ids.ID, error) {
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	return baseWallet.P().IssueCreateChainTx(
+		subnetID,
+		genesis,
+		vmID,
+		nil,
+		chainName,
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+}
+
+const (
+	customVMsReadyTimeout      = 5 * time.Minute
+	customVMsReadyPollInterval = 5 * time.Second
+)
+
 var defaultPoll = common.WithPollFrequency(5 * time.Second)
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeJSONRPC(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch req.Method {
+		case "eth_blockNumber":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x0"}`))
+		case "eth_syncing":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "eth_chainId":
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1}`))
+		default:
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`))
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	assert.NoError(probeJSONRPC(ctx, srv.URL, "eth_blockNumber"))
+	assert.Error(probeJSONRPC(ctx, srv.URL, "eth_syncing"))
+	assert.Error(probeJSONRPC(ctx, srv.URL, "eth_chainId"))
+	err := probeJSONRPC(ctx, srv.URL, "other")
+	assert.Error(err)
+	assert.Contains(err.Error(), "method not found")
+}
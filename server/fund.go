@@ -0,0 +1,243 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia/ids"
+	"github.com/axiacoin/axia/utils/constants"
+	"github.com/axiacoin/axia/utils/formatting/address"
+	"github.com/axiacoin/axia/vms/components/axc"
+	"github.com/axiacoin/axia/vms/secp256k1fx"
+	"github.com/axiacoin/axia/wallet/subnet/primary/common"
+	"github.com/axiacoin/coreth/core/types"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+const (
+	// nAXC to wei conversion rate of the AXChain
+	x2cRate = 1_000_000_000
+	// gas of a plain AXC transfer on the AXChain
+	cChainTransferGas = 21000
+	// interval between receipt polls while waiting for an AXChain tx to be accepted
+	cChainReceiptPollInterval = time.Second
+)
+
+// fundTarget is an address to send AXC to
+type fundTarget struct {
+	// "X", "P" or "C"
+	chain string
+	// SwapChain or CoreChain address
+	addr ids.ShortID
+	// AXChain address
+	ethAddr ethcommon.Address
+}
+
+func (s *server) Fund(ctx context.Context, req *rpcpb.FundRequest) (*rpcpb.FundResponse, error) {
+	zap.L().Info("received fund request",
+		zap.String("address", req.Address),
+		zap.Uint64("amount", req.Amount),
+	)
+	target, err := parseFundTarget(req.Address)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount == 0 {
+		return nil, ErrInvalidAmount
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	txID, err := s.network.fund(ctx, target, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+	zap.L().Info("funded address",
+		zap.String("address", req.Address),
+		zap.Uint64("amount", req.Amount),
+		zap.String("tx-id", txID),
+	)
+	return &rpcpb.FundResponse{ClusterInfo: s.clusterInfo, TxId: txID}, nil
+}
+
+// parseFundTarget parses [addr] as a SwapChain or CoreChain address, e.g. "X-custom1...",
+// or as an AXChain hex address, e.g. "0x8db9..."
+func parseFundTarget(addr string) (fundTarget, error) {
+	if strings.HasPrefix(addr, "0x") || strings.HasPrefix(addr, "0X") {
+		if !ethcommon.IsHexAddress(addr) {
+			return fundTarget{}, fmt.Errorf("%w: %q", ErrInvalidAddress, addr)
+		}
+		return fundTarget{chain: "C", ethAddr: ethcommon.HexToAddress(addr)}, nil
+	}
+	chain, _, addrBytes, err := address.Parse(addr)
+	if err != nil {
+		return fundTarget{}, fmt.Errorf("%w: %q: %v", ErrInvalidAddress, addr, err)
+	}
+	if chain != "X" && chain != "P" {
+		return fundTarget{}, fmt.Errorf("%w: %q: unknown chain %q", ErrInvalidAddress, addr, chain)
+	}
+	shortID, err := ids.ToShortID(addrBytes)
+	if err != nil {
+		return fundTarget{}, fmt.Errorf("%w: %q: %v", ErrInvalidAddress, addr, err)
+	}
+	return fundTarget{chain: chain, addr: shortID}, nil
+}
+
+// fund sends [amount] nAXC from the pre-funded test key to [target],
+// and returns the ID of the accepted tx crediting it
+func (lc *localNetwork) fund(ctx context.Context, target fundTarget, amount uint64) (string, error) {
+	if target.chain == "C" {
+		return lc.fundCChain(ctx, target.ethAddr, amount)
+	}
+	baseWallet, axcAssetID, testKeyAddr, err := lc.setupWallet(ctx, lc.nodeInfos[lc.nodeNames[0]].Uri)
+	if err != nil {
+		return "", err
+	}
+	if target.chain == "X" {
+		println()
+		color.Outf("{{green}}sending %d nAXC to %q on the SwapChain{{/}}\n", amount, target.addr)
+		cctx, cancel := createDefaultCtx(ctx)
+		txID, err := baseWallet.X().IssueBaseTx(
+			[]*axc.TransferableOutput{transferableOutput(axcAssetID, amount, target.addr)},
+			common.WithContext(cctx),
+			defaultPoll,
+		)
+		cancel()
+		if err != nil {
+			return "", err
+		}
+		return txID.String(), nil
+	}
+
+	// the CoreChain wallet can't issue base txs, so the AXC is exported from the
+	// SwapChain to the test key, then imported into the address, paying the import fee
+	println()
+	color.Outf("{{green}}exporting %d nAXC from the SwapChain to the CoreChain{{/}}\n", amount)
+	cctx, cancel := createDefaultCtx(ctx)
+	exportTxID, err := baseWallet.X().IssueExportTx(
+		constants.PlatformChainID,
+		[]*axc.TransferableOutput{transferableOutput(axcAssetID, amount+baseWallet.P().BaseTxFee(), testKeyAddr)},
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+	cancel()
+	if err != nil {
+		return "", err
+	}
+	zap.L().Info("exported AXC to the CoreChain", zap.String("tx-id", exportTxID.String()))
+
+	color.Outf("{{green}}importing %d nAXC to %q on the CoreChain{{/}}\n", amount, target.addr)
+	cctx, cancel = createDefaultCtx(ctx)
+	importTxID, err := baseWallet.P().IssueImportTx(
+		baseWallet.X().BlockchainID(),
+		&secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{target.addr},
+		},
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+	cancel()
+	if err != nil {
+		return "", err
+	}
+	return importTxID.String(), nil
+}
+
+func transferableOutput(assetID ids.ID, amount uint64, to ids.ShortID) *axc.TransferableOutput {
+	return &axc.TransferableOutput{
+		Asset: axc.Asset{ID: assetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: amount,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{to},
+			},
+		},
+	}
+}
+
+// fundCChain transfers [amount] nAXC from the pre-funded test key to [to] on the AXChain
+// through the first node, and returns the hash of the accepted tx
+func (lc *localNetwork) fundCChain(ctx context.Context, to ethcommon.Address, amount uint64) (string, error) {
+	nd, err := lc.nw.GetNode(lc.nodeNames[0])
+	if err != nil {
+		return "", err
+	}
+	// the client is closed with the node
+	ethClient := nd.GetAPIClient().CChainEthAPI()
+	key, err := crypto.ToECDSA(prefundedKey.Bytes())
+	if err != nil {
+		return "", err
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	nonce, err := ethClient.NonceAt(cctx, from, nil)
+	if err != nil {
+		return "", err
+	}
+	gasPrice, err := ethClient.SuggestGasPrice(cctx)
+	if err != nil {
+		return "", err
+	}
+	chainID, err := ethClient.ChainID(cctx)
+	if err != nil {
+		return "", err
+	}
+	value := new(big.Int).Mul(new(big.Int).SetUint64(amount), big.NewInt(x2cRate))
+	tx := types.NewTransaction(nonce, to, value, cChainTransferGas, gasPrice, nil)
+	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainID), key)
+	if err != nil {
+		return "", err
+	}
+	println()
+	color.Outf("{{green}}sending %d nAXC to %s on the AXChain{{/}}\n", amount, to)
+	if err := ethClient.SendTransaction(cctx, signedTx); err != nil {
+		return "", err
+	}
+	if _, err := waitForEVMReceipt(cctx, ethClient, signedTx.Hash(), cChainReceiptPollInterval); err != nil {
+		return "", err
+	}
+	return signedTx.Hash().Hex(), nil
+}
+
+// waitForEVMReceipt polls the receipt of tx [txHash] every [pollInterval] until it is found,
+// and returns it if the tx succeeded
+func waitForEVMReceipt(ctx context.Context, client evmClient, txHash ethcommon.Hash, pollInterval time.Duration) (*types.Receipt, error) {
+	for {
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return nil, fmt.Errorf("tx %s failed", txHash)
+			}
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("tx %s not accepted: %w (last error: %v)", txHash, ctx.Err(), err)
+		case <-time.After(pollInterval):
+		}
+	}
+}
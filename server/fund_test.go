@@ -0,0 +1,39 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFundTarget(t *testing.T) {
+	assert := assert.New(t)
+
+	target, err := parseFundTarget("X-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p")
+	assert.NoError(err)
+	assert.Equal("X", target.chain)
+	assert.Equal("6Y3kysjF9jnHnYkdS9yGAuoHyae2eNmeV", target.addr.String())
+
+	pTarget, err := parseFundTarget("P-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p")
+	assert.NoError(err)
+	assert.Equal("P", pTarget.chain)
+	assert.Equal(target.addr, pTarget.addr)
+
+	target, err = parseFundTarget("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	assert.NoError(err)
+	assert.Equal("C", target.chain)
+	assert.Equal(common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC"), target.ethAddr)
+
+	for _, addr := range []string{
+		"",
+		"0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52",
+		"custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p",
+		"C-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7p",
+		"X-custom18jma8ppw3nhx5r4ap8clazz0dps7rv5u9xde7q",
+	} {
+		_, err := parseFundTarget(addr)
+		assert.True(errors.Is(err, ErrInvalidAddress), addr)
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia-network-runner/utils"
+	"github.com/axiacoin/axia/genesis"
+	"github.com/axiacoin/axia/ids"
+	"github.com/axiacoin/axia/utils/constants"
+	"github.com/axiacoin/axia/utils/crypto"
+	"github.com/axiacoin/axia/utils/formatting"
+	"github.com/axiacoin/axia/utils/formatting/address"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+const (
+	// name of [prefundedKey] in the server keychain
+	prefundedKeyName = "ewoq"
+	privateKeyPrefix = "PrivateKey-"
+)
+
+// prefundedKey is funded on the SwapChain, CoreChain and AXChain by the default network genesis
+var prefundedKey = genesis.EWOQKey
+
+// keychain holds the keys managed by the server, in the order they were added
+type keychain struct {
+	mu    sync.Mutex
+	names []string
+	keys  map[string]*crypto.PrivateKeySECP256K1R
+}
+
+// newKeychain returns a keychain holding [prefundedKey]
+func newKeychain() *keychain {
+	return &keychain{
+		names: []string{prefundedKeyName},
+		keys:  map[string]*crypto.PrivateKeySECP256K1R{prefundedKeyName: prefundedKey},
+	}
+}
+
+// add adds [key] as [name], unless the name or the key is already in the keychain
+func (kc *keychain) add(name string, key *crypto.PrivateKeySECP256K1R) error {
+	if name == "" {
+		return ErrNoKeyName
+	}
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+	if _, ok := kc.keys[name]; ok {
+		return fmt.Errorf("%w: %q", ErrKeyExists, name)
+	}
+	addr := key.PublicKey().Address()
+	for existingName, existing := range kc.keys {
+		if existing.PublicKey().Address() == addr {
+			return fmt.Errorf("%w: as %q", ErrKeyExists, existingName)
+		}
+	}
+	kc.names = append(kc.names, name)
+	kc.keys[name] = key
+	return nil
+}
+
+// get returns the key named [name]
+func (kc *keychain) get(name string) (*crypto.PrivateKeySECP256K1R, bool) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+	key, ok := kc.keys[name]
+	return key, ok
+}
+
+// list returns the names and keys of the keychain, in the order they were added
+func (kc *keychain) list() ([]string, []*crypto.PrivateKeySECP256K1R) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+	names := make([]string, len(kc.names))
+	copy(names, kc.names)
+	keys := make([]*crypto.PrivateKeySECP256K1R, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, kc.keys[name])
+	}
+	return names, keys
+}
+
+// parsePrivateKey parses a "PrivateKey-" prefixed CB58 or a hex encoded private key
+func parsePrivateKey(s string) (*crypto.PrivateKeySECP256K1R, error) {
+	var (
+		keyBytes []byte
+		err      error
+	)
+	if strings.HasPrefix(s, privateKeyPrefix) {
+		keyBytes, err = formatting.Decode(formatting.CB58, strings.TrimPrefix(s, privateKeyPrefix))
+	} else {
+		keyBytes, err = hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+	}
+	factory := crypto.FactorySECP256K1R{}
+	key, err := factory.ToPrivateKey(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPrivateKey, err)
+	}
+	return key.(*crypto.PrivateKeySECP256K1R), nil
+}
+
+func (s *server) CreateKey(ctx context.Context, req *rpcpb.CreateKeyRequest) (*rpcpb.CreateKeyResponse, error) {
+	zap.L().Info("received create key request", zap.String("name", req.Name))
+	if req.Name == "" {
+		return nil, ErrNoKeyName
+	}
+	factory := crypto.FactorySECP256K1R{}
+	key, err := factory.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	clusterInfo, keyInfo, err := s.addKey(ctx, req.Name, key.(*crypto.PrivateKeySECP256K1R))
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.CreateKeyResponse{ClusterInfo: clusterInfo, Key: keyInfo}, nil
+}
+
+func (s *server) ImportKey(ctx context.Context, req *rpcpb.ImportKeyRequest) (*rpcpb.ImportKeyResponse, error) {
+	zap.L().Info("received import key request", zap.String("name", req.Name))
+	if req.Name == "" {
+		return nil, ErrNoKeyName
+	}
+	key, err := parsePrivateKey(req.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	clusterInfo, keyInfo, err := s.addKey(ctx, req.Name, key)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.ImportKeyResponse{ClusterInfo: clusterInfo, Key: keyInfo}, nil
+}
+
+// addKey adds [key] to the server keychain as [name],
+// and returns its addresses and balances on the running network
+func (s *server) addKey(ctx context.Context, name string, key *crypto.PrivateKeySECP256K1R) (*rpcpb.ClusterInfo, *rpcpb.KeyInfo, error) {
+	if info := s.getClusterInfo(); info == nil {
+		return nil, nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, nil, ErrNetworkNotReady
+	}
+	keyInfo, err := s.network.keyInfo(ctx, name, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.keys.add(name, key); err != nil {
+		return nil, nil, err
+	}
+	zap.L().Info("added key",
+		zap.String("name", name),
+		zap.String("x-address", keyInfo.XAddress),
+		zap.String("c-address", keyInfo.CAddress),
+	)
+	return s.clusterInfo, keyInfo, nil
+}
+
+func (s *server) ListKeys(ctx context.Context, req *rpcpb.ListKeysRequest) (*rpcpb.ListKeysResponse, error) {
+	zap.L().Debug("received list keys request")
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ErrNetworkNotReady
+	}
+	names, keys := s.keys.list()
+	keyInfos := make([]*rpcpb.KeyInfo, 0, len(keys))
+	for i, key := range keys {
+		keyInfo, err := s.network.keyInfo(ctx, names[i], key)
+		if err != nil {
+			return nil, err
+		}
+		keyInfos = append(keyInfos, keyInfo)
+	}
+	return &rpcpb.ListKeysResponse{ClusterInfo: s.clusterInfo, Keys: keyInfos}, nil
+}
+
+// keyInfo returns the addresses of [key] on the network, and its AXC balances
+// on the SwapChain, CoreChain and AXChain as reported by the first node
+func (lc *localNetwork) keyInfo(ctx context.Context, name string, key *crypto.PrivateKeySECP256K1R) (*rpcpb.KeyInfo, error) {
+	cb58Key, err := formatting.EncodeWithChecksum(formatting.CB58, key.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	networkID, err := utils.NetworkIDFromGenesis([]byte(lc.cfg.Genesis))
+	if err != nil {
+		return nil, err
+	}
+	hrp := constants.GetHRP(networkID)
+	addr := key.PublicKey().Address()
+	xAddr, err := address.Format("X", hrp, addr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	pAddr, err := address.Format("P", hrp, addr.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	ecdsaKey, err := ethcrypto.ToECDSA(key.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	cAddr := ethcrypto.PubkeyToAddress(ecdsaKey.PublicKey)
+
+	nd, err := lc.nw.GetNode(lc.nodeNames[0])
+	if err != nil {
+		return nil, err
+	}
+	apiClient := nd.GetAPIClient()
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	axcAssetID, err := apiClient.PChainAPI().GetStakingAssetID(cctx, constants.PrimaryNetworkID)
+	if err != nil {
+		return nil, err
+	}
+	xBalance, err := apiClient.XChainAPI().GetBalance(cctx, addr, axcAssetID.String(), false)
+	if err != nil {
+		return nil, err
+	}
+	pBalance, err := apiClient.PChainAPI().GetBalance(cctx, []ids.ShortID{addr})
+	if err != nil {
+		return nil, err
+	}
+	// the client is closed with the node
+	cBalance, err := apiClient.CChainEthAPI().BalanceAt(cctx, cAddr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rpcpb.KeyInfo{
+		Name:          name,
+		PrivateKey:    privateKeyPrefix + cb58Key,
+		PrivateKeyHex: hex.EncodeToString(key.Bytes()),
+		XAddress:      xAddr,
+		PAddress:      pAddr,
+		CAddress:      cAddr.Hex(),
+		XBalance:      uint64(xBalance.Balance),
+		PBalance:      uint64(pBalance.Balance),
+		CBalance:      new(big.Int).Div(cBalance, big.NewInt(x2cRate)).Uint64(),
+	}, nil
+}
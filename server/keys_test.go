@@ -0,0 +1,59 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/axiacoin/axia/utils/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeychain(t *testing.T) {
+	assert := assert.New(t)
+
+	kc := newKeychain()
+	names, keys := kc.list()
+	assert.Equal([]string{prefundedKeyName}, names)
+	assert.Equal([]*crypto.PrivateKeySECP256K1R{prefundedKey}, keys)
+
+	factory := crypto.FactorySECP256K1R{}
+	key, err := factory.NewPrivateKey()
+	assert.NoError(err)
+	assert.NoError(kc.add("key1", key.(*crypto.PrivateKeySECP256K1R)))
+	names, _ = kc.list()
+	assert.Equal([]string{prefundedKeyName, "key1"}, names)
+
+	assert.True(errors.Is(kc.add("", key.(*crypto.PrivateKeySECP256K1R)), ErrNoKeyName))
+	// same name
+	otherKey, err := factory.NewPrivateKey()
+	assert.NoError(err)
+	assert.True(errors.Is(kc.add("key1", otherKey.(*crypto.PrivateKeySECP256K1R)), ErrKeyExists))
+	// same key
+	assert.True(errors.Is(kc.add("key2", prefundedKey), ErrKeyExists))
+	names, _ = kc.list()
+	assert.Equal([]string{prefundedKeyName, "key1"}, names)
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, s := range []string{
+		"PrivateKey-ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNN",
+		"56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027",
+		"0x56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d8027",
+	} {
+		key, err := parsePrivateKey(s)
+		assert.NoError(err, s)
+		assert.Equal(prefundedKey.Bytes(), key.Bytes(), s)
+	}
+
+	for _, s := range []string{
+		"",
+		"PrivateKey-ewoqjP7PxY4yr3iLTpLisriqt94hdyDFNgchSxGGztUrTXtNM",
+		"56289e99c94b6912bfc12adc093c9b51124f0dc54ac7a766b2bc5ccf558d80",
+		"not a key",
+	} {
+		_, err := parsePrivateKey(s)
+		assert.True(errors.Is(err, ErrInvalidPrivateKey), s)
+	}
+}
@@ -0,0 +1,648 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia/ids"
+	"github.com/axiacoin/axia/utils/crypto"
+	"github.com/axiacoin/axia/utils/units"
+	"github.com/axiacoin/axia/vms/components/axc"
+	"github.com/axiacoin/axia/vms/secp256k1fx"
+	"github.com/axiacoin/axia/wallet/subnet/primary/common"
+	"github.com/axiacoin/coreth/core/types"
+	"github.com/axiacoin/coreth/ethclient"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+const (
+	loadChainX = "X"
+	loadChainC = "C"
+
+	defaultLoadRate    = 10
+	defaultLoadNumKeys = 10
+	maxLoadRate        = 10_000
+	maxLoadNumKeys     = 1_000
+	// nAXC funded to each generated key, on each chain
+	loadKeyFunds = 100 * units.Axc
+	// nAXC sent by each transfer
+	loadTransferAmount = 1
+	// timeout for a tx to be accepted
+	loadTxTimeout = time.Minute
+	// interval between status polls while waiting for a tx to be accepted
+	loadPollInterval = 100 * time.Millisecond
+	// number of the most recent latencies kept per chain
+	maxLoadLatencies = 100_000
+
+	loadContractDeployGas = 200_000
+	loadContractCallGas   = 50_000
+)
+
+// loadContractCode is the creation code of a contract incrementing a counter
+// in its storage on every call
+var loadContractCode = ethcommon.FromHex("0x600a600c600039600a6000f360016000540160005500")
+
+var loadPoll = common.WithPollFrequency(loadPollInterval)
+
+// loadSender sends a tx and waits for it to be accepted, and returns the latency
+// from sending to acceptance
+type loadSender func(ctx context.Context) (time.Duration, error)
+
+// evmClient is the subset of the AXChain and EVM subnet clients used to send txs
+type evmClient interface {
+	ChainID(context.Context) (*big.Int, error)
+	NonceAt(context.Context, ethcommon.Address, *big.Int) (uint64, error)
+	SuggestGasPrice(context.Context) (*big.Int, error)
+	SendTransaction(context.Context, *types.Transaction) error
+	TransactionReceipt(context.Context, ethcommon.Hash) (*types.Receipt, error)
+}
+
+// loadGenerator sends txs at a target rate to each of its chains, from generated keys
+type loadGenerator struct {
+	chains        []string
+	rate          uint32
+	numKeys       uint32
+	contractCalls bool
+	duration      time.Duration
+
+	cancel context.CancelFunc
+	doneCh chan struct{}
+
+	statsMu   sync.Mutex
+	startTime time.Time
+	endTime   time.Time
+	stats     map[string]*loadStats
+}
+
+// loadStats are the outcomes of the txs sent to a chain
+type loadStats struct {
+	sent     uint64
+	accepted uint64
+	failed   uint64
+	// latencies of the most recent accepted txs, overwritten in a circle
+	latencies []time.Duration
+	next      int
+}
+
+// checkStartLoadRequest checks the rate, number of keys and duration of the load,
+// and that its chains are named
+func checkStartLoadRequest(req *rpcpb.StartLoadRequest) error {
+	if req.Rate > maxLoadRate {
+		return fmt.Errorf("%w: rate %d over %d", ErrInvalidLoad, req.Rate, maxLoadRate)
+	}
+	if req.NumKeys > maxLoadNumKeys {
+		return fmt.Errorf("%w: %d keys, over %d", ErrInvalidLoad, req.NumKeys, maxLoadNumKeys)
+	}
+	if req.Duration < 0 {
+		return fmt.Errorf("%w: duration %v", ErrInvalidLoad, time.Duration(req.Duration))
+	}
+	chains := make(map[string]struct{}, len(req.Chains))
+	for _, chain := range req.Chains {
+		if chain == "" {
+			return fmt.Errorf("%w: empty chain", ErrInvalidLoad)
+		}
+		if _, ok := chains[chain]; ok {
+			return fmt.Errorf("%w: chain %q given twice", ErrInvalidLoad, chain)
+		}
+		chains[chain] = struct{}{}
+	}
+	return nil
+}
+
+func newLoadGenerator(req *rpcpb.StartLoadRequest) *loadGenerator {
+	chains := req.Chains
+	if len(chains) == 0 {
+		chains = []string{loadChainX, loadChainC}
+	}
+	rate := req.Rate
+	if rate == 0 {
+		rate = defaultLoadRate
+	}
+	numKeys := req.NumKeys
+	if numKeys == 0 {
+		numKeys = defaultLoadNumKeys
+	}
+	stats := make(map[string]*loadStats, len(chains))
+	for _, chain := range chains {
+		stats[chain] = &loadStats{}
+	}
+	return &loadGenerator{
+		chains:        chains,
+		rate:          rate,
+		numKeys:       numKeys,
+		contractCalls: req.ContractCalls,
+		duration:      time.Duration(req.Duration),
+		doneCh:        make(chan struct{}),
+		stats:         stats,
+	}
+}
+
+func (lg *loadGenerator) running() bool {
+	select {
+	case <-lg.doneCh:
+		return false
+	default:
+		return true
+	}
+}
+
+func (lg *loadGenerator) recordSent(chain string) {
+	lg.statsMu.Lock()
+	defer lg.statsMu.Unlock()
+	lg.stats[chain].sent++
+}
+
+func (lg *loadGenerator) recordOutcome(chain string, latency time.Duration, err error) {
+	lg.statsMu.Lock()
+	defer lg.statsMu.Unlock()
+	ls := lg.stats[chain]
+	if err != nil {
+		ls.failed++
+		return
+	}
+	ls.accepted++
+	if len(ls.latencies) < maxLoadLatencies {
+		ls.latencies = append(ls.latencies, latency)
+		return
+	}
+	ls.latencies[ls.next] = latency
+	ls.next = (ls.next + 1) % maxLoadLatencies
+}
+
+// report returns the stats of the load so far
+func (lg *loadGenerator) report() *rpcpb.LoadReport {
+	lg.statsMu.Lock()
+	defer lg.statsMu.Unlock()
+
+	end := lg.endTime
+	running := end.IsZero()
+	if running {
+		end = time.Now()
+	}
+	elapsed := end.Sub(lg.startTime)
+	report := &rpcpb.LoadReport{
+		Running:   running,
+		StartTime: lg.startTime.UnixNano(),
+		Rate:      lg.rate,
+		NumKeys:   lg.numKeys,
+		Chains:    make(map[string]*rpcpb.ChainLoadStats, len(lg.stats)),
+	}
+	if !running {
+		report.EndTime = lg.endTime.UnixNano()
+	}
+	for chain, ls := range lg.stats {
+		latencies := append([]time.Duration{}, ls.latencies...)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		chainStats := &rpcpb.ChainLoadStats{
+			Sent:       ls.sent,
+			Accepted:   ls.accepted,
+			Failed:     ls.failed,
+			LatencyP50: int64(latencyPercentile(latencies, 50)),
+			LatencyP90: int64(latencyPercentile(latencies, 90)),
+			LatencyP99: int64(latencyPercentile(latencies, 99)),
+		}
+		if elapsed > 0 {
+			chainStats.AcceptedPerSecond = float64(ls.accepted) / elapsed.Seconds()
+		}
+		report.Chains[chain] = chainStats
+	}
+	return report
+}
+
+// latencyPercentile returns the [p]th percentile of [sorted] by nearest rank,
+// or zero if empty
+func latencyPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func (s *server) StartLoad(ctx context.Context, req *rpcpb.StartLoadRequest) (*rpcpb.StartLoadResponse, error) {
+	zap.L().Info("received start load request",
+		zap.Strings("chains", req.Chains),
+		zap.Uint32("rate", req.Rate),
+		zap.Uint32("num-keys", req.NumKeys),
+		zap.Bool("contract-calls", req.ContractCalls),
+		zap.Duration("duration", time.Duration(req.Duration)),
+	)
+	if err := checkStartLoadRequest(req); err != nil {
+		return nil, err
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	if s.load != nil && s.load.running() {
+		return nil, ErrLoadStarted
+	}
+
+	lg := newLoadGenerator(req)
+	senders, closeClients, err := s.network.setupLoad(ctx, lg)
+	if err != nil {
+		return nil, err
+	}
+	// the run outlives this request
+	var loadCtx context.Context
+	if lg.duration > 0 {
+		loadCtx, lg.cancel = context.WithTimeout(context.Background(), lg.duration)
+	} else {
+		loadCtx, lg.cancel = context.WithCancel(context.Background())
+	}
+	lg.startTime = time.Now()
+	s.load = lg
+	go lg.run(loadCtx, senders, s.network.stopCh, closeClients)
+
+	return &rpcpb.StartLoadResponse{ClusterInfo: s.clusterInfo, Report: lg.report()}, nil
+}
+
+func (s *server) StopLoad(ctx context.Context, req *rpcpb.StopLoadRequest) (*rpcpb.StopLoadResponse, error) {
+	zap.L().Info("received stop load request")
+
+	s.mu.RLock()
+	lg := s.load
+	s.mu.RUnlock()
+	if lg == nil {
+		return nil, ErrLoadNotStarted
+	}
+
+	lg.cancel()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-lg.doneCh:
+	}
+
+	s.mu.Lock()
+	if s.load == lg {
+		s.load = nil
+	}
+	s.mu.Unlock()
+
+	return &rpcpb.StopLoadResponse{Report: lg.report()}, nil
+}
+
+func (s *server) LoadStatus(ctx context.Context, req *rpcpb.LoadStatusRequest) (*rpcpb.LoadStatusResponse, error) {
+	zap.L().Debug("received load status request")
+
+	s.mu.RLock()
+	lg := s.load
+	s.mu.RUnlock()
+	if lg == nil {
+		return nil, ErrLoadNotStarted
+	}
+	return &rpcpb.LoadStatusResponse{Report: lg.report()}, nil
+}
+
+// run paces [senders] by chain until [ctx] is done or the network is stopped,
+// then closes the clients they use
+func (lg *loadGenerator) run(ctx context.Context, senders map[string][]loadSender, stopCh <-chan struct{}, closeClients func()) {
+	color.Outf("{{magenta}}{{bold}}load started at %d txs per second per chain{{/}}\n", lg.rate)
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	wg := sync.WaitGroup{}
+	for chain, chainSenders := range senders {
+		tokens := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lg.pace(ctx, tokens)
+		}()
+		for _, send := range chainSenders {
+			wg.Add(1)
+			go func(chain string, send loadSender) {
+				defer wg.Done()
+				lg.sendLoop(ctx, chain, tokens, send)
+			}(chain, send)
+		}
+	}
+	wg.Wait()
+	cancel()
+	closeClients()
+
+	lg.statsMu.Lock()
+	lg.endTime = time.Now()
+	lg.statsMu.Unlock()
+	close(lg.doneCh)
+	color.Outf("{{magenta}}{{bold}}load done{{/}}\n")
+}
+
+// pace sends a token to [tokens] [lg.rate] times per second,
+// dropped if all the senders are busy, until [ctx] is done
+func (lg *loadGenerator) pace(ctx context.Context, tokens chan<- struct{}) {
+	ticker := time.NewTicker(time.Second / time.Duration(lg.rate))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		select {
+		case tokens <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// sendLoop sends a tx with [send] for every token taken from [tokens], until [ctx] is done
+func (lg *loadGenerator) sendLoop(ctx context.Context, chain string, tokens <-chan struct{}, send loadSender) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tokens:
+		}
+		lg.recordSent(chain)
+		latency, err := send(ctx)
+		if ctx.Err() != nil {
+			// interrupted by the end of the load
+			return
+		}
+		if err != nil {
+			zap.L().Debug("load tx failed", zap.String("chain", chain), zap.Error(err))
+		}
+		lg.recordOutcome(chain, latency, err)
+	}
+}
+
+// setupLoad generates the keys of [lg] and funds them from the pre-funded test key on each chain,
+// and returns the senders of their txs by chain, spread across the nodes,
+// with a function closing the clients they use
+func (lc *localNetwork) setupLoad(ctx context.Context, lg *loadGenerator) (map[string][]loadSender, func(), error) {
+	keys := make([]*crypto.PrivateKeySECP256K1R, lg.numKeys)
+	factory := crypto.FactorySECP256K1R{}
+	for i := range keys {
+		key, err := factory.NewPrivateKey()
+		if err != nil {
+			return nil, nil, err
+		}
+		keys[i] = key.(*crypto.PrivateKeySECP256K1R)
+	}
+
+	closers := []func(){}
+	closeClients := func() {
+		for _, closeFunc := range closers {
+			closeFunc()
+		}
+	}
+	senders := make(map[string][]loadSender, len(lg.chains))
+	for _, chain := range lg.chains {
+		var (
+			chainSenders []loadSender
+			err          error
+		)
+		if chain == loadChainX {
+			chainSenders, err = lc.setupXChainLoad(ctx, keys)
+		} else {
+			var (
+				clients   []evmClient
+				closeFunc func()
+			)
+			clients, closeFunc, err = lc.loadEVMClients(chain)
+			if err == nil {
+				closers = append(closers, closeFunc)
+				println()
+				color.Outf("{{green}}funding %d load keys on chain %q{{/}}\n", len(keys), chain)
+				chainSenders, err = setupEVMLoad(ctx, clients, keys, lg.contractCalls)
+			}
+		}
+		if err != nil {
+			closeClients()
+			return nil, nil, fmt.Errorf("load of chain %q: %w", chain, err)
+		}
+		senders[chain] = chainSenders
+	}
+	return senders, closeClients, nil
+}
+
+// setupXChainLoad funds [keys] on the SwapChain, and returns senders of transfers
+// from each key to the next one, through the nodes in turn
+func (lc *localNetwork) setupXChainLoad(ctx context.Context, keys []*crypto.PrivateKeySECP256K1R) ([]loadSender, error) {
+	httpRPCEp := lc.nodeInfos[lc.nodeNames[0]].Uri
+	baseWallet, axcAssetID, _, err := lc.setupWallet(ctx, httpRPCEp)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]ids.ShortID, len(keys))
+	outs := make([]*axc.TransferableOutput, len(keys))
+	for i, key := range keys {
+		addrs[i] = key.PublicKey().Address()
+		outs[i] = transferableOutput(axcAssetID, loadKeyFunds, addrs[i])
+	}
+	println()
+	color.Outf("{{green}}funding %d load keys on the SwapChain{{/}}\n", len(keys))
+	cctx, cancel := createDefaultCtx(ctx)
+	_, err = baseWallet.X().IssueBaseTx(outs, common.WithContext(cctx), defaultPoll)
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	senders := make([]loadSender, len(keys))
+	for i, key := range keys {
+		// fetches the funds from the node that accepted them
+		wallet, err := createRefreshableWallet(ctx, httpRPCEp, secp256k1fx.NewKeychain(key))
+		if err != nil {
+			return nil, err
+		}
+		wallet.refresh(lc.nodeInfos[lc.nodeNames[i%len(lc.nodeNames)]].Uri)
+		out := transferableOutput(axcAssetID, loadTransferAmount, addrs[(i+1)%len(addrs)])
+		senders[i] = func(ctx context.Context) (time.Duration, error) {
+			ctx, cancel := context.WithTimeout(ctx, loadTxTimeout)
+			defer cancel()
+			start := time.Now()
+			if _, err := wallet.X().IssueBaseTx([]*axc.TransferableOutput{out}, common.WithContext(ctx), loadPoll); err != nil {
+				return 0, err
+			}
+			return time.Since(start), nil
+		}
+	}
+	return senders, nil
+}
+
+// loadEVMClients returns clients of EVM chain [chain], the AXChain or the blockchain of a custom VM,
+// for each node running it, with a function closing them
+func (lc *localNetwork) loadEVMClients(chain string) ([]evmClient, func(), error) {
+	if chain == loadChainC {
+		clients := make([]evmClient, 0, len(lc.nodeNames))
+		for _, nodeName := range lc.nodeNames {
+			nd, err := lc.nw.GetNode(nodeName)
+			if err != nil {
+				return nil, nil, err
+			}
+			// the client is closed with the node
+			clients = append(clients, nd.GetAPIClient().CChainEthAPI())
+		}
+		return clients, func() {}, nil
+	}
+
+	subnetID, blockchainID, ok := lc.customVMBlockchain(chain)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: no chain or custom VM blockchain %q", ErrInvalidChain, chain)
+	}
+	ethClients := []ethclient.Client{}
+	closeFunc := func() {
+		for _, ethClient := range ethClients {
+			ethClient.Close()
+		}
+	}
+	clients := []evmClient{}
+	for _, nodeName := range lc.nodeNames {
+		nodeInfo := lc.nodeInfos[nodeName]
+		if !containsSubnet(nodeInfo.WhitelistedSubnets, subnetID) {
+			continue
+		}
+		ethClient, err := ethclient.Dial(fmt.Sprintf("%s/ext/bc/%s/rpc", nodeInfo.Uri, blockchainID))
+		if err != nil {
+			closeFunc()
+			return nil, nil, err
+		}
+		ethClients = append(ethClients, ethClient)
+		clients = append(clients, ethClient)
+	}
+	if len(clients) == 0 {
+		return nil, nil, fmt.Errorf("%w: no node runs the blockchain of %q", ErrInvalidChain, chain)
+	}
+	return clients, closeFunc, nil
+}
+
+// setupEVMLoad funds [keys] on the EVM chain of [clients] from the pre-funded test key, deploying
+// a contract if [contractCalls], and returns senders of value transfers from each key to the next one,
+// or of calls to the contract, through [clients] in turn
+func setupEVMLoad(ctx context.Context, clients []evmClient, keys []*crypto.PrivateKeySECP256K1R, contractCalls bool) ([]loadSender, error) {
+	ecdsaKeys := make([]*ecdsa.PrivateKey, len(keys))
+	addrs := make([]ethcommon.Address, len(keys))
+	for i, key := range keys {
+		ecdsaKey, err := ethcrypto.ToECDSA(key.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		ecdsaKeys[i] = ecdsaKey
+		addrs[i] = ethcrypto.PubkeyToAddress(ecdsaKey.PublicKey)
+	}
+	fundKey, err := ethcrypto.ToECDSA(prefundedKey.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	funder := clients[0]
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	chainID, err := funder.ChainID(cctx)
+	if err != nil {
+		return nil, err
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	nonce, err := funder.NonceAt(cctx, ethcrypto.PubkeyToAddress(fundKey.PublicKey), nil)
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := funder.SuggestGasPrice(cctx)
+	if err != nil {
+		return nil, err
+	}
+	fundTxs := make([]*types.Transaction, 0, len(addrs)+1)
+	value := new(big.Int).Mul(new(big.Int).SetUint64(loadKeyFunds), big.NewInt(x2cRate))
+	for _, addr := range addrs {
+		fundTxs = append(fundTxs, types.NewTransaction(nonce, addr, value, cChainTransferGas, gasPrice, nil))
+		nonce++
+	}
+	if contractCalls {
+		fundTxs = append(fundTxs, types.NewContractCreation(nonce, big.NewInt(0), loadContractDeployGas, gasPrice, loadContractCode))
+	}
+	var contractAddr ethcommon.Address
+	for i, tx := range fundTxs {
+		signedTx, err := types.SignTx(tx, signer, fundKey)
+		if err != nil {
+			return nil, err
+		}
+		if err := funder.SendTransaction(cctx, signedTx); err != nil {
+			return nil, err
+		}
+		fundTxs[i] = signedTx
+	}
+	for _, tx := range fundTxs {
+		receipt, err := waitForEVMReceipt(cctx, funder, tx.Hash(), cChainReceiptPollInterval)
+		if err != nil {
+			return nil, err
+		}
+		if tx.To() == nil {
+			contractAddr = receipt.ContractAddress
+		}
+	}
+
+	senders := make([]loadSender, len(keys))
+	for i := range keys {
+		client, key := clients[i%len(clients)], ecdsaKeys[i]
+		to, value, gas := addrs[(i+1)%len(addrs)], new(big.Int).Mul(big.NewInt(loadTransferAmount), big.NewInt(x2cRate)), uint64(cChainTransferGas)
+		if contractCalls {
+			to, value, gas = contractAddr, big.NewInt(0), loadContractCallGas
+		}
+		// the key is new, and only used by this sender
+		nonce, resync := uint64(0), false
+		senders[i] = func(ctx context.Context) (time.Duration, error) {
+			ctx, cancel := context.WithTimeout(ctx, loadTxTimeout)
+			defer cancel()
+			if resync {
+				n, err := client.NonceAt(ctx, ethcrypto.PubkeyToAddress(key.PublicKey), nil)
+				if err != nil {
+					return 0, err
+				}
+				nonce, resync = n, false
+			}
+			gasPrice, err := client.SuggestGasPrice(ctx)
+			if err != nil {
+				return 0, err
+			}
+			signedTx, err := types.SignTx(types.NewTransaction(nonce, to, value, gas, gasPrice, nil), signer, key)
+			if err != nil {
+				return 0, err
+			}
+			start := time.Now()
+			if err := client.SendTransaction(ctx, signedTx); err != nil {
+				resync = true
+				return 0, err
+			}
+			nonce++
+			if _, err := waitForEVMReceipt(ctx, client, signedTx.Hash(), loadPollInterval); err != nil {
+				return 0, err
+			}
+			return time.Since(start), nil
+		}
+	}
+	return senders, nil
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckStartLoadRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *rpcpb.StartLoadRequest
+		err  error
+	}{
+		{name: "defaults", req: &rpcpb.StartLoadRequest{}},
+		{name: "valid", req: &rpcpb.StartLoadRequest{Chains: []string{"X", "C", "subnetevm"}, Rate: 100, NumKeys: 20}},
+		{name: "rate too high", req: &rpcpb.StartLoadRequest{Rate: maxLoadRate + 1}, err: ErrInvalidLoad},
+		{name: "too many keys", req: &rpcpb.StartLoadRequest{NumKeys: maxLoadNumKeys + 1}, err: ErrInvalidLoad},
+		{name: "negative duration", req: &rpcpb.StartLoadRequest{Duration: -1}, err: ErrInvalidLoad},
+		{name: "empty chain", req: &rpcpb.StartLoadRequest{Chains: []string{"X", ""}}, err: ErrInvalidLoad},
+		{name: "duplicate chain", req: &rpcpb.StartLoadRequest{Chains: []string{"C", "C"}}, err: ErrInvalidLoad},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStartLoadRequest(tt.req)
+			if tt.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, tt.err), err)
+		})
+	}
+}
+
+func TestNewLoadGenerator(t *testing.T) {
+	assert := assert.New(t)
+
+	lg := newLoadGenerator(&rpcpb.StartLoadRequest{})
+	assert.Equal([]string{loadChainX, loadChainC}, lg.chains)
+	assert.EqualValues(defaultLoadRate, lg.rate)
+	assert.EqualValues(defaultLoadNumKeys, lg.numKeys)
+	assert.Zero(lg.duration)
+	assert.True(lg.running())
+
+	lg = newLoadGenerator(&rpcpb.StartLoadRequest{Chains: []string{"subnetevm"}, Rate: 5, NumKeys: 3, Duration: int64(time.Minute)})
+	assert.Equal([]string{"subnetevm"}, lg.chains)
+	assert.EqualValues(5, lg.rate)
+	assert.EqualValues(3, lg.numKeys)
+	assert.Equal(time.Minute, lg.duration)
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Zero(latencyPercentile(nil, 50))
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	assert.Equal(50*time.Millisecond, latencyPercentile(latencies, 50))
+	assert.Equal(90*time.Millisecond, latencyPercentile(latencies, 90))
+	assert.Equal(99*time.Millisecond, latencyPercentile(latencies, 99))
+	assert.Equal(time.Millisecond, latencyPercentile(latencies, 0))
+	assert.Equal(7*time.Millisecond, latencyPercentile([]time.Duration{7 * time.Millisecond}, 99))
+}
+
+func TestLoadReport(t *testing.T) {
+	assert := assert.New(t)
+
+	lg := newLoadGenerator(&rpcpb.StartLoadRequest{Chains: []string{"X", "C"}})
+	lg.startTime = time.Now().Add(-10 * time.Second)
+	for i := 1; i <= 10; i++ {
+		lg.recordSent("X")
+		lg.recordOutcome("X", time.Duration(i)*time.Second, nil)
+	}
+	lg.recordSent("X")
+	lg.recordOutcome("X", 0, errors.New("rejected"))
+	lg.recordSent("X")
+
+	report := lg.report()
+	assert.True(report.Running)
+	assert.Zero(report.EndTime)
+	assert.Len(report.Chains, 2)
+	x := report.Chains["X"]
+	assert.EqualValues(12, x.Sent)
+	assert.EqualValues(10, x.Accepted)
+	assert.EqualValues(1, x.Failed)
+	assert.EqualValues(5*time.Second, x.LatencyP50)
+	assert.EqualValues(9*time.Second, x.LatencyP90)
+	assert.EqualValues(10*time.Second, x.LatencyP99)
+	assert.InDelta(1, x.AcceptedPerSecond, 0.1)
+	assert.Zero(report.Chains["C"].Sent)
+
+	// the most recent latencies are kept
+	ls := lg.stats["C"]
+	for i := 0; i < maxLoadLatencies+1; i++ {
+		lg.recordOutcome("C", time.Second, nil)
+	}
+	assert.Len(ls.latencies, maxLoadLatencies)
+	assert.Equal(1, ls.next)
+}
+
+func TestLoadRun(t *testing.T) {
+	assert := assert.New(t)
+
+	lg := newLoadGenerator(&rpcpb.StartLoadRequest{Chains: []string{"X"}, Rate: 1000})
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	lg.cancel = cancel
+	closed := false
+	send := func(ctx context.Context) (time.Duration, error) {
+		return time.Millisecond, nil
+	}
+	lg.startTime = time.Now()
+	lg.run(ctx, map[string][]loadSender{"X": {send, send}}, make(chan struct{}), func() { closed = true })
+
+	assert.False(lg.running())
+	assert.True(closed)
+	report := lg.report()
+	assert.False(report.Running)
+	assert.NotZero(report.EndTime)
+	assert.NotZero(report.Chains["X"].Accepted)
+	// the txs interrupted by the end of the load are not counted as failed
+	assert.Zero(report.Chains["X"].Failed)
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+func (s *server) SetLogLevel(ctx context.Context, req *rpcpb.SetLogLevelRequest) (*rpcpb.SetLogLevelResponse, error) {
+	zap.L().Info("received set log level request",
+		zap.Strings("node-names", req.NodeNames),
+		zap.String("logger-name", req.LoggerName),
+		zap.String("log-level", req.LogLevel),
+		zap.String("display-level", req.DisplayLevel),
+	)
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+	if req.LogLevel == "" && req.DisplayLevel == "" {
+		return nil, ErrNoLogLevel
+	}
+
+	nodes, err := s.getNodes(req.NodeNames)
+	if err != nil {
+		return nil, err
+	}
+
+	errGr, gctx := errgroup.WithContext(ctx)
+	nodeNames := make([]string, 0, len(nodes))
+	for _, nd := range nodes {
+		nd := nd
+		nodeNames = append(nodeNames, nd.GetName())
+		errGr.Go(func() error {
+			if _, err := nd.GetAPIClient().AdminAPI().SetLoggerLevel(gctx, req.LoggerName, req.LogLevel, req.DisplayLevel); err != nil {
+				return fmt.Errorf("failed to set log level of node %q: %w", nd.GetName(), err)
+			}
+			return nil
+		})
+	}
+	if err := errGr.Wait(); err != nil {
+		return nil, err
+	}
+
+	return &rpcpb.SetLogLevelResponse{NodeNames: nodeNames}, nil
+}
@@ -10,12 +10,17 @@ import (
 	"fmt"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/axiacoin/axia-network-runner/local"
 	"github.com/axiacoin/axia-network-runner/network"
+	"github.com/axiacoin/axia-network-runner/network/node"
 	"github.com/axiacoin/axia-network-runner/pkg/color"
 	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia-network-runner/spec"
+	"github.com/axiacoin/axia-network-runner/utils"
 	"github.com/axiacoin/axia/config"
 	"github.com/axiacoin/axia/ids"
 	"github.com/axiacoin/axia/network/peer"
@@ -33,6 +38,9 @@ const (
 		"api-ipcs-enabled":true,
 		"index-enabled":true
   }`
+	// timeout to get the version of a node through its info API,
+	// short as the nodes are healthy when their info is updated
+	nodeVersionTimeout = 2 * time.Second
 )
 
 var ignoreFields = map[string]struct{}{
@@ -52,6 +60,10 @@ type localNetwork struct {
 	nodeNames []string
 	nodeInfos map[string]*rpcpb.NodeInfo
 
+	// map from exec path to the versions reported by the binary
+	binaryVersions   map[string]utils.BinaryVersion
+	binaryVersionsMu sync.RWMutex
+
 	options localNetworkOptions
 
 	// maps from node name to peer ID to peer object
@@ -94,6 +106,21 @@ type localNetworkOptions struct {
 	pluginDir         string
 	customVMs         map[string][]byte
 	customNodeConfigs map[string]string
+	// map from VM name to the nodes validating and tracking its subnet, if not all nodes
+	customVMValidators map[string]*rpcpb.CustomVmValidators
+	// chain config, upgrade and subnet config files for all nodes, and by node name
+	chainConfigs     *rpcpb.ChainConfigs
+	nodeChainConfigs map[string]*rpcpb.ChainConfigs
+	// map from VM name to the JSON-RPC method probing the readiness of its blockchain
+	customVMReadinessProbes map[string]string
+	// map from node name to its binary, if not [execPath]
+	nodeExecPaths map[string]string
+	// versions of the binaries, detected before the start
+	binaryVersions map[string]utils.BinaryVersion
+
+	// if not empty, overrides the default node names, binaries and beacons
+	// there must be one spec per node, with a resolved exec path
+	nodeSpecs []spec.Node
 
 	// to block racey restart while installing custom VMs
 	restartMu *sync.RWMutex
@@ -113,6 +140,11 @@ func newLocalNetwork(opts localNetworkOptions) (*localNetwork, error) {
 		return nil, err
 	}
 
+	binaryVersions := make(map[string]utils.BinaryVersion)
+	for execPath, version := range opts.binaryVersions {
+		binaryVersions[execPath] = version
+	}
+
 	return &localNetwork{
 		logger: logger,
 
@@ -135,6 +167,8 @@ func newLocalNetwork(opts localNetworkOptions) (*localNetwork, error) {
 
 		nodeInfos: make(map[string]*rpcpb.NodeInfo),
 		nodeNames: []string{},
+
+		binaryVersions: binaryVersions,
 	}, nil
 }
 
@@ -156,8 +190,16 @@ func (lc *localNetwork) createConfig() error {
 	}
 
 	for i := range cfg.NodeConfigs {
-		// NOTE: Naming convention for node names is currently `node` + number, i.e. `node1,node2,node3,...node101`
-		nodeName := fmt.Sprintf("node%d", i+1)
+		nodeName := defaultNodeName(i)
+		execPath := lc.options.execPath
+		if len(lc.options.nodeSpecs) > 0 {
+			nodeSpec := lc.options.nodeSpecs[i]
+			nodeName = nodeSpec.Name
+			execPath = nodeSpec.ExecPath
+			cfg.NodeConfigs[i].IsBeacon = nodeSpec.IsBeacon
+		} else if nodeExecPath, ok := lc.options.nodeExecPaths[nodeName]; ok {
+			execPath = nodeExecPath
+		}
 		logDir := filepath.Join(lc.options.rootDataDir, nodeName, "log")
 		dbDir := filepath.Join(lc.options.rootDataDir, nodeName, "db-dir")
 
@@ -169,14 +211,9 @@ func (lc *localNetwork) createConfig() error {
 			return fmt.Errorf("failed merging provided configs: %w", err)
 		}
 
-		// axia expects buildDir (parent dir of pluginDir) to be provided at cmdline
-		buildDir := ""
-		if lc.options.pluginDir != "" {
-			pluginDir := filepath.Clean(lc.options.pluginDir)
-			if filepath.Base(pluginDir) != "plugins" {
-				return fmt.Errorf("plugin dir %q is not named plugins", pluginDir)
-			}
-			buildDir = filepath.Dir(pluginDir)
+		buildDir, err := getBuildDir(lc.options.pluginDir)
+		if err != nil {
+			return err
 		}
 
 		cfg.NodeConfigs[i].ConfigFile, err = createConfigFileString(mergedConfig, logDir, dbDir, buildDir, lc.options.whitelistedSubnets)
@@ -184,7 +221,9 @@ func (lc *localNetwork) createConfig() error {
 			return err
 		}
 
-		cfg.NodeConfigs[i].BinaryPath = lc.options.execPath
+		lc.setNodeConfigFiles(&cfg.NodeConfigs[i], lc.options.whitelistedSubnets)
+
+		cfg.NodeConfigs[i].BinaryPath = execPath
 		cfg.NodeConfigs[i].RedirectStdout = lc.options.redirectNodesOutput
 		cfg.NodeConfigs[i].RedirectStderr = lc.options.redirectNodesOutput
 	}
@@ -193,6 +232,65 @@ func (lc *localNetwork) createConfig() error {
 	return nil
 }
 
+// defaultNodeName returns the name of the [i]th (from 0) default node.
+// NOTE: Naming convention for node names is currently `node` + number, i.e. `node1,node2,node3,...node101`
+func defaultNodeName(i int) string {
+	return fmt.Sprintf("node%d", i+1)
+}
+
+// getBuildDir returns the build dir for [pluginDir], or an empty string if [pluginDir] is empty.
+// axia expects buildDir (parent dir of pluginDir) to be provided at cmdline
+func getBuildDir(pluginDir string) (string, error) {
+	if pluginDir == "" {
+		return "", nil
+	}
+	pluginDir = filepath.Clean(pluginDir)
+	if filepath.Base(pluginDir) != "plugins" {
+		return "", fmt.Errorf("plugin dir %q is not named plugins", pluginDir)
+	}
+	return filepath.Dir(pluginDir), nil
+}
+
+// getNodeConfig returns the config node [nodeName] was started with
+func (lc *localNetwork) getNodeConfig(nodeName string) (node.Config, bool) {
+	for _, nodeConfig := range lc.cfg.NodeConfigs {
+		if nodeConfig.Name == nodeName {
+			return nodeConfig, true
+		}
+	}
+	return node.Config{}, false
+}
+
+// removeNodeConfig forgets the config of node [nodeName], if any
+func (lc *localNetwork) removeNodeConfig(nodeName string) {
+	for i, nodeConfig := range lc.cfg.NodeConfigs {
+		if nodeConfig.Name == nodeName {
+			lc.cfg.NodeConfigs = append(lc.cfg.NodeConfigs[:i], lc.cfg.NodeConfigs[i+1:]...)
+			return
+		}
+	}
+}
+
+// mergeWhitelistedSubnets merges comma-separated lists of subnet IDs
+// into a sorted comma-separated list, without duplicates
+func mergeWhitelistedSubnets(whitelistedSubnets ...string) string {
+	subnetIDs := map[string]struct{}{}
+	for _, subnets := range whitelistedSubnets {
+		for _, subnetID := range strings.Split(subnets, ",") {
+			subnetID = strings.TrimSpace(subnetID)
+			if subnetID != "" {
+				subnetIDs[subnetID] = struct{}{}
+			}
+		}
+	}
+	sortedSubnetIDs := make([]string, 0, len(subnetIDs))
+	for subnetID := range subnetIDs {
+		sortedSubnetIDs = append(sortedSubnetIDs, subnetID)
+	}
+	sort.Strings(sortedSubnetIDs)
+	return strings.Join(sortedSubnetIDs, ",")
+}
+
 // mergeAndCheckForIgnores takes two maps, merging the two and overriding the first with the second
 // if common entries are found.
 // It also skips some entries which are internal to the runner
@@ -281,7 +379,7 @@ func (lc *localNetwork) start(argCtx context.Context) {
 		color.Outf("{{orange}}{{bold}}custom VM not specified, skipping installation and its health checks...{{/}}\n")
 		return
 	}
-	if err := lc.installCustomVMs(ctx); err != nil {
+	if err := lc.installCustomVMs(ctx, lc.customVMNameToGenesis, lc.options.customVMValidators); err != nil {
 		lc.startErrCh <- err
 		return
 	}
@@ -296,7 +394,7 @@ func (lc *localNetwork) loadSnapshot(ctx context.Context, snapshotName string) e
 		close(lc.startDoneCh)
 	}()
 	color.Outf("{{blue}}{{bold}}create and run local network from snapshot{{/}}\n")
-	nw, err := local.NewNetworkFromSnapshot(lc.logger, snapshotName, lc.options.rootDataDir, lc.options.snapshotsDir)
+	nw, err := local.NewNetworkFromSnapshot(lc.logger, snapshotName, lc.options.rootDataDir, lc.options.snapshotsDir, lc.options.execPath)
 	if err != nil {
 		return err
 	}
@@ -357,7 +455,7 @@ func (lc *localNetwork) waitForLocalClusterReady(ctx context.Context) error {
 		return err
 	}
 
-	if err := lc.updateNodeInfo(); err != nil {
+	if err := lc.updateNodeInfo(ctx); err != nil {
 		return err
 	}
 
@@ -371,11 +469,12 @@ func (lc *localNetwork) waitForLocalClusterReady(ctx context.Context) error {
 	return nil
 }
 
-func (lc *localNetwork) updateNodeInfo() error {
+func (lc *localNetwork) updateNodeInfo(ctx context.Context) error {
 	nodes, err := lc.nw.GetAllNodes()
 	if err != nil {
 		return err
 	}
+	nodeVersions := getNodeVersions(ctx, nodes)
 	nodeNames := []string{}
 	for name := range nodes {
 		nodeNames = append(nodeNames, name)
@@ -411,6 +510,8 @@ func (lc *localNetwork) updateNodeInfo() error {
 			}
 		}
 
+		binaryVersion := lc.getBinaryVersion(node.GetBinaryPath())
+
 		lc.nodeInfos[name] = &rpcpb.NodeInfo{
 			Name:               node.GetName(),
 			Uri:                fmt.Sprintf("http://%s:%d", node.GetURL(), node.GetAPIPort()),
@@ -421,11 +522,42 @@ func (lc *localNetwork) updateNodeInfo() error {
 			Config:             []byte(node.GetConfigFile()),
 			PluginDir:          pluginDir,
 			WhitelistedSubnets: whitelistedSubnets,
+			NodeVersion:        nodeVersions[name],
+			AppVersion:         binaryVersion.App,
+			DbVersion:          binaryVersion.Database,
 		}
 	}
 	return nil
 }
 
+// getNodeVersions returns the versions reported by the info API of [nodes], by node name.
+// The nodes are queried concurrently, and the ones failing to answer are left out.
+func getNodeVersions(ctx context.Context, nodes map[string]node.Node) map[string]string {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		versions = make(map[string]string, len(nodes))
+	)
+	for name, nd := range nodes {
+		wg.Add(1)
+		go func(name string, nd node.Node) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, nodeVersionTimeout)
+			versionReply, err := nd.GetAPIClient().InfoAPI().GetNodeVersion(cctx)
+			cancel()
+			if err != nil {
+				zap.L().Warn("failed to get node version", zap.String("node-name", name), zap.Error(err))
+				return
+			}
+			mu.Lock()
+			versions[name] = versionReply.Version
+			mu.Unlock()
+		}(name, nd)
+	}
+	wg.Wait()
+	return versions
+}
+
 func (lc *localNetwork) stop(ctx context.Context) {
 	lc.stopOnce.Do(func() {
 		close(lc.stopCh)
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia/ids"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -152,3 +154,73 @@ func TestEvalConfig(t *testing.T) {
 	assert.NotEqual(controlMap["staking-port"], float64(11111))
 	assert.NotEqual(controlMap["http-port"], float64(5555))
 }
+
+func TestMergeWhitelistedSubnets(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", mergeWhitelistedSubnets())
+	assert.Equal("", mergeWhitelistedSubnets("", " , "))
+	assert.Equal("a,b", mergeWhitelistedSubnets("b, a"))
+	assert.Equal("a,b,c", mergeWhitelistedSubnets("c,a", "b,a", ""))
+}
+
+func TestSameConfigFile(t *testing.T) {
+	assert := assert.New(t)
+
+	same, err := sameConfigFile(`{"a":1,"b":{"c":"d"}}`, `{ "b": {"c": "d"}, "a": 1.0 }`)
+	assert.NoError(err)
+	assert.True(same)
+
+	same, err = sameConfigFile(`{"a":1}`, `{"a":2}`)
+	assert.NoError(err)
+	assert.False(same)
+
+	_, err = sameConfigFile(`{"a":1}`, `not json`)
+	assert.Error(err)
+}
+
+func TestSpecWhitelistedSubnets(t *testing.T) {
+	assert := assert.New(t)
+
+	// the custom VM of subnet1 was installed on node1 only, the one of subnet2 on both nodes
+	subnet1, subnet2 := ids.GenerateTestID(), ids.GenerateTestID()
+	lc := &localNetwork{
+		customVMIDToInfo: map[ids.ID]vmInfo{
+			ids.GenerateTestID(): {subnetID: subnet1},
+			ids.GenerateTestID(): {subnetID: subnet2},
+		},
+		nodeInfos: map[string]*rpcpb.NodeInfo{
+			"node1": {WhitelistedSubnets: mergeWhitelistedSubnets(subnet1.String(), subnet2.String())},
+			"node2": {WhitelistedSubnets: mergeWhitelistedSubnets("spec", subnet2.String())},
+		},
+	}
+
+	// the running nodes keep the subnets they track, so that they are not restarted
+	assert.Equal(lc.nodeInfos["node1"].WhitelistedSubnets, lc.specWhitelistedSubnets("", "node1"))
+	assert.Equal(lc.nodeInfos["node2"].WhitelistedSubnets, lc.specWhitelistedSubnets("spec", "node2"))
+	// the subnets of the spec are merged, and the ones dropped from it are not kept
+	assert.Equal(mergeWhitelistedSubnets("other", subnet2.String()), lc.specWhitelistedSubnets("other", "node2"))
+	// the added nodes track all the custom VM subnets
+	assert.Equal(mergeWhitelistedSubnets("spec", subnet1.String(), subnet2.String()), lc.specWhitelistedSubnets("spec", "node3"))
+}
+
+func TestMergeUpgradeConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	configFile, err := mergeUpgradeConfig(
+		`{"log-dir":"/logs","db-dir":"/db","log-level":"INFO","http-port":9650}`,
+		map[string]interface{}{"log-level": "DEBUG", "log-dir": "/other", "build-dir": "/build", "http-port": 1},
+	)
+	assert.NoError(err)
+	var configFileMap map[string]interface{}
+	assert.NoError(json.Unmarshal([]byte(configFile), &configFileMap))
+	assert.Equal(map[string]interface{}{
+		"log-dir":   "/logs",
+		"db-dir":    "/db",
+		"log-level": "DEBUG",
+		"http-port": float64(9650),
+	}, configFileMap)
+
+	_, err = mergeUpgradeConfig(`not json`, nil)
+	assert.Error(err)
+}
@@ -0,0 +1,170 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/network/node"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia/api/admin"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultCPUProfileDuration = 10 * time.Second
+	// timeout to stop the CPU profiler of a node, once the request is done
+	stopCPUProfilerTimeout = 10 * time.Second
+
+	cpuProfileType    = "cpu"
+	memoryProfileType = "memory"
+	lockProfileType   = "lock"
+)
+
+// files the axia profiler writes to in the node's profile dir
+var profileFileNames = map[string]string{
+	cpuProfileType:    "cpu.profile",
+	memoryProfileType: "mem.profile",
+	lockProfileType:   "lock.profile",
+}
+
+func (s *server) CollectProfiles(ctx context.Context, req *rpcpb.CollectProfilesRequest) (*rpcpb.CollectProfilesResponse, error) {
+	zap.L().Info("received collect profiles request", zap.Strings("node-names", req.NodeNames))
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	nodes, err := s.getNodes(req.NodeNames)
+	if err != nil {
+		return nil, err
+	}
+
+	profileTypes := []string{}
+	if req.Cpu {
+		profileTypes = append(profileTypes, cpuProfileType)
+	}
+	if req.Memory {
+		profileTypes = append(profileTypes, memoryProfileType)
+	}
+	if req.Lock {
+		profileTypes = append(profileTypes, lockProfileType)
+	}
+	if len(profileTypes) == 0 {
+		profileTypes = []string{cpuProfileType, memoryProfileType, lockProfileType}
+	}
+	cpuDuration := time.Duration(req.CpuDuration)
+	if cpuDuration <= 0 {
+		cpuDuration = defaultCPUProfileDuration
+	}
+
+	// profile all nodes at once, so that the profiles cover the same period
+	var (
+		profilesLock sync.Mutex
+		profiles     = []*rpcpb.Profile{}
+	)
+	errGr, gctx := errgroup.WithContext(ctx)
+	for _, nd := range nodes {
+		nd := nd
+		errGr.Go(func() error {
+			nodeProfiles, err := collectNodeProfiles(gctx, nd, profileTypes, cpuDuration, req.GetOutputDir())
+			if err != nil {
+				return fmt.Errorf("failed to collect profiles for node %q: %w", nd.GetName(), err)
+			}
+			profilesLock.Lock()
+			profiles = append(profiles, nodeProfiles...)
+			profilesLock.Unlock()
+			return nil
+		})
+	}
+	if err := errGr.Wait(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return profiles[i].NodeName < profiles[j].NodeName
+	})
+
+	return &rpcpb.CollectProfilesResponse{Profiles: profiles}, nil
+}
+
+// takeCPUProfile profiles the CPU of the node of [adminClient] for [duration].
+// The profiler is stopped even if [ctx] is done before, so that the node does not keep profiling.
+func takeCPUProfile(ctx context.Context, adminClient admin.Client, duration time.Duration) (err error) {
+	if _, err := adminClient.StartCPUProfiler(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		sctx, cancel := context.WithTimeout(context.Background(), stopCPUProfilerTimeout)
+		defer cancel()
+		if _, serr := adminClient.StopCPUProfiler(sctx); serr != nil && err == nil {
+			err = serr
+		}
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(duration):
+		return nil
+	}
+}
+
+// collectNodeProfiles takes the profiles of type [profileTypes] through the node's admin API,
+// and reads them back from its profile dir.
+// If [outputDir] is not empty, the profiles are copied to it instead of being returned.
+func collectNodeProfiles(
+	ctx context.Context,
+	nd node.Node,
+	profileTypes []string,
+	cpuDuration time.Duration,
+	outputDir string,
+) ([]*rpcpb.Profile, error) {
+	adminClient := nd.GetAPIClient().AdminAPI()
+	profiles := make([]*rpcpb.Profile, 0, len(profileTypes))
+	for _, profileType := range profileTypes {
+		zap.L().Info("taking profile", zap.String("node-name", nd.GetName()), zap.String("type", profileType))
+		var err error
+		switch profileType {
+		case cpuProfileType:
+			err = takeCPUProfile(ctx, adminClient, cpuDuration)
+		case memoryProfileType:
+			_, err = adminClient.MemoryProfile(ctx)
+		case lockProfileType:
+			_, err = adminClient.LockProfile(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fileName := profileFileNames[profileType]
+		data, err := os.ReadFile(filepath.Join(nd.GetProfilesDir(), fileName))
+		if err != nil {
+			return nil, err
+		}
+		profile := &rpcpb.Profile{
+			NodeName: nd.GetName(),
+			Type:     profileType,
+			FileName: fileName,
+		}
+		if outputDir == "" {
+			profile.Data = data
+		} else {
+			nodeOutputDir := filepath.Join(outputDir, nd.GetName())
+			if err := os.MkdirAll(nodeOutputDir, os.ModePerm); err != nil {
+				return nil, err
+			}
+			profile.Path = filepath.Join(nodeOutputDir, fileName)
+			if err := os.WriteFile(profile.Path, data, 0o644); err != nil {
+				return nil, err
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/network/node"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia-network-runner/utils"
+	"go.uber.org/zap"
+)
+
+// interval between samples of the resource usage of the nodes
+const resourceUsageInterval = 5 * time.Second
+
+// resourceUsageLoop samples the resource usage of the running nodes
+// every [resourceUsageInterval], until the server is closed
+func (s *server) resourceUsageLoop(ctx context.Context) {
+	tc := time.NewTicker(resourceUsageInterval)
+	defer tc.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.closed:
+			return
+		case <-tc.C:
+		}
+		s.updateResourceUsage()
+	}
+}
+
+// updateResourceUsage samples the resource usage of the running nodes, if the network is healthy.
+// The samples are kept apart from the node infos, and merged into the copies of the cluster info.
+func (s *server) updateResourceUsage() {
+	s.mu.RLock()
+	if s.network == nil || s.network.nw == nil || s.clusterInfo == nil || !s.clusterInfo.Healthy {
+		s.mu.RUnlock()
+		return
+	}
+	lc := s.network
+	nw := lc.nw
+	s.mu.RUnlock()
+
+	// sample without holding the lock, walking db dirs may take a while
+	nodes, err := nw.GetAllNodes()
+	if err != nil {
+		zap.L().Debug("failed to get nodes for resource usage", zap.Error(err))
+		return
+	}
+	usages := make(map[string]*rpcpb.ResourceUsage, len(nodes))
+	for nodeName, nd := range nodes {
+		usages[nodeName] = getResourceUsage(nd)
+	}
+
+	s.resourceUsagesMu.Lock()
+	s.resourceUsagesNetwork = lc
+	s.resourceUsages = usages
+	s.resourceUsagesMu.Unlock()
+}
+
+// setResourceUsage sets the last sampled resource usage of the nodes of [info],
+// a copy of the cluster info of network [lc]
+func (s *server) setResourceUsage(info *rpcpb.ClusterInfo, lc *localNetwork) {
+	s.resourceUsagesMu.Lock()
+	defer s.resourceUsagesMu.Unlock()
+	// sampled on a previous network
+	if lc == nil || s.resourceUsagesNetwork != lc {
+		return
+	}
+	for nodeName, nodeInfo := range info.NodeInfos {
+		// the samples are never updated, so they can be shared by the copies
+		nodeInfo.ResourceUsage = s.resourceUsages[nodeName]
+	}
+}
+
+// getResourceUsage returns nil if the node's process isn't running
+func getResourceUsage(nd node.Node) *rpcpb.ResourceUsage {
+	if exited, _ := nd.GetExitStatus(); exited {
+		return nil
+	}
+	pid := nd.GetPID()
+	if pid == 0 {
+		return nil
+	}
+	usage := &rpcpb.ResourceUsage{Pid: int32(pid)}
+	procUsage, err := utils.GetResourceUsage(pid)
+	if err != nil {
+		zap.L().Debug("failed to get process resource usage", zap.String("node-name", nd.GetName()), zap.Error(err))
+	} else {
+		usage.CpuSeconds = procUsage.CPUSeconds
+		usage.RssBytes = procUsage.RSSBytes
+		usage.OpenFds = procUsage.OpenFDs
+		usage.Threads = procUsage.Threads
+	}
+	dbDirSize, err := utils.DirSize(nd.GetDbDir())
+	if err != nil {
+		zap.L().Debug("failed to get db dir size", zap.String("node-name", nd.GetName()), zap.Error(err))
+	} else {
+		usage.DbDirSizeBytes = dbDirSize
+	}
+	return usage
+}
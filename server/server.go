@@ -22,7 +22,9 @@ import (
 
 	"github.com/axiacoin/axia-network-runner/network/node"
 	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia-network-runner/spec"
 	"github.com/axiacoin/axia-network-runner/utils"
+	"github.com/axiacoin/axia/ids"
 	"github.com/axiacoin/axia/message"
 	"github.com/axiacoin/axia/network/peer"
 	"github.com/axiacoin/axia/snow/networking/router"
@@ -33,6 +35,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 type Config struct {
@@ -66,6 +69,23 @@ type server struct {
 	mu          *sync.RWMutex
 	clusterInfo *rpcpb.ClusterInfo
 	network     *localNetwork
+	chaos       *chaosController
+	load        *loadGenerator
+	// set while InstallCustomVMs runs without holding [mu]
+	installingCustomVMs bool
+
+	// last resource usage of the nodes of [resourceUsagesNetwork], by node name,
+	// kept apart from the cluster info that is updated with [mu] held
+	resourceUsages        map[string]*rpcpb.ResourceUsage
+	resourceUsagesNetwork *localNetwork
+	resourceUsagesMu      sync.Mutex
+
+	// map from alias to registered binary
+	binaries   map[string]*rpcpb.BinaryInfo
+	binariesMu sync.Mutex
+
+	// keys created or imported, kept across networks
+	keys *keychain
 
 	rpcpb.UnimplementedPingServiceServer
 	rpcpb.UnimplementedControlServiceServer
@@ -84,6 +104,39 @@ var (
 	ErrPeerNotFound                       = errors.New("peer not found")
 	ErrUnexpectedType                     = errors.New("unexpected type")
 	ErrStatusCanceled                     = errors.New("gRPC stream status canceled")
+	ErrNoLogLevel                         = errors.New("log level or display level must be set")
+	ErrNetworkNotReady                    = errors.New("network not ready")
+	ErrCustomVMsChanged                   = errors.New("custom VMs can only be installed at start or with InstallCustomVMs")
+	ErrChaosEnabled                       = errors.New("chaos already enabled")
+	ErrChaosNotEnabled                    = errors.New("chaos not enabled")
+	ErrLoadStarted                        = errors.New("load already started")
+	ErrLoadNotStarted                     = errors.New("load not started")
+	ErrInvalidLoad                        = errors.New("invalid load")
+	ErrNoChaosTargets                     = errors.New("no non-beacon node to inject faults into")
+	ErrNotEnoughHealthyValidators         = errors.New("not enough healthy validators")
+	ErrNoExecPath                         = errors.New("exec path must be set")
+	ErrNoBinaryAlias                      = errors.New("binary alias must be set")
+	ErrBinaryChanged                      = errors.New("registered binary changed")
+	ErrInvalidThreshold                   = errors.New("owner threshold exceeds the number of owners")
+	ErrNoSubnetValidators                 = errors.New("no subnet validator specified")
+	ErrDuplicateSubnetValidator           = errors.New("node listed twice as subnet validator")
+	ErrInvalidValidatorPeriod             = errors.New("validator end time must be after its start time")
+	ErrNoPluginDir                        = errors.New("network started without plugin dir")
+	ErrNoCustomVMs                        = errors.New("no custom VM specified")
+	ErrCustomVMsInstalling                = errors.New("custom VMs installation in progress")
+	ErrPluginDirChanged                   = errors.New("plugin dir differs from the one of the network")
+	ErrCustomVMsNotReady                  = errors.New("custom VMs not ready")
+	ErrInvalidAddress                     = errors.New("invalid address")
+	ErrInvalidAmount                      = errors.New("amount must be positive")
+	ErrNoKeyName                          = errors.New("key name must be set")
+	ErrKeyExists                          = errors.New("key already exists")
+	ErrInvalidPrivateKey                  = errors.New("invalid private key")
+	ErrAlreadyValidator                   = errors.New("node already a primary network validator")
+	ErrNotValidator                       = errors.New("node not a primary network validator")
+	ErrInvalidChain                       = errors.New("invalid chain")
+	ErrInvalidAsset                       = errors.New("invalid asset")
+	ErrAssetNotFound                      = errors.New("asset not found")
+	ErrKeyNotFound                        = errors.New("key not found")
 )
 
 const (
@@ -110,6 +163,9 @@ func New(cfg Config) (Server, error) {
 		gRPCServer: grpc.NewServer(),
 
 		mu: new(sync.RWMutex),
+
+		binaries: make(map[string]*rpcpb.BinaryInfo),
+		keys:     newKeychain(),
 	}
 	if !cfg.GwDisabled {
 		srv.gwMux = runtime.NewServeMux()
@@ -130,6 +186,9 @@ func (s *server) Run(rootCtx context.Context) (err error) {
 		rpcpb.RegisterControlServiceServer(s.gRPCServer, s)
 	})
 
+	go s.subnetValidatorsLoop(rootCtx)
+	go s.resourceUsageLoop(rootCtx)
+
 	gRPCErrc := make(chan error)
 	go func() {
 		zap.L().Info("serving gRPC server", zap.String("port", s.cfg.Port))
@@ -229,6 +288,18 @@ func (s *server) Start(ctx context.Context, req *rpcpb.StartRequest) (*rpcpb.Sta
 		zap.L().Info("received start request with existing timeout", zap.String("deadline", deadline.String()))
 	}
 
+	clusterInfo, err := s.start(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.StartResponse{ClusterInfo: clusterInfo}, nil
+}
+
+// start starts a new network, and returns once its nodes are launched.
+// The user is expected to poll the cluster status for its readiness.
+// If [nodeSpecs] is not empty, the network has one node per spec,
+// with the given name, binary and beacon setting.
+func (s *server) start(ctx context.Context, req *rpcpb.StartRequest, nodeSpecs []spec.Node) (*rpcpb.ClusterInfo, error) {
 	if req.NumNodes == nil {
 		n := DefaultNodes
 		req.NumNodes = &n
@@ -236,7 +307,16 @@ func (s *server) Start(ctx context.Context, req *rpcpb.StartRequest) (*rpcpb.Sta
 	if *req.NumNodes < MinNodes {
 		return nil, ErrNotEnoughNodesForStart
 	}
-	customVMs := make(map[string][]byte)
+	var err error
+	req.ExecPath, err = s.resolveExecPath(req.GetExecPath())
+	if err != nil {
+		return nil, err
+	}
+	req.NodeExecPaths, err = s.resolveNodeExecPaths(req.GetNodeExecPaths())
+	if err != nil {
+		return nil, err
+	}
+	var customVMs map[string][]byte
 	if req.GetPluginDir() == "" {
 		if len(req.GetCustomVms()) > 0 {
 			return nil, ErrPluginDirEmptyButCustomVMsNotEmpty
@@ -249,34 +329,32 @@ func (s *server) Start(ctx context.Context, req *rpcpb.StartRequest) (*rpcpb.Sta
 			return nil, ErrPluginDirNonEmptyButCustomVMsEmpty
 		}
 		zap.L().Info("non-empty plugin dir", zap.String("plugin-dir", req.GetPluginDir()))
-		for vmName, vmGenesisFilePath := range req.GetCustomVms() {
-			zap.L().Info("checking custom VM ID before installation", zap.String("vm-id", vmName))
-			vmID, err := utils.VMID(vmName)
-			if err != nil {
-				zap.L().Warn("failed to convert VM name to VM ID",
-					zap.String("vm-name", vmName),
-					zap.Error(err),
-				)
-				return nil, ErrInvalidVMName
-			}
-			if err := utils.CheckExecPluginPaths(
-				req.GetExecPath(),
-				filepath.Join(req.GetPluginDir(), vmID.String()),
-				vmGenesisFilePath,
-			); err != nil {
-				return nil, err
-			}
-			b, err := os.ReadFile(vmGenesisFilePath)
-			if err != nil {
-				return nil, err
-			}
-			customVMs[vmName] = b
+		customVMs, err = readCustomVMs(req.GetExecPath(), req.GetPluginDir(), req.GetCustomVms())
+		if err != nil {
+			return nil, err
 		}
 	}
 	pluginDir := ""
 	if req.GetPluginDir() != "" {
 		pluginDir = req.GetPluginDir()
 	}
+	if len(nodeSpecs) == 0 {
+		numNodes := req.GetNumNodes()
+		if len(req.GetCustomNodeConfigs()) > 0 {
+			numNodes = uint32(len(req.GetCustomNodeConfigs()))
+		}
+		if err := checkNodeExecPaths(req.GetNodeExecPaths(), numNodes); err != nil {
+			return nil, err
+		}
+	}
+	execPaths := []string{req.GetExecPath()}
+	for _, nodeSpec := range nodeSpecs {
+		execPaths = append(execPaths, nodeSpec.ExecPath)
+	}
+	for _, nodeExecPath := range req.GetNodeExecPaths() {
+		execPaths = append(execPaths, nodeExecPath)
+	}
+	binaryVersions := detectBinaryVersions(execPaths...)
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -294,7 +372,6 @@ func (s *server) Start(ctx context.Context, req *rpcpb.StartRequest) (*rpcpb.Sta
 		pid                = int32(os.Getpid())
 		globalNodeConfig   = req.GetGlobalNodeConfig()
 		customNodeConfigs  = req.GetCustomNodeConfigs()
-		err                error
 	)
 	if len(rootDataDir) == 0 {
 		rootDataDir, err = os.MkdirTemp(os.TempDir(), "network-runner-root-data")
@@ -317,27 +394,37 @@ func (s *server) Start(ctx context.Context, req *rpcpb.StartRequest) (*rpcpb.Sta
 		zap.String("rootDataDir", rootDataDir),
 		zap.String("pluginDir", pluginDir),
 		zap.String("defaultNodeConfig", globalNodeConfig),
+		zap.Any("nodeExecPaths", req.GetNodeExecPaths()),
 	)
 
 	if s.network != nil {
 		return nil, ErrAlreadyBootstrapped
 	}
 
-	if len(customNodeConfigs) > 0 {
+	if len(nodeSpecs) > 0 {
+		numNodes = uint32(len(nodeSpecs))
+	} else if len(customNodeConfigs) > 0 {
 		zap.L().Warn("custom node configs have been provided; ignoring the 'number-of-nodes' parameter and setting it to", zap.Int("numNodes", len(customNodeConfigs)))
 		numNodes = uint32(len(customNodeConfigs))
 	}
 
 	s.network, err = newLocalNetwork(localNetworkOptions{
-		execPath:            execPath,
-		rootDataDir:         rootDataDir,
-		numNodes:            numNodes,
-		whitelistedSubnets:  whitelistedSubnets,
-		redirectNodesOutput: s.cfg.RedirectNodesOutput,
-		pluginDir:           pluginDir,
-		customVMs:           customVMs,
-		globalNodeConfig:    globalNodeConfig,
-		customNodeConfigs:   customNodeConfigs,
+		execPath:                execPath,
+		rootDataDir:             rootDataDir,
+		numNodes:                numNodes,
+		whitelistedSubnets:      whitelistedSubnets,
+		redirectNodesOutput:     s.cfg.RedirectNodesOutput,
+		pluginDir:               pluginDir,
+		customVMs:               customVMs,
+		globalNodeConfig:        globalNodeConfig,
+		customNodeConfigs:       customNodeConfigs,
+		customVMValidators:      req.GetCustomVmValidators(),
+		chainConfigs:            req.GetChainConfigs(),
+		nodeChainConfigs:        req.GetNodeChainConfigs(),
+		customVMReadinessProbes: req.GetCustomVmReadinessProbes(),
+		nodeExecPaths:           req.GetNodeExecPaths(),
+		binaryVersions:          binaryVersions,
+		nodeSpecs:               nodeSpecs,
 
 		// to block racey restart
 		// "s.network.start" runs asynchronously
@@ -354,6 +441,18 @@ func (s *server) Start(ctx context.Context, req *rpcpb.StartRequest) (*rpcpb.Sta
 		s.network = nil
 		return nil, err
 	}
+	if err := checkCustomVMValidators(req.GetCustomVmValidators(), customVMs, s.network.nodeNames); err != nil {
+		s.network = nil
+		return nil, err
+	}
+	if err := checkNodeChainConfigs(req.GetNodeChainConfigs(), s.network.nodeNames); err != nil {
+		s.network = nil
+		return nil, err
+	}
+	if err := checkCustomVMReadinessProbes(req.GetCustomVmReadinessProbes(), customVMs); err != nil {
+		s.network = nil
+		return nil, err
+	}
 
 	// start non-blocking to install local cluster + custom VMs (if applicable)
 	// the user is expected to poll cluster status
@@ -391,21 +490,92 @@ func (s *server) Start(ctx context.Context, req *rpcpb.StartRequest) (*rpcpb.Sta
 			case <-s.network.stopCh:
 				return
 			case serr := <-s.network.startErrCh:
+				if errors.Is(serr, ErrCustomVMsNotReady) {
+					// report the custom VMs with the readiness of their blockchains
+					zap.L().Warn("custom VMs failed to become ready", zap.Error(serr))
+					s.mu.Lock()
+					s.clusterInfo.CustomVms = customVMsInfo(s.network.customVMIDToInfo)
+					s.mu.Unlock()
+					return
+				}
 				zap.L().Warn("start custom VMs failed to complete", zap.Error(serr))
 				panic(serr)
 			case <-s.network.customVMsReadyCh:
 				s.mu.Lock()
-				s.clusterInfo.CustomVmsHealthy = true
-				s.clusterInfo.CustomVms = make(map[string]*rpcpb.CustomVmInfo)
-				for vmID, vmInfo := range s.network.customVMIDToInfo {
-					s.clusterInfo.CustomVms[vmID.String()] = vmInfo.info
-				}
+				s.updateCustomVMsInfo()
 				s.mu.Unlock()
 			}
 		}
 	}()
 
-	return &rpcpb.StartResponse{ClusterInfo: s.clusterInfo}, nil
+	return s.clusterInfo, nil
+}
+
+// readCustomVMs checks that the binaries of [customVMs] exist in [pluginDir],
+// and returns their genesis bytes by VM name
+func readCustomVMs(execPath string, pluginDir string, customVMs map[string]string) (map[string][]byte, error) {
+	vmGenesis := make(map[string][]byte, len(customVMs))
+	for vmName, vmGenesisFilePath := range customVMs {
+		zap.L().Info("checking custom VM ID before installation", zap.String("vm-id", vmName))
+		vmID, err := utils.VMID(vmName)
+		if err != nil {
+			zap.L().Warn("failed to convert VM name to VM ID",
+				zap.String("vm-name", vmName),
+				zap.Error(err),
+			)
+			return nil, ErrInvalidVMName
+		}
+		if err := utils.CheckExecPluginPaths(
+			execPath,
+			filepath.Join(pluginDir, vmID.String()),
+			vmGenesisFilePath,
+		); err != nil {
+			return nil, err
+		}
+		b, err := os.ReadFile(vmGenesisFilePath)
+		if err != nil {
+			return nil, err
+		}
+		vmGenesis[vmName] = b
+	}
+	return vmGenesis, nil
+}
+
+// checkNodeExecPaths checks that the keys of [nodeExecPaths] are the names of
+// the [numNodes] default nodes, and that their binaries exist
+func checkNodeExecPaths(nodeExecPaths map[string]string, numNodes uint32) error {
+	for nodeName, execPath := range nodeExecPaths {
+		found := false
+		for i := 0; i < int(numNodes); i++ {
+			if nodeName == defaultNodeName(i) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: %q has an exec path but is not one of the %d nodes", ErrNodeNotFound, nodeName, numNodes)
+		}
+		if err := utils.CheckExecPluginPaths(execPath, "", ""); err != nil {
+			return fmt.Errorf("node %q: %w", nodeName, err)
+		}
+	}
+	return nil
+}
+
+// updateCustomVMsInfo marks the custom VMs as healthy in the cluster info.
+// Assumes [s.mu] is held.
+func (s *server) updateCustomVMsInfo() {
+	s.clusterInfo.CustomVmsHealthy = true
+	s.clusterInfo.CustomVms = customVMsInfo(s.network.customVMIDToInfo)
+}
+
+// customVMsInfo returns the cluster info of the custom VMs [customVMIDToInfo]
+func customVMsInfo(customVMIDToInfo map[ids.ID]vmInfo) map[string]*rpcpb.CustomVmInfo {
+	customVMs := make(map[string]*rpcpb.CustomVmInfo, len(customVMIDToInfo))
+	for vmID, vmInfo := range customVMIDToInfo {
+		customVMs[vmID.String()] = vmInfo.info
+	}
+	return customVMs
 }
 
 func (s *server) Health(ctx context.Context, req *rpcpb.HealthRequest) (*rpcpb.HealthResponse, error) {
@@ -553,35 +723,70 @@ func (s *server) recvLoop(stream rpcpb.ControlService_StreamStatusServer) error
 func (s *server) AddNode(ctx context.Context, req *rpcpb.AddNodeRequest) (*rpcpb.AddNodeResponse, error) {
 	zap.L().Debug("received add node request", zap.String("name", req.Name))
 
+	if err := checkPrimaryValidatorSpec(req.Validator); err != nil {
+		return nil, err
+	}
 	if info := s.getClusterInfo(); info == nil {
 		return nil, ErrNotBootstrapped
 	}
 
+	resp, lc, waitValidator, err := s.addNode(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Validator != nil {
+		info, err := s.waitPrimaryValidator(ctx, lc, waitValidator)
+		if err != nil {
+			return nil, fmt.Errorf("node %q added but not as a validator: %w", req.Name, err)
+		}
+		resp.ClusterInfo = info
+	}
+	return resp, nil
+}
+
+// addNode adds the node of [req], and issues its add validator tx if requested.
+// Returns the network it was added to, and the function waiting for the node
+// to be a current validator, if any.
+func (s *server) addNode(ctx context.Context, req *rpcpb.AddNodeRequest) (*rpcpb.AddNodeResponse, *localNetwork, func(context.Context) error, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.installingCustomVMs {
+		return nil, nil, nil, ErrCustomVMsInstalling
+	}
+	if req.Validator != nil {
+		// the add validator tx is issued through the other nodes
+		if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+			return nil, nil, nil, ErrNetworkNotReady
+		}
+	}
+
 	var whitelistedSubnets, pluginDir string
 
 	if _, exists := s.network.nodeInfos[req.Name]; exists {
-		return nil, fmt.Errorf("node with name %s already exists", req.Name)
+		return nil, nil, nil, fmt.Errorf("node with name %s already exists", req.Name)
 	}
 	// fix if not given
 	if req.StartRequest == nil {
 		req.StartRequest = &rpcpb.StartRequest{}
 	}
 	// user can override bin path for this node...
-	execPath := req.StartRequest.ExecPath
+	execPath, err := s.resolveExecPath(req.StartRequest.ExecPath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
 	if execPath == "" {
 		// ...or use the same binary as the rest of the network
 		execPath = s.network.binPath
 	}
-	_, err := os.Stat(execPath)
+	_, err = os.Stat(execPath)
 	if err != nil {
 		if errors.Is(err, fs.ErrNotExist) {
-			return nil, utils.ErrNotExists
+			return nil, nil, nil, utils.ErrNotExists
 		}
-		return nil, fmt.Errorf("failed to stat exec %q (%w)", execPath, err)
+		return nil, nil, nil, fmt.Errorf("failed to stat exec %q (%w)", execPath, err)
 	}
+	binaryVersions := detectBinaryVersions(execPath)
 
 	// use same configs from other nodes
 	whitelistedSubnets = s.network.options.whitelistedSubnets
@@ -594,11 +799,11 @@ func (s *server) AddNode(ctx context.Context, req *rpcpb.AddNodeRequest) (*rpcpb
 
 	var defaultConfig, globalConfig map[string]interface{}
 	if err := json.Unmarshal([]byte(defaultNodeConfig), &defaultConfig); err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 	if req.StartRequest.GetGlobalNodeConfig() != "" {
 		if err := json.Unmarshal([]byte(req.StartRequest.GetGlobalNodeConfig()), &globalConfig); err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 	}
 
@@ -606,15 +811,15 @@ func (s *server) AddNode(ctx context.Context, req *rpcpb.AddNodeRequest) (*rpcpb
 	// we only need to merge from the default node config here, as we are only adding one node
 	mergedConfig, err = mergeNodeConfig(defaultConfig, globalConfig, "")
 	if err != nil {
-		return nil, fmt.Errorf("failed merging provided configs: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed merging provided configs: %w", err)
 	}
 	configFile, err := createConfigFileString(mergedConfig, logDir, dbDir, pluginDir, whitelistedSubnets)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate json node config string: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to generate json node config string: %w", err)
 	}
 	stakingCert, stakingKey, err := staking.NewCertAndKeyBytes()
 	if err != nil {
-		return nil, fmt.Errorf("couldn't generate staking Cert/Key: %w", err)
+		return nil, nil, nil, fmt.Errorf("couldn't generate staking Cert/Key: %w", err)
 	}
 
 	nodeConfig := node.Config{
@@ -626,12 +831,24 @@ func (s *server) AddNode(ctx context.Context, req *rpcpb.AddNodeRequest) (*rpcpb
 		RedirectStdout: s.cfg.RedirectNodesOutput,
 		RedirectStderr: s.cfg.RedirectNodesOutput,
 	}
-	_, err = s.network.nw.AddNode(nodeConfig)
+	nd, err := s.network.nw.AddNode(nodeConfig)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
+	s.network.setBinaryVersions(binaryVersions)
+	// keep the node config, so that the node can be restarted
+	s.network.cfg.NodeConfigs = append(s.network.cfg.NodeConfigs, nodeConfig)
 
-	return &rpcpb.AddNodeResponse{ClusterInfo: s.clusterInfo}, nil
+	resp := &rpcpb.AddNodeResponse{ClusterInfo: s.clusterInfo}
+	if req.Validator == nil {
+		return resp, s.network, nil, nil
+	}
+	txID, waitValidator, err := s.network.addPrimaryValidator(ctx, nd.GetNodeID(), req.Validator)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("node %q added but not as a validator: %w", req.Name, err)
+	}
+	resp.ValidatorTxId = txID.String()
+	return resp, s.network, waitValidator, nil
 }
 
 func (s *server) RemoveNode(ctx context.Context, req *rpcpb.RemoveNodeRequest) (*rpcpb.RemoveNodeResponse, error) {
@@ -643,6 +860,9 @@ func (s *server) RemoveNode(ctx context.Context, req *rpcpb.RemoveNodeRequest) (
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
 	if _, ok := s.network.nodeInfos[req.Name]; !ok {
 		return nil, ErrNodeNotFound
 	}
@@ -650,6 +870,7 @@ func (s *server) RemoveNode(ctx context.Context, req *rpcpb.RemoveNodeRequest) (
 	if err := s.network.nw.RemoveNode(req.Name); err != nil {
 		return nil, err
 	}
+	s.network.removeNodeConfig(req.Name)
 
 	zap.L().Info("waiting for local cluster readiness")
 	if err := s.network.waitForLocalClusterReady(ctx); err != nil {
@@ -671,10 +892,15 @@ func (s *server) RestartNode(ctx context.Context, req *rpcpb.RestartNodeRequest)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
 	nodeInfo, ok := s.network.nodeInfos[req.Name]
 	if !ok {
 		return nil, ErrNodeNotFound
 	}
+	// the node info is only updated once the node is back
+	nodeInfo = proto.Clone(nodeInfo).(*rpcpb.NodeInfo)
 
 	found, idx := false, 0
 	oldNodeConfig := node.Config{}
@@ -693,7 +919,11 @@ func (s *server) RestartNode(ctx context.Context, req *rpcpb.RestartNodeRequest)
 
 	// use existing value if not specified
 	if req.GetExecPath() != "" {
-		nodeInfo.ExecPath = req.GetExecPath()
+		execPath, err := s.resolveExecPath(req.GetExecPath())
+		if err != nil {
+			return nil, err
+		}
+		nodeInfo.ExecPath = execPath
 	}
 	if req.GetWhitelistedSubnets() != "" {
 		nodeInfo.WhitelistedSubnets = req.GetWhitelistedSubnets()
@@ -702,13 +932,19 @@ func (s *server) RestartNode(ctx context.Context, req *rpcpb.RestartNodeRequest)
 		nodeInfo.DbDir = filepath.Join(req.GetRootDataDir(), req.Name, "db-dir")
 	}
 
+	binaryVersions := detectBinaryVersions(nodeInfo.ExecPath)
+	if !req.Force {
+		if err := utils.CheckDBVersion(nodeInfo.DbDir, binaryVersions[nodeInfo.ExecPath].Database); err != nil {
+			return nil, fmt.Errorf("%w (force to restart anyway)", err)
+		}
+	}
+
 	var defaultConfig map[string]interface{}
 	if err := json.Unmarshal([]byte(defaultNodeConfig), &defaultConfig); err != nil {
 		return nil, err
 	}
 
-	var err error
-	nodeConfig.ConfigFile, err = createConfigFileString(
+	configFile, err := createConfigFileString(
 		defaultConfig,
 		nodeInfo.LogDir,
 		nodeInfo.DbDir,
@@ -718,6 +954,7 @@ func (s *server) RestartNode(ctx context.Context, req *rpcpb.RestartNodeRequest)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate json node config string: %w", err)
 	}
+	nodeConfig.ConfigFile = configFile
 
 	nodeConfig.BinaryPath = nodeInfo.ExecPath
 	nodeConfig.RedirectStdout = s.cfg.RedirectNodesOutput
@@ -734,6 +971,7 @@ func (s *server) RestartNode(ctx context.Context, req *rpcpb.RestartNodeRequest)
 	if _, err := s.network.nw.AddNode(nodeConfig); err != nil {
 		return nil, err
 	}
+	s.network.setBinaryVersions(binaryVersions)
 
 	zap.L().Info("waiting for local cluster readiness")
 	if err := s.network.waitForLocalClusterReady(ctx); err != nil {
@@ -757,12 +995,24 @@ func (s *server) Stop(ctx context.Context, req *rpcpb.StopRequest) (*rpcpb.StopR
 	if s.network == nil {
 		return nil, ErrNotBootstrapped
 	}
+	// the install would use the stopped network
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
 
 	info := s.clusterInfo
 	if info == nil {
 		info = &rpcpb.ClusterInfo{}
 	}
 
+	// the chaos run ends once it sees the network is gone
+	if s.chaos != nil {
+		s.chaos.cancel()
+	}
+	// likewise for the load
+	if s.load != nil {
+		s.load.cancel()
+	}
 	s.network.stop(ctx)
 	s.network = nil
 	s.clusterInfo = nil
@@ -781,12 +1031,23 @@ func (s *server) AttachPeer(ctx context.Context, req *rpcpb.AttachPeerRequest) (
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	node, err := s.network.nw.GetNode((req.NodeName))
+	peerInfo, err := s.attachPeer(ctx, req.NodeName)
 	if err != nil {
 		return nil, err
 	}
 
-	lh := &loggingInboundHandler{nodeName: req.NodeName}
+	return &rpcpb.AttachPeerResponse{ClusterInfo: info, AttachedPeerInfo: peerInfo}, nil
+}
+
+// attachPeer attaches a new test peer to node [nodeName].
+// Assumes [s.mu] is held.
+func (s *server) attachPeer(ctx context.Context, nodeName string) (*rpcpb.AttachedPeerInfo, error) {
+	node, err := s.network.nw.GetNode(nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	lh := &loggingInboundHandler{nodeName: nodeName}
 	newPeer, err := node.AttachPeer(ctx, lh)
 	if err != nil {
 		return nil, err
@@ -801,32 +1062,32 @@ func (s *server) AttachPeer(ctx context.Context, req *rpcpb.AttachPeerRequest) (
 	newPeerID := newPeer.ID().String()
 
 	zap.L().Debug("new peer is attached",
-		zap.String("node-name", req.NodeName),
+		zap.String("node-name", nodeName),
 		zap.String("peer-id", newPeerID),
 	)
 
-	peers, ok := s.network.attachedPeers[req.NodeName]
+	peers, ok := s.network.attachedPeers[nodeName]
 	if !ok {
 		peers = make(map[string]peer.Peer)
 		peers[newPeerID] = newPeer
 	} else {
 		peers[newPeerID] = newPeer
 	}
-	s.network.attachedPeers[req.NodeName] = peers
+	s.network.attachedPeers[nodeName] = peers
 
 	if s.clusterInfo.AttachedPeerInfos == nil {
 		s.clusterInfo.AttachedPeerInfos = make(map[string]*rpcpb.ListOfAttachedPeerInfo)
 	}
 	peerInfo := &rpcpb.AttachedPeerInfo{Id: newPeerID}
-	if v, ok := s.clusterInfo.AttachedPeerInfos[req.NodeName]; ok {
+	if v, ok := s.clusterInfo.AttachedPeerInfos[nodeName]; ok {
 		v.Peers = append(v.Peers, peerInfo)
 	} else {
-		s.clusterInfo.AttachedPeerInfos[req.NodeName] = &rpcpb.ListOfAttachedPeerInfo{
+		s.clusterInfo.AttachedPeerInfos[nodeName] = &rpcpb.ListOfAttachedPeerInfo{
 			Peers: []*rpcpb.AttachedPeerInfo{peerInfo},
 		}
 	}
 
-	return &rpcpb.AttachPeerResponse{ClusterInfo: info, AttachedPeerInfo: peerInfo}, nil
+	return peerInfo, nil
 }
 
 var _ router.InboundHandler = &loggingInboundHandler{}
@@ -874,6 +1135,15 @@ func (s *server) LoadSnapshot(ctx context.Context, req *rpcpb.LoadSnapshotReques
 		zap.L().Info("received start request with existing timeout", zap.String("deadline", deadline.String()))
 	}
 
+	execPath, err := s.resolveExecPath(req.GetExecPath())
+	if err != nil {
+		return nil, err
+	}
+	binaryVersions, err := checkSnapshotBinaries(s.cfg.SnapshotsDir, req.SnapshotName, execPath, req.Force)
+	if err != nil {
+		return nil, err
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -882,10 +1152,7 @@ func (s *server) LoadSnapshot(ctx context.Context, req *rpcpb.LoadSnapshotReques
 		return nil, ErrAlreadyBootstrapped
 	}
 
-	var (
-		pid = int32(os.Getpid())
-		err error
-	)
+	pid := int32(os.Getpid())
 
 	rootDataDir, err := os.MkdirTemp(os.TempDir(), "network-runner-root-data")
 	if err != nil {
@@ -908,7 +1175,9 @@ func (s *server) LoadSnapshot(ctx context.Context, req *rpcpb.LoadSnapshotReques
 	}
 
 	s.network, err = newLocalNetwork(localNetworkOptions{
-		rootDataDir: rootDataDir,
+		execPath:       execPath,
+		rootDataDir:    rootDataDir,
+		binaryVersions: binaryVersions,
 
 		// to block racey restart
 		// "s.network.start" runs asynchronously
@@ -958,10 +1227,7 @@ func (s *server) LoadSnapshot(ctx context.Context, req *rpcpb.LoadSnapshotReques
 			s.clusterInfo.NodeNames = s.network.nodeNames
 			s.clusterInfo.NodeInfos = s.network.nodeInfos
 			s.clusterInfo.CustomVmsHealthy = true
-			s.clusterInfo.CustomVms = make(map[string]*rpcpb.CustomVmInfo)
-			for vmID, vmInfo := range s.network.customVMIDToInfo {
-				s.clusterInfo.CustomVms[vmID.String()] = vmInfo.info
-			}
+			s.clusterInfo.CustomVms = customVMsInfo(s.network.customVMIDToInfo)
 			s.mu.Unlock()
 		}
 	}()
@@ -1018,13 +1284,52 @@ func (s *server) GetSnapshotNames(ctx context.Context, req *rpcpb.GetSnapshotNam
 	return &rpcpb.GetSnapshotNamesResponse{SnapshotNames: snapshotNames}, nil
 }
 
+// getClusterInfo returns a copy of the cluster info, with the last sampled resource usage
+// of the nodes, or nil if not bootstrapped.
+// The copy can be read without holding [s.mu], while the cluster info is updated.
 func (s *server) getClusterInfo() *rpcpb.ClusterInfo {
 	s.mu.RLock()
-	info := s.clusterInfo
-	s.mu.RUnlock()
+	defer s.mu.RUnlock()
+	if s.clusterInfo == nil {
+		return nil
+	}
+	info := proto.Clone(s.clusterInfo).(*rpcpb.ClusterInfo)
+	s.setResourceUsage(info, s.network)
 	return info
 }
 
+// getNodes returns the nodes named [nodeNames], or all the nodes
+// sorted by name if [nodeNames] is empty.
+func (s *server) getNodes(nodeNames []string) ([]node.Node, error) {
+	s.mu.RLock()
+	if s.network == nil || s.network.nw == nil {
+		s.mu.RUnlock()
+		return nil, ErrNotBootstrapped
+	}
+	nw := s.network.nw
+	s.mu.RUnlock()
+
+	allNodes, err := nw.GetAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	if len(nodeNames) == 0 {
+		for nodeName := range allNodes {
+			nodeNames = append(nodeNames, nodeName)
+		}
+		sort.Strings(nodeNames)
+	}
+	nodes := make([]node.Node, 0, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		nd, ok := allNodes[nodeName]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrNodeNotFound, nodeName)
+		}
+		nodes = append(nodes, nd)
+	}
+	return nodes, nil
+}
+
 func isClientCanceled(ctxErr error, err error) bool {
 	if ctxErr != nil {
 		return true
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia-network-runner/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckNodeExecPaths(t *testing.T) {
+	assert := assert.New(t)
+
+	execPath := filepath.Join(t.TempDir(), "axia")
+	assert.NoError(os.WriteFile(execPath, nil, 0o755))
+
+	assert.NoError(checkNodeExecPaths(nil, 5))
+	assert.NoError(checkNodeExecPaths(map[string]string{"node1": execPath, "node5": execPath}, 5))
+
+	// the names are the ones of the generated default nodes
+	assert.NoError(checkNodeExecPaths(map[string]string{defaultNodeName(0): execPath, defaultNodeName(4): execPath}, 5))
+	err := checkNodeExecPaths(map[string]string{defaultNodeName(5): execPath}, 5)
+	assert.True(errors.Is(err, ErrNodeNotFound))
+
+	err = checkNodeExecPaths(map[string]string{"node6": execPath}, 5)
+	assert.True(errors.Is(err, ErrNodeNotFound))
+
+	err = checkNodeExecPaths(map[string]string{"node1": execPath + "-missing"}, 5)
+	assert.True(errors.Is(err, utils.ErrNotExists))
+}
+
+func TestGetClusterInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &server{mu: new(sync.RWMutex)}
+	assert.Nil(s.getClusterInfo())
+
+	s.clusterInfo = &rpcpb.ClusterInfo{
+		NodeNames: []string{"node1"},
+		NodeInfos: map[string]*rpcpb.NodeInfo{"node1": {Name: "node1"}},
+	}
+	info := s.getClusterInfo()
+	assert.Equal([]string{"node1"}, info.NodeNames)
+	// the copy is not updated with the cluster info
+	s.clusterInfo.NodeInfos["node1"].Uri = "http://127.0.0.1:9650"
+	assert.Empty(info.NodeInfos["node1"].Uri)
+
+	// the resource usage sampled on the network is merged into the copy only
+	s.network = &localNetwork{}
+	s.resourceUsagesNetwork = s.network
+	s.resourceUsages = map[string]*rpcpb.ResourceUsage{"node1": {Pid: 1}}
+	info = s.getClusterInfo()
+	assert.EqualValues(1, info.NodeInfos["node1"].ResourceUsage.GetPid())
+	assert.Nil(s.clusterInfo.NodeInfos["node1"].ResourceUsage)
+
+	// not the one sampled on a previous network
+	s.network = &localNetwork{}
+	assert.Nil(s.getClusterInfo().NodeInfos["node1"].ResourceUsage)
+}
+
+func TestReadCustomVMs(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	execPath := filepath.Join(dir, "axia")
+	assert.NoError(os.WriteFile(execPath, nil, 0o755))
+	pluginDir := filepath.Join(dir, "plugins")
+	assert.NoError(os.Mkdir(pluginDir, 0o755))
+	vmID, err := utils.VMID("subnetevm")
+	assert.NoError(err)
+	assert.NoError(os.WriteFile(filepath.Join(pluginDir, vmID.String()), nil, 0o755))
+	genesisPath := filepath.Join(dir, "genesis.json")
+	assert.NoError(os.WriteFile(genesisPath, []byte(`{"config":{}}`), 0o644))
+
+	vmGenesis, err := readCustomVMs(execPath, pluginDir, map[string]string{"subnetevm": genesisPath})
+	assert.NoError(err)
+	assert.Equal(map[string][]byte{"subnetevm": []byte(`{"config":{}}`)}, vmGenesis)
+
+	_, err = readCustomVMs(execPath, pluginDir, map[string]string{"othervm": genesisPath})
+	assert.True(errors.Is(err, utils.ErrNotExistsPlugin))
+
+	_, err = readCustomVMs(execPath, pluginDir, map[string]string{"subnetevm": genesisPath + "-missing"})
+	assert.True(errors.Is(err, utils.ErrNotExistsPluginGenesis))
+
+	_, err = readCustomVMs(execPath, pluginDir, map[string]string{"a-vm-name-longer-than-32-bytes-long": genesisPath})
+	assert.True(errors.Is(err, ErrInvalidVMName))
+}
@@ -0,0 +1,528 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia-network-runner/utils"
+	"github.com/axiacoin/axia/config"
+	"github.com/axiacoin/axia/ids"
+	"github.com/axiacoin/axia/utils/formatting/address"
+	"github.com/axiacoin/axia/vms/secp256k1fx"
+	"github.com/axiacoin/axia/wallet/subnet/primary/common"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultSubnetValidatorWeight = 1000
+	// reasonable delay in most/slow test environments
+	defaultSubnetValidatorStartDelay = time.Minute
+	defaultSubnetValidatorDuration   = 100 * time.Hour
+	// timeout for a node restarted to whitelist a subnet to come back
+	whitelistRestartTimeout = 5 * time.Minute
+)
+
+func (s *server) CreateSubnet(ctx context.Context, req *rpcpb.CreateSubnetRequest) (*rpcpb.CreateSubnetResponse, error) {
+	zap.L().Info("received create subnet request",
+		zap.Strings("owner-addresses", req.OwnerAddresses),
+		zap.Uint32("threshold", req.Threshold),
+	)
+	owners := make([]ids.ShortID, 0, len(req.OwnerAddresses))
+	for _, addr := range req.OwnerAddresses {
+		owner, err := address.ParseToID(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid owner address %q: %w", addr, err)
+		}
+		owners = append(owners, owner)
+	}
+	threshold := req.Threshold
+	if threshold == 0 {
+		threshold = 1
+	}
+	if len(owners) > 0 && int(threshold) > len(owners) {
+		return nil, fmt.Errorf("%w: %d for %d owners", ErrInvalidThreshold, threshold, len(owners))
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	subnetID, err := s.network.createSubnet(ctx, owners, threshold)
+	if err != nil {
+		return nil, err
+	}
+	return &rpcpb.CreateSubnetResponse{ClusterInfo: s.clusterInfo, SubnetId: subnetID.String()}, nil
+}
+
+func (s *server) AddSubnetValidators(ctx context.Context, req *rpcpb.AddSubnetValidatorsRequest) (*rpcpb.AddSubnetValidatorsResponse, error) {
+	zap.L().Info("received add subnet validators request",
+		zap.String("subnet-id", req.SubnetId),
+		zap.Int("validators", len(req.Validators)),
+	)
+	subnetID, err := ids.FromString(req.SubnetId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subnet ID %q: %w", req.SubnetId, err)
+	}
+	now := time.Now()
+	if err := checkSubnetValidators(req.Validators, nil, now); err != nil {
+		return nil, err
+	}
+	validators := make([]*rpcpb.SubnetValidatorSpec, 0, len(req.Validators))
+	nodeNames := make([]string, 0, len(req.Validators))
+	for _, v := range req.Validators {
+		validators = append(validators, withSubnetValidatorDefaults(v, now))
+		nodeNames = append(nodeNames, v.NodeName)
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	// the faults would fail the restarts
+	if s.chaos != nil && s.chaos.running() {
+		return nil, ErrChaosEnabled
+	}
+	for _, nodeName := range nodeNames {
+		if _, ok := s.network.nodeInfos[nodeName]; !ok {
+			return nil, fmt.Errorf("%w: %q", ErrNodeNotFound, nodeName)
+		}
+	}
+
+	if err := s.whitelistSubnet(ctx, nodeNames, req.SubnetId); err != nil {
+		return nil, err
+	}
+
+	baseWallet, _, _, err := s.network.setupWallet(ctx, s.network.nodeInfos[s.network.nodeNames[0]].Uri)
+	if err != nil {
+		return nil, err
+	}
+	txIDs := make(map[string]string, len(validators))
+	for _, v := range validators {
+		nodeID, err := ids.NodeIDFromString(s.network.nodeInfos[v.NodeName].Id)
+		if err != nil {
+			return nil, err
+		}
+		txID, err := s.network.addSubnetValidator(
+			ctx,
+			baseWallet,
+			subnetID,
+			nodeID,
+			v.Weight,
+			time.Unix(v.StartTime, 0),
+			time.Unix(v.EndTime, 0),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("node %q: %w", v.NodeName, err)
+		}
+		zap.L().Info("added the node as a subnet validator",
+			zap.String("node-name", v.NodeName),
+			zap.String("node-id", nodeID.String()),
+			zap.String("subnet-id", req.SubnetId),
+			zap.String("tx-id", txID.String()),
+		)
+		txIDs[v.NodeName] = txID.String()
+	}
+
+	s.clusterInfo.NodeNames = s.network.nodeNames
+	s.clusterInfo.NodeInfos = s.network.nodeInfos
+	s.setSubnetValidators(ctx)
+	return &rpcpb.AddSubnetValidatorsResponse{ClusterInfo: s.clusterInfo, TxIds: txIDs}, nil
+}
+
+func (s *server) CreateBlockchain(ctx context.Context, req *rpcpb.CreateBlockchainRequest) (*rpcpb.CreateBlockchainResponse, error) {
+	zap.L().Info("received create blockchain request",
+		zap.String("subnet-id", req.SubnetId),
+		zap.String("vm-name", req.VmName),
+		zap.String("genesis-path", req.GenesisPath),
+		zap.String("chain-name", req.ChainName),
+	)
+	subnetID, err := ids.FromString(req.SubnetId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subnet ID %q: %w", req.SubnetId, err)
+	}
+	vmID, err := utils.VMID(req.VmName)
+	if err != nil {
+		zap.L().Warn("failed to convert VM name to VM ID",
+			zap.String("vm-name", req.VmName),
+			zap.Error(err),
+		)
+		return nil, ErrInvalidVMName
+	}
+	chainName := req.ChainName
+	if chainName == "" {
+		chainName = req.VmName
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	pluginDir := s.network.options.pluginDir
+	if pluginDir == "" {
+		return nil, ErrNoPluginDir
+	}
+	if err := utils.CheckExecPluginPaths(
+		s.network.binPath,
+		filepath.Join(pluginDir, vmID.String()),
+		req.GenesisPath,
+	); err != nil {
+		return nil, err
+	}
+	genesis, err := os.ReadFile(req.GenesisPath)
+	if err != nil {
+		return nil, err
+	}
+
+	baseWallet, _, _, err := s.network.setupWallet(ctx, s.network.nodeInfos[s.network.nodeNames[0]].Uri)
+	if err != nil {
+		return nil, err
+	}
+	blockchainID, err := s.network.createBlockchain(ctx, baseWallet, subnetID, vmID, chainName, genesis)
+	if err != nil {
+		return nil, err
+	}
+	zap.L().Info("created a new blockchain",
+		zap.String("vm-name", req.VmName),
+		zap.String("vm-id", vmID.String()),
+		zap.String("subnet-id", req.SubnetId),
+		zap.String("blockchain-id", blockchainID.String()),
+	)
+
+	info := &rpcpb.CustomVmInfo{
+		VmName:       req.VmName,
+		VmId:         vmID.String(),
+		SubnetId:     req.SubnetId,
+		BlockchainId: blockchainID.String(),
+	}
+	if prev, ok := s.network.customVMIDToInfo[vmID]; ok {
+		zap.L().Info("replacing the blockchain reported for the VM",
+			zap.String("vm-id", vmID.String()),
+			zap.String("blockchain-id", prev.info.BlockchainId),
+		)
+	}
+	s.network.customVMIDToInfo[vmID] = vmInfo{
+		info:         info,
+		subnetID:     subnetID,
+		blockchainID: blockchainID,
+	}
+	if s.clusterInfo.CustomVms == nil {
+		s.clusterInfo.CustomVms = make(map[string]*rpcpb.CustomVmInfo)
+	}
+	s.clusterInfo.CustomVms[vmID.String()] = info
+
+	return &rpcpb.CreateBlockchainResponse{
+		ClusterInfo: s.clusterInfo,
+		Blockchain:  proto.Clone(info).(*rpcpb.CustomVmInfo),
+	}, nil
+}
+
+func (s *server) InstallCustomVMs(ctx context.Context, req *rpcpb.InstallCustomVMsRequest) (*rpcpb.InstallCustomVMsResponse, error) {
+	zap.L().Info("received install custom VMs request",
+		zap.String("plugin-dir", req.PluginDir),
+		zap.Any("custom-vms", req.CustomVms),
+	)
+	if len(req.CustomVms) == 0 {
+		return nil, ErrNoCustomVMs
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	lc, customVMs, err := s.prepareCustomVMsInstall(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		s.mu.Lock()
+		s.installingCustomVMs = false
+		s.mu.Unlock()
+	}()
+
+	// the node restarts take [s.mu], as when installing custom VMs at start
+	if err := lc.installCustomVMs(ctx, customVMs, req.CustomVmValidators); err != nil {
+		return nil, err
+	}
+	if err := lc.waitForCustomVMsReady(ctx); err != nil {
+		if errors.Is(err, ErrCustomVMsNotReady) {
+			// report the readiness of their blockchains
+			s.mu.Lock()
+			if s.network == lc && s.clusterInfo != nil {
+				s.clusterInfo.CustomVms = customVMsInfo(lc.customVMIDToInfo)
+			}
+			s.mu.Unlock()
+		}
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// stopped while installing
+	if s.network != lc || s.clusterInfo == nil {
+		return nil, errAborted
+	}
+	s.clusterInfo.NodeNames = lc.nodeNames
+	s.clusterInfo.NodeInfos = lc.nodeInfos
+	s.updateCustomVMsInfo()
+	s.setSubnetValidators(ctx)
+	return &rpcpb.InstallCustomVMsResponse{ClusterInfo: s.clusterInfo}, nil
+}
+
+// prepareCustomVMsInstall checks that the custom VMs of [req] can be installed
+// from its plugin dir on the network, with its validators and readiness probes,
+// adds its chain config files to the ones of the network,
+// and marks the installation as in progress.
+// Returns the network to install on, and the genesis bytes by VM name.
+func (s *server) prepareCustomVMsInstall(req *rpcpb.InstallCustomVMsRequest) (*localNetwork, map[string][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, nil, ErrCustomVMsInstalling
+	}
+	// the faults would fail the restarts
+	if s.chaos != nil && s.chaos.running() {
+		return nil, nil, ErrChaosEnabled
+	}
+	pluginDir := req.PluginDir
+	networkPluginDir := s.network.options.pluginDir
+	if pluginDir == "" {
+		pluginDir = networkPluginDir
+	}
+	if pluginDir == "" {
+		return nil, nil, ErrNoPluginDir
+	}
+	if networkPluginDir != "" && filepath.Clean(pluginDir) != filepath.Clean(networkPluginDir) {
+		return nil, nil, fmt.Errorf("%w: %q, network uses %q", ErrPluginDirChanged, pluginDir, networkPluginDir)
+	}
+	if _, err := getBuildDir(pluginDir); err != nil {
+		return nil, nil, err
+	}
+	vmGenesis, err := readCustomVMs(s.network.binPath, pluginDir, req.CustomVms)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkCustomVMValidators(req.CustomVmValidators, vmGenesis, s.network.nodeNames); err != nil {
+		return nil, nil, err
+	}
+	if err := checkNodeChainConfigs(req.NodeChainConfigs, s.network.nodeNames); err != nil {
+		return nil, nil, err
+	}
+	if err := checkCustomVMReadinessProbes(req.CustomVmReadinessProbes, vmGenesis); err != nil {
+		return nil, nil, err
+	}
+
+	s.network.options.pluginDir = pluginDir
+	s.network.options.chainConfigs = mergeChainConfigs(s.network.options.chainConfigs, req.ChainConfigs)
+	if len(req.NodeChainConfigs) > 0 && s.network.options.nodeChainConfigs == nil {
+		s.network.options.nodeChainConfigs = make(map[string]*rpcpb.ChainConfigs)
+	}
+	for nodeName, configs := range req.NodeChainConfigs {
+		s.network.options.nodeChainConfigs[nodeName] = mergeChainConfigs(s.network.options.nodeChainConfigs[nodeName], configs)
+	}
+	if len(req.CustomVmReadinessProbes) > 0 && s.network.options.customVMReadinessProbes == nil {
+		s.network.options.customVMReadinessProbes = make(map[string]string)
+	}
+	for vmName, method := range req.CustomVmReadinessProbes {
+		s.network.options.customVMReadinessProbes[vmName] = method
+	}
+	if s.network.customVMNameToGenesis == nil {
+		s.network.customVMNameToGenesis = make(map[string][]byte)
+	}
+	for vmName, genesis := range vmGenesis {
+		s.network.customVMNameToGenesis[vmName] = genesis
+	}
+	s.clusterInfo.CustomVmsHealthy = false
+	s.installingCustomVMs = true
+	return s.network, vmGenesis, nil
+}
+
+// checkCustomVMReadinessProbes checks that [probes] are given for VMs of [customVMs]
+func checkCustomVMReadinessProbes(probes map[string]string, customVMs map[string][]byte) error {
+	for vmName := range probes {
+		if _, ok := customVMs[vmName]; !ok {
+			return fmt.Errorf("readiness probe for %q: %w", vmName, ErrInvalidVMName)
+		}
+	}
+	return nil
+}
+
+// withSubnetValidatorDefaults returns a copy of [v] with its unset weight
+// and times set to the defaults, relative to [now]
+func withSubnetValidatorDefaults(v *rpcpb.SubnetValidatorSpec, now time.Time) *rpcpb.SubnetValidatorSpec {
+	v = proto.Clone(v).(*rpcpb.SubnetValidatorSpec)
+	if v.Weight == 0 {
+		v.Weight = defaultSubnetValidatorWeight
+	}
+	if v.StartTime == 0 {
+		v.StartTime = now.Add(defaultSubnetValidatorStartDelay).Unix()
+	}
+	if v.EndTime == 0 {
+		v.EndTime = time.Unix(v.StartTime, 0).Add(defaultSubnetValidatorDuration).Unix()
+	}
+	return v
+}
+
+// checkSubnetValidators checks that [validators] is not empty, names distinct nodes
+// of [nodeNames] (any node if nil), and that their validations end after they start
+func checkSubnetValidators(validators []*rpcpb.SubnetValidatorSpec, nodeNames map[string]struct{}, now time.Time) error {
+	if len(validators) == 0 {
+		return ErrNoSubnetValidators
+	}
+	seen := make(map[string]struct{}, len(validators))
+	for _, v := range validators {
+		if _, ok := seen[v.NodeName]; ok {
+			return fmt.Errorf("%w: %q", ErrDuplicateSubnetValidator, v.NodeName)
+		}
+		seen[v.NodeName] = struct{}{}
+		if _, ok := nodeNames[v.NodeName]; nodeNames != nil && !ok {
+			return fmt.Errorf("%w: %q", ErrNodeNotFound, v.NodeName)
+		}
+		v = withSubnetValidatorDefaults(v, now)
+		if v.EndTime <= v.StartTime {
+			return fmt.Errorf("%w: node %q ends at %d, before its start at %d", ErrInvalidValidatorPeriod, v.NodeName, v.EndTime, v.StartTime)
+		}
+	}
+	return nil
+}
+
+// checkCustomVMValidators checks that [customVMValidators] only selects nodes of [nodeNames]
+// for VMs of [customVMs]
+func checkCustomVMValidators(customVMValidators map[string]*rpcpb.CustomVmValidators, customVMs map[string][]byte, nodeNames []string) error {
+	nodes := make(map[string]struct{}, len(nodeNames))
+	for _, nodeName := range nodeNames {
+		nodes[nodeName] = struct{}{}
+	}
+	now := time.Now()
+	for vmName, vmValidators := range customVMValidators {
+		if _, ok := customVMs[vmName]; !ok {
+			return fmt.Errorf("validators for %q: %w", vmName, ErrInvalidVMName)
+		}
+		if err := checkSubnetValidators(vmValidators.GetValidators(), nodes, now); err != nil {
+			return fmt.Errorf("validators for %q: %w", vmName, err)
+		}
+		for _, nodeName := range vmValidators.GetObservers() {
+			if _, ok := nodes[nodeName]; !ok {
+				return fmt.Errorf("observers for %q: %w: %q", vmName, ErrNodeNotFound, nodeName)
+			}
+		}
+	}
+	return nil
+}
+
+// createSubnet issues a tx creating a subnet owned by [owners],
+// or by the test key if [owners] is empty
+func (lc *localNetwork) createSubnet(ctx context.Context, owners []ids.ShortID, threshold uint32) (ids.ID, error) {
+	baseWallet, _, testKeyAddr, err := lc.setupWallet(ctx, lc.nodeInfos[lc.nodeNames[0]].Uri)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if len(owners) == 0 {
+		owners = []ids.ShortID{testKeyAddr}
+	}
+	cctx, cancel := createDefaultCtx(ctx)
+	subnetID, err := baseWallet.P().IssueCreateSubnetTx(
+		&secp256k1fx.OutputOwners{
+			Threshold: threshold,
+			Addrs:     owners,
+		},
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+	cancel()
+	if err != nil {
+		return ids.Empty, err
+	}
+	color.Outf("{{green}}created subnet %q{{/}}\n", subnetID)
+	return subnetID, nil
+}
+
+// whitelistSubnet restarts the nodes of [nodeNames] not whitelisting [subnetID] yet,
+// one at a time, with the subnet added to their whitelisted subnets.
+// Assumes [s.mu] is held.
+func (s *server) whitelistSubnet(ctx context.Context, nodeNames []string, subnetID string) error {
+	restarted := false
+	for _, nodeName := range nodeNames {
+		current := mergeWhitelistedSubnets(s.network.nodeInfos[nodeName].WhitelistedSubnets)
+		whitelistedSubnets := mergeWhitelistedSubnets(current, subnetID)
+		if whitelistedSubnets == current {
+			continue
+		}
+		nodeConfig, ok := s.network.getNodeConfig(nodeName)
+		if !ok {
+			return fmt.Errorf("%w: no config for node %q", ErrNodeNotFound, nodeName)
+		}
+		var err error
+		nodeConfig.ConfigFile, err = utils.SetJSONKey(nodeConfig.ConfigFile, config.WhitelistedSubnetsKey, whitelistedSubnets)
+		if err != nil {
+			return err
+		}
+		nodeConfig.RedirectStdout = s.cfg.RedirectNodesOutput
+		nodeConfig.RedirectStderr = s.cfg.RedirectNodesOutput
+
+		color.Outf("{{blue}}restarting node %q to whitelist subnet %q...{{/}}\n", nodeName, subnetID)
+		if err := s.network.nw.RemoveNode(nodeName); err != nil {
+			return err
+		}
+		s.network.removeNodeConfig(nodeName)
+		s.dropAttachedPeers(nodeName)
+		nd, err := s.network.nw.AddNode(nodeConfig)
+		if err != nil {
+			return fmt.Errorf("node %q failed to start: %w", nodeName, err)
+		}
+		s.network.cfg.NodeConfigs = append(s.network.cfg.NodeConfigs, nodeConfig)
+
+		wctx, cancel := context.WithTimeout(ctx, whitelistRestartTimeout)
+		err = waitUpgradedNode(wctx, s.network, nd)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("node %q failed to come back: %w", nodeName, err)
+		}
+		restarted = true
+	}
+	if !restarted {
+		return nil
+	}
+	zap.L().Info("waiting for local cluster readiness")
+	if err := s.network.waitForLocalClusterReady(ctx); err != nil {
+		return err
+	}
+	return nil
+}
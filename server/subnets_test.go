@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSubnetValidatorDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Unix(1_000_000, 0)
+	v := withSubnetValidatorDefaults(&rpcpb.SubnetValidatorSpec{NodeName: "node1"}, now)
+	assert.Equal(uint64(defaultSubnetValidatorWeight), v.Weight)
+	assert.Equal(now.Add(defaultSubnetValidatorStartDelay).Unix(), v.StartTime)
+	assert.Equal(now.Add(defaultSubnetValidatorStartDelay+defaultSubnetValidatorDuration).Unix(), v.EndTime)
+
+	spec := &rpcpb.SubnetValidatorSpec{NodeName: "node1", Weight: 20, StartTime: 2_000_000}
+	v = withSubnetValidatorDefaults(spec, now)
+	assert.Equal(uint64(20), v.Weight)
+	assert.Equal(int64(2_000_000), v.StartTime)
+	assert.Equal(time.Unix(2_000_000, 0).Add(defaultSubnetValidatorDuration).Unix(), v.EndTime)
+	// the spec is not modified
+	assert.Zero(spec.EndTime)
+}
+
+func TestCheckCustomVMValidators(t *testing.T) {
+	assert := assert.New(t)
+
+	customVMs := map[string][]byte{"subnetevm": nil, "timestampvm": nil}
+	nodeNames := []string{"node1", "node2", "node3"}
+
+	assert.NoError(checkCustomVMValidators(nil, customVMs, nodeNames))
+	assert.NoError(checkCustomVMValidators(map[string]*rpcpb.CustomVmValidators{
+		"subnetevm": {
+			Validators: []*rpcpb.SubnetValidatorSpec{{NodeName: "node1", Weight: 10}, {NodeName: "node2"}},
+			Observers:  []string{"node3"},
+		},
+		"timestampvm": {
+			Validators: []*rpcpb.SubnetValidatorSpec{{NodeName: "node2"}},
+		},
+	}, customVMs, nodeNames))
+
+	err := checkCustomVMValidators(map[string]*rpcpb.CustomVmValidators{
+		"othervm": {Validators: []*rpcpb.SubnetValidatorSpec{{NodeName: "node1"}}},
+	}, customVMs, nodeNames)
+	assert.True(errors.Is(err, ErrInvalidVMName))
+
+	err = checkCustomVMValidators(map[string]*rpcpb.CustomVmValidators{
+		"subnetevm": {Observers: []string{"node1"}},
+	}, customVMs, nodeNames)
+	assert.True(errors.Is(err, ErrNoSubnetValidators))
+
+	err = checkCustomVMValidators(map[string]*rpcpb.CustomVmValidators{
+		"subnetevm": {Validators: []*rpcpb.SubnetValidatorSpec{{NodeName: "node4"}}},
+	}, customVMs, nodeNames)
+	assert.True(errors.Is(err, ErrNodeNotFound))
+
+	err = checkCustomVMValidators(map[string]*rpcpb.CustomVmValidators{
+		"subnetevm": {
+			Validators: []*rpcpb.SubnetValidatorSpec{{NodeName: "node1"}},
+			Observers:  []string{"node4"},
+		},
+	}, customVMs, nodeNames)
+	assert.True(errors.Is(err, ErrNodeNotFound))
+
+	err = checkCustomVMValidators(map[string]*rpcpb.CustomVmValidators{
+		"subnetevm": {Validators: []*rpcpb.SubnetValidatorSpec{{NodeName: "node1"}, {NodeName: "node1"}}},
+	}, customVMs, nodeNames)
+	assert.True(errors.Is(err, ErrDuplicateSubnetValidator))
+
+	err = checkCustomVMValidators(map[string]*rpcpb.CustomVmValidators{
+		"subnetevm": {Validators: []*rpcpb.SubnetValidatorSpec{{NodeName: "node1", StartTime: 2_000_000, EndTime: 1_000_000}}},
+	}, customVMs, nodeNames)
+	assert.True(errors.Is(err, ErrInvalidValidatorPeriod))
+}
+
+func TestCheckCustomVMReadinessProbes(t *testing.T) {
+	assert := assert.New(t)
+
+	customVMs := map[string][]byte{"subnetevm": nil}
+	assert.NoError(checkCustomVMReadinessProbes(nil, customVMs))
+	assert.NoError(checkCustomVMReadinessProbes(map[string]string{"subnetevm": "eth_blockNumber"}, customVMs))
+	err := checkCustomVMReadinessProbes(map[string]string{"othervm": "eth_blockNumber"}, customVMs)
+	assert.True(errors.Is(err, ErrInvalidVMName))
+}
+
+func TestTracksCustomVM(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(tracksCustomVM("node1", nil))
+	vmValidators := &rpcpb.CustomVmValidators{
+		Validators: []*rpcpb.SubnetValidatorSpec{{NodeName: "node1"}},
+		Observers:  []string{"node2"},
+	}
+	assert.True(tracksCustomVM("node1", vmValidators))
+	assert.True(tracksCustomVM("node2", vmValidators))
+	assert.False(tracksCustomVM("node3", vmValidators))
+
+	assert.True(tracksSubnet(&rpcpb.NodeInfo{WhitelistedSubnets: "a, b"}, "b"))
+	assert.False(tracksSubnet(&rpcpb.NodeInfo{WhitelistedSubnets: "a,b"}, "c"))
+	assert.False(tracksSubnet(&rpcpb.NodeInfo{}, "a"))
+}
+
+func TestCustomVMsInstallingBlocksNetworkChanges(t *testing.T) {
+	assert := assert.New(t)
+
+	lc := &localNetwork{nodeInfos: map[string]*rpcpb.NodeInfo{"node1": {Name: "node1"}}}
+	s := &server{
+		mu:                  new(sync.RWMutex),
+		network:             lc,
+		clusterInfo:         &rpcpb.ClusterInfo{Healthy: true},
+		installingCustomVMs: true,
+	}
+	ctx := context.Background()
+
+	_, err := s.Stop(ctx, &rpcpb.StopRequest{})
+	assert.ErrorIs(err, ErrCustomVMsInstalling)
+	_, err = s.RemoveNode(ctx, &rpcpb.RemoveNodeRequest{Name: "node1"})
+	assert.ErrorIs(err, ErrCustomVMsInstalling)
+	_, err = s.RestartNode(ctx, &rpcpb.RestartNodeRequest{Name: "node1"})
+	assert.ErrorIs(err, ErrCustomVMsInstalling)
+	_, err = s.AddNode(ctx, &rpcpb.AddNodeRequest{Name: "node2"})
+	assert.ErrorIs(err, ErrCustomVMsInstalling)
+
+	// the network is kept for the install
+	assert.Equal(lc, s.network)
+	assert.NotNil(s.clusterInfo)
+}
@@ -0,0 +1,431 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/api"
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia-network-runner/utils"
+	"github.com/axiacoin/axia/ids"
+	"github.com/axiacoin/axia/utils/constants"
+	"github.com/axiacoin/axia/utils/crypto"
+	"github.com/axiacoin/axia/utils/formatting/address"
+	"github.com/axiacoin/axia/vms/components/axc"
+	"github.com/axiacoin/axia/vms/secp256k1fx"
+	"github.com/axiacoin/axia/wallet/subnet/primary/common"
+	"github.com/axiacoin/coreth/plugin/evm"
+	ethcommon "github.com/ethereum/go-ethereum/common"
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"go.uber.org/zap"
+)
+
+const (
+	// interval between status polls while waiting for an AXChain atomic tx to be accepted
+	atomicTxPollInterval = time.Second
+	// max number of atomic UTXOs fetched from the AXChain at once
+	maxAtomicUTXOs = 1024
+)
+
+// transferHop is an export of AXC from chain [source] and its import on chain [destination]
+type transferHop struct {
+	source      string
+	destination string
+}
+
+func (s *server) CrossChainTransfer(ctx context.Context, req *rpcpb.CrossChainTransferRequest) (*rpcpb.CrossChainTransferResponse, error) {
+	zap.L().Info("received cross chain transfer request",
+		zap.String("source-chain", req.SourceChain),
+		zap.String("destination-chain", req.DestinationChain),
+		zap.Uint64("amount", req.Amount),
+		zap.String("address", req.Address),
+	)
+	hops, err := transferHops(req.SourceChain, req.DestinationChain)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount == 0 {
+		return nil, ErrInvalidAmount
+	}
+	var target *fundTarget
+	if req.Address != "" {
+		t, err := parseFundTarget(req.Address)
+		if err != nil {
+			return nil, err
+		}
+		if t.chain != req.DestinationChain {
+			return nil, fmt.Errorf("%w: %q is not on chain %q", ErrInvalidAddress, req.Address, req.DestinationChain)
+		}
+		target = &t
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	txIDs, err := s.network.crossChainTransfer(ctx, hops, req.Amount, target)
+	if err != nil {
+		return nil, err
+	}
+	zap.L().Info("transferred AXC across chains",
+		zap.String("source-chain", req.SourceChain),
+		zap.String("destination-chain", req.DestinationChain),
+		zap.Uint64("amount", req.Amount),
+		zap.Strings("tx-ids", txIDs),
+	)
+	return &rpcpb.CrossChainTransferResponse{ClusterInfo: s.clusterInfo, TxIds: txIDs}, nil
+}
+
+// transferHops returns the exports and imports moving AXC from chain [source] to chain [destination].
+// The AXChain only exchanges atomically with the SwapChain, so transfers between the CoreChain
+// and the AXChain go through the SwapChain.
+func transferHops(source string, destination string) ([]transferHop, error) {
+	for _, chain := range []string{source, destination} {
+		if chain != "X" && chain != "P" && chain != "C" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidChain, chain)
+		}
+	}
+	if source == destination {
+		return nil, fmt.Errorf("%w: same source and destination %q", ErrInvalidChain, source)
+	}
+	if source != "X" && destination != "X" {
+		return []transferHop{{source: source, destination: "X"}, {source: "X", destination: destination}}, nil
+	}
+	return []transferHop{{source: source, destination: destination}}, nil
+}
+
+// crossChainTransfer moves [amount] nAXC from the pre-funded test key along [hops],
+// crediting [target], or the test key if nil, on the last destination chain.
+// Each import pays its fee from the exported AXC, so that [amount] is credited.
+// Returns the IDs of the accepted export and import txs.
+func (lc *localNetwork) crossChainTransfer(ctx context.Context, hops []transferHop, amount uint64, target *fundTarget) ([]string, error) {
+	httpRPCEp := lc.nodeInfos[lc.nodeNames[0]].Uri
+	baseWallet, axcAssetID, testKeyAddr, err := lc.setupWallet(ctx, httpRPCEp)
+	if err != nil {
+		return nil, err
+	}
+	cChain, err := lc.newAXChainAtomic(ctx, axcAssetID)
+	if err != nil {
+		return nil, err
+	}
+	chainIDs := map[string]ids.ID{
+		"X": baseWallet.X().BlockchainID(),
+		"P": constants.PlatformChainID,
+		"C": cChain.chainID,
+	}
+
+	txIDs := make([]string, 0, 2*len(hops))
+	for i, hop := range hops {
+		to := fundTarget{chain: hop.destination, addr: testKeyAddr, ethAddr: cChain.ethAddr}
+		if i == len(hops)-1 && target != nil {
+			to = *target
+		}
+		sourceChainID, destinationChainID := chainIDs[hop.source], chainIDs[hop.destination]
+
+		var importFee uint64
+		switch hop.destination {
+		case "X":
+			importFee = baseWallet.X().BaseTxFee()
+		case "P":
+			importFee = baseWallet.P().BaseTxFee()
+		case "C":
+			importFee, err = cChain.importFee(ctx, sourceChainID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		println()
+		color.Outf("{{green}}exporting %d nAXC from the %sChain to the %sChain{{/}}\n", amount, hop.source, hop.destination)
+		var exportTxID ids.ID
+		exportOut := transferableOutput(axcAssetID, amount+importFee, testKeyAddr)
+		cctx, cancel := createDefaultCtx(ctx)
+		switch hop.source {
+		case "X":
+			exportTxID, err = baseWallet.X().IssueExportTx(destinationChainID, []*axc.TransferableOutput{exportOut}, common.WithContext(cctx), defaultPoll)
+		case "P":
+			exportTxID, err = baseWallet.P().IssueExportTx(destinationChainID, []*axc.TransferableOutput{exportOut}, common.WithContext(cctx), defaultPoll)
+		case "C":
+			exportTxID, err = cChain.export(cctx, destinationChainID, amount+importFee, testKeyAddr)
+			if err == nil {
+				// the wallet only knows the UTXOs exported by the SwapChain and the CoreChain
+				err = baseWallet.fetchAtomicUTXOs(ctx, sourceChainID, destinationChainID)
+			}
+		}
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("export from the %sChain: %w", hop.source, err)
+		}
+		zap.L().Info("exported AXC",
+			zap.String("source-chain", hop.source),
+			zap.String("destination-chain", hop.destination),
+			zap.String("tx-id", exportTxID.String()),
+		)
+		txIDs = append(txIDs, exportTxID.String())
+
+		color.Outf("{{green}}importing %d nAXC to the %sChain{{/}}\n", amount, hop.destination)
+		var importTxID ids.ID
+		owners := &secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{to.addr},
+		}
+		cctx, cancel = createDefaultCtx(ctx)
+		switch hop.destination {
+		case "X":
+			importTxID, err = baseWallet.X().IssueImportTx(sourceChainID, owners, common.WithContext(cctx), defaultPoll)
+		case "P":
+			importTxID, err = baseWallet.P().IssueImportTx(sourceChainID, owners, common.WithContext(cctx), defaultPoll)
+		case "C":
+			importTxID, err = cChain.importExported(cctx, sourceChainID, exportTxID, testKeyAddr, to.ethAddr)
+		}
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("import to the %sChain: %w", hop.destination, err)
+		}
+		zap.L().Info("imported AXC",
+			zap.String("source-chain", hop.source),
+			zap.String("destination-chain", hop.destination),
+			zap.String("tx-id", importTxID.String()),
+		)
+		txIDs = append(txIDs, importTxID.String())
+	}
+	return txIDs, nil
+}
+
+// axChainAtomic issues atomic txs of the pre-funded test key on the AXChain
+type axChainAtomic struct {
+	networkID  uint32
+	hrp        string
+	chainID    ids.ID
+	axcAssetID ids.ID
+	// the clients are closed with the node
+	client    evm.Client
+	ethClient api.EthClient
+	ethAddr   ethcommon.Address
+}
+
+// newAXChainAtomic returns an AXChain atomic tx issuer through the first node
+func (lc *localNetwork) newAXChainAtomic(ctx context.Context, axcAssetID ids.ID) (*axChainAtomic, error) {
+	networkID, err := utils.NetworkIDFromGenesis([]byte(lc.cfg.Genesis))
+	if err != nil {
+		return nil, err
+	}
+	nd, err := lc.nw.GetNode(lc.nodeNames[0])
+	if err != nil {
+		return nil, err
+	}
+	apiClient := nd.GetAPIClient()
+	cctx, cancel := createDefaultCtx(ctx)
+	chainID, err := apiClient.InfoAPI().GetBlockchainID(cctx, "C")
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+	key, err := ethcrypto.ToECDSA(prefundedKey.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &axChainAtomic{
+		networkID:  networkID,
+		hrp:        constants.GetHRP(networkID),
+		chainID:    chainID,
+		axcAssetID: axcAssetID,
+		client:     apiClient.CChainAPI(),
+		ethClient:  apiClient.CChainEthAPI(),
+		ethAddr:    ethcrypto.PubkeyToAddress(key.PublicKey),
+	}, nil
+}
+
+// export exports [amount] nAXC of the test key to [to] on chain [destinationChainID],
+// paying the fee on top of it, and waits for the tx to be accepted
+func (c *axChainAtomic) export(ctx context.Context, destinationChainID ids.ID, amount uint64, to ids.ShortID) (ids.ID, error) {
+	nonce, err := c.ethClient.NonceAt(ctx, c.ethAddr, nil)
+	if err != nil {
+		return ids.Empty, err
+	}
+	baseFee, err := c.ethClient.EstimateBaseFee(ctx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	newTx := func(fee uint64) (*evm.Tx, error) {
+		tx := &evm.Tx{UnsignedAtomicTx: &evm.UnsignedExportTx{
+			NetworkID:        c.networkID,
+			BlockchainID:     c.chainID,
+			DestinationChain: destinationChainID,
+			Ins: []evm.EVMInput{{
+				Address: c.ethAddr,
+				Amount:  amount + fee,
+				AssetID: c.axcAssetID,
+				Nonce:   nonce,
+			}},
+			ExportedOutputs: []*axc.TransferableOutput{transferableOutput(c.axcAssetID, amount, to)},
+		}}
+		return tx, tx.Sign(evm.Codec, [][]*crypto.PrivateKeySECP256K1R{{prefundedKey}})
+	}
+	// the fee doesn't change the size of the tx, so neither its gas
+	tx, err := newTx(0)
+	if err != nil {
+		return ids.Empty, err
+	}
+	fee, err := atomicTxFee(tx, baseFee)
+	if err != nil {
+		return ids.Empty, err
+	}
+	tx, err = newTx(fee)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return c.issue(ctx, tx)
+}
+
+// importFee returns the fee of importing a single UTXO from [sourceChainID]
+// at the current base fee
+func (c *axChainAtomic) importFee(ctx context.Context, sourceChainID ids.ID) (uint64, error) {
+	cctx, cancel := createDefaultCtx(ctx)
+	baseFee, err := c.ethClient.EstimateBaseFee(cctx)
+	cancel()
+	if err != nil {
+		return 0, err
+	}
+	ins := []*axc.TransferableInput{transferableInput(axc.UTXOID{}, c.axcAssetID, 0)}
+	tx, err := c.newImportTx(sourceChainID, ins, 0, c.ethAddr)
+	if err != nil {
+		return 0, err
+	}
+	return atomicTxFee(tx, baseFee)
+}
+
+// importExported imports the AXC exported by tx [exportTxID] from [sourceChainID]
+// to the test key address [from] into [to], paying the fee from it,
+// and waits for the tx to be accepted
+func (c *axChainAtomic) importExported(ctx context.Context, sourceChainID ids.ID, exportTxID ids.ID, from ids.ShortID, to ethcommon.Address) (ids.ID, error) {
+	cAddr, err := address.Format("C", c.hrp, from.Bytes())
+	if err != nil {
+		return ids.Empty, err
+	}
+	utxosBytes, _, err := c.client.GetAtomicUTXOs(ctx, []string{cAddr}, sourceChainID.String(), maxAtomicUTXOs, "", "")
+	if err != nil {
+		return ids.Empty, err
+	}
+	var (
+		ins      []*axc.TransferableInput
+		imported uint64
+	)
+	for _, utxoBytes := range utxosBytes {
+		utxo := &axc.UTXO{}
+		if _, err := evm.Codec.Unmarshal(utxoBytes, utxo); err != nil {
+			return ids.Empty, err
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || utxo.TxID != exportTxID || utxo.AssetID() != c.axcAssetID {
+			continue
+		}
+		ins = append(ins, transferableInput(utxo.UTXOID, c.axcAssetID, out.Amt))
+		imported += out.Amt
+	}
+	if len(ins) == 0 {
+		return ids.Empty, fmt.Errorf("no UTXO exported by tx %s to import", exportTxID)
+	}
+	axc.SortTransferableInputs(ins)
+
+	baseFee, err := c.ethClient.EstimateBaseFee(ctx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	// the amount doesn't change the size of the tx, so neither its gas
+	tx, err := c.newImportTx(sourceChainID, ins, imported, to)
+	if err != nil {
+		return ids.Empty, err
+	}
+	fee, err := atomicTxFee(tx, baseFee)
+	if err != nil {
+		return ids.Empty, err
+	}
+	if fee >= imported {
+		return ids.Empty, fmt.Errorf("import fee %d nAXC is not less than the imported %d nAXC", fee, imported)
+	}
+	tx, err = c.newImportTx(sourceChainID, ins, imported-fee, to)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return c.issue(ctx, tx)
+}
+
+// newImportTx returns a signed import of [ins] from [sourceChainID], crediting [amount] to [to]
+func (c *axChainAtomic) newImportTx(sourceChainID ids.ID, ins []*axc.TransferableInput, amount uint64, to ethcommon.Address) (*evm.Tx, error) {
+	tx := &evm.Tx{UnsignedAtomicTx: &evm.UnsignedImportTx{
+		NetworkID:      c.networkID,
+		BlockchainID:   c.chainID,
+		SourceChain:    sourceChainID,
+		ImportedInputs: ins,
+		Outs: []evm.EVMOutput{{
+			Address: to,
+			Amount:  amount,
+			AssetID: c.axcAssetID,
+		}},
+	}}
+	signers := make([][]*crypto.PrivateKeySECP256K1R, len(ins))
+	for i := range signers {
+		signers[i] = []*crypto.PrivateKeySECP256K1R{prefundedKey}
+	}
+	return tx, tx.Sign(evm.Codec, signers)
+}
+
+// issue issues [tx] and waits for it to be accepted
+func (c *axChainAtomic) issue(ctx context.Context, tx *evm.Tx) (ids.ID, error) {
+	txID, err := c.client.IssueTx(ctx, tx.Bytes())
+	if err != nil {
+		return ids.Empty, err
+	}
+	for {
+		status, err := c.client.GetAtomicTxStatus(ctx, txID)
+		if err == nil {
+			switch status {
+			case evm.Accepted:
+				return txID, nil
+			case evm.Dropped:
+				return ids.Empty, fmt.Errorf("atomic tx %s dropped", txID)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ids.Empty, fmt.Errorf("atomic tx %s not accepted: %w (last status %s, error: %v)", txID, ctx.Err(), status, err)
+		case <-time.After(atomicTxPollInterval):
+		}
+	}
+}
+
+// atomicTxFee returns the fee in nAXC of the signed atomic tx [tx] at [baseFee]
+func atomicTxFee(tx *evm.Tx, baseFee *big.Int) (uint64, error) {
+	// local networks have the fixed atomic tx fee activated from genesis
+	gasUsed, err := tx.GasUsed(true)
+	if err != nil {
+		return 0, err
+	}
+	return evm.CalculateDynamicFee(gasUsed, baseFee)
+}
+
+func transferableInput(utxoID axc.UTXOID, assetID ids.ID, amount uint64) *axc.TransferableInput {
+	return &axc.TransferableInput{
+		UTXOID: utxoID,
+		Asset:  axc.Asset{ID: assetID},
+		In: &secp256k1fx.TransferInput{
+			Amt:   amount,
+			Input: secp256k1fx.Input{SigIndices: []uint32{0}},
+		},
+	}
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferHops(t *testing.T) {
+	assert := assert.New(t)
+
+	hops, err := transferHops("X", "P")
+	assert.NoError(err)
+	assert.Equal([]transferHop{{source: "X", destination: "P"}}, hops)
+
+	hops, err = transferHops("C", "X")
+	assert.NoError(err)
+	assert.Equal([]transferHop{{source: "C", destination: "X"}}, hops)
+
+	// through the SwapChain
+	hops, err = transferHops("P", "C")
+	assert.NoError(err)
+	assert.Equal([]transferHop{{source: "P", destination: "X"}, {source: "X", destination: "C"}}, hops)
+	hops, err = transferHops("C", "P")
+	assert.NoError(err)
+	assert.Equal([]transferHop{{source: "C", destination: "X"}, {source: "X", destination: "P"}}, hops)
+
+	_, err = transferHops("X", "X")
+	assert.True(errors.Is(err, ErrInvalidChain))
+	_, err = transferHops("X", "D")
+	assert.True(errors.Is(err, ErrInvalidChain))
+	_, err = transferHops("", "C")
+	assert.True(errors.Is(err, ErrInvalidChain))
+}
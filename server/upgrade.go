@@ -0,0 +1,329 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/network/node"
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia-network-runner/utils"
+	"github.com/axiacoin/axia/config"
+	"go.uber.org/zap"
+)
+
+const (
+	// timeout for a batch of upgraded nodes to come back healthy and bootstrapped
+	upgradeBatchTimeout = 5 * time.Minute
+	// interval between checks of an upgraded node
+	upgradePollInterval = time.Second
+)
+
+// chains an upgraded node must have bootstrapped before the next batch
+var upgradeBootstrapChains = []string{"P", "X", "C"}
+
+func (s *server) UpgradeNetwork(ctx context.Context, req *rpcpb.UpgradeNetworkRequest) (*rpcpb.UpgradeNetworkResponse, error) {
+	zap.L().Info("received upgrade network request",
+		zap.String("exec-path", req.ExecPath),
+		zap.Strings("node-names", req.NodeNames),
+		zap.Uint32("batch-size", req.BatchSize),
+		zap.Duration("pause", time.Duration(req.Pause)),
+	)
+	if req.ExecPath == "" {
+		return nil, ErrNoExecPath
+	}
+	execPath, err := s.resolveExecPath(req.ExecPath)
+	if err != nil {
+		return nil, err
+	}
+	var globalConfig map[string]interface{}
+	if req.GetGlobalNodeConfig() != "" {
+		if err := json.Unmarshal([]byte(req.GetGlobalNodeConfig()), &globalConfig); err != nil {
+			return nil, err
+		}
+	}
+	binaryVersions := detectBinaryVersions(execPath)
+	batchSize := int(req.BatchSize)
+	if batchSize == 0 {
+		batchSize = 1
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	// resolves the node names before taking the lock
+	nodes, err := s.getNodes(req.NodeNames)
+	if err != nil {
+		return nil, err
+	}
+	nodeNames := make([]string, 0, len(nodes))
+	for _, nd := range nodes {
+		nodeNames = append(nodeNames, nd.GetName())
+	}
+
+	s.mu.Lock()
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		s.mu.Unlock()
+		return nil, ErrNetworkNotReady
+	}
+	lc := s.network
+	if err := s.checkUpgrading(lc); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.mu.Unlock()
+
+	// the lock is only held to restart each node,
+	// so that the network can be queried during the upgrade
+	resp := &rpcpb.UpgradeNetworkResponse{}
+	for start := 0; start < len(nodeNames); start += batchSize {
+		if start > 0 && req.Pause > 0 {
+			select {
+			case <-ctx.Done():
+				resp.PendingNodes = nodeNames[start:]
+				resp.Error = ctx.Err().Error()
+				return s.upgradeResponse(ctx, lc, resp)
+			case <-time.After(time.Duration(req.Pause)):
+			}
+		}
+		end := start + batchSize
+		if end > len(nodeNames) {
+			end = len(nodeNames)
+		}
+		batch := nodeNames[start:end]
+		color.Outf("{{blue}}{{bold}}upgrading nodes %v to %q...{{/}}\n", batch, execPath)
+
+		upgraded, err := s.upgradeBatch(ctx, lc, batch, execPath, binaryVersions[execPath], globalConfig)
+		resp.UpgradedNodes = append(resp.UpgradedNodes, upgraded...)
+		if err != nil {
+			zap.L().Warn("upgrade stopped", zap.Strings("batch", batch), zap.Error(err))
+			resp.PendingNodes = nodeNames[end:]
+			resp.Error = err.Error()
+			return s.upgradeResponse(ctx, lc, resp)
+		}
+	}
+
+	zap.L().Info("waiting for local cluster readiness")
+	color.Outf("{{blue}}{{bold}}waiting for all nodes to report healthy...{{/}}\n")
+	if err := lc.nw.Healthy(ctx); err != nil {
+		zap.L().Warn("network not healthy after the upgrade", zap.Error(err))
+		resp.Error = err.Error()
+	}
+	return s.upgradeResponse(ctx, lc, resp)
+}
+
+// checkUpgrading returns an error if the upgrade of network [lc] can't go on.
+// Assumes [s.mu] is held.
+func (s *server) checkUpgrading(lc *localNetwork) error {
+	// stopped or replaced during the upgrade
+	if s.network != lc || s.clusterInfo == nil {
+		return errAborted
+	}
+	if s.installingCustomVMs {
+		return ErrCustomVMsInstalling
+	}
+	// the faults would be reported as upgrade failures
+	if s.chaos != nil && s.chaos.running() {
+		return ErrChaosEnabled
+	}
+	return nil
+}
+
+// upgradeResponse sets the cluster info of [resp] to the current nodes of network [lc]
+func (s *server) upgradeResponse(ctx context.Context, lc *localNetwork, resp *rpcpb.UpgradeNetworkResponse) (*rpcpb.UpgradeNetworkResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network != lc || s.clusterInfo == nil {
+		return nil, errAborted
+	}
+	if err := lc.updateNodeInfo(ctx); err != nil {
+		return nil, err
+	}
+	s.clusterInfo.NodeNames = lc.nodeNames
+	s.clusterInfo.NodeInfos = lc.nodeInfos
+	resp.ClusterInfo = s.clusterInfo
+	return resp, nil
+}
+
+// upgradeBatch restarts the nodes of [batch] of network [lc] with [execPath], merging [globalConfig]
+// into their configs, and waits for them to be healthy and bootstrapped.
+// Nodes with a database newer than [binaryVersion] are not restarted.
+// Returns an error naming the first node that failed to restart or to come back.
+// Takes [s.mu] to restart each node, and releases it while waiting.
+func (s *server) upgradeBatch(
+	ctx context.Context,
+	lc *localNetwork,
+	batch []string,
+	execPath string,
+	binaryVersion utils.BinaryVersion,
+	globalConfig map[string]interface{},
+) ([]*rpcpb.UpgradedNodeInfo, error) {
+	upgraded := make([]*rpcpb.UpgradedNodeInfo, 0, len(batch))
+	starts := make([]time.Time, 0, len(batch))
+	nodes := make([]node.Node, 0, len(batch))
+	var failErr error
+	for _, nodeName := range batch {
+		info := &rpcpb.UpgradedNodeInfo{NodeName: nodeName}
+		start := time.Now()
+		nd, err := s.upgradeNode(lc, info, execPath, binaryVersion, globalConfig)
+		if info.OldExecPath != "" {
+			upgraded = append(upgraded, info)
+			starts = append(starts, start)
+		}
+		if err != nil {
+			info.Error = err.Error()
+			info.Duration = int64(time.Since(start))
+			failErr = fmt.Errorf("node %q: %w", nodeName, err)
+			break
+		}
+		nodes = append(nodes, nd)
+	}
+
+	// waits for the nodes already restarted, even if restarting the next ones failed
+	bctx, cancel := context.WithTimeout(ctx, upgradeBatchTimeout)
+	defer cancel()
+	for i, nd := range nodes {
+		info := upgraded[i]
+		if err := waitUpgradedNode(bctx, lc, nd); err != nil {
+			info.Error = err.Error()
+			if failErr == nil {
+				failErr = fmt.Errorf("node %q failed to come back: %w", info.NodeName, err)
+			}
+		}
+		info.Duration = int64(time.Since(starts[i]))
+	}
+	return upgraded, failErr
+}
+
+// upgradeNode restarts node [info.NodeName] of network [lc] with [execPath],
+// and returns the restarted node.
+// [info.OldExecPath] is set once the node is found.
+func (s *server) upgradeNode(
+	lc *localNetwork,
+	info *rpcpb.UpgradedNodeInfo,
+	execPath string,
+	binaryVersion utils.BinaryVersion,
+	globalConfig map[string]interface{},
+) (node.Node, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkUpgrading(lc); err != nil {
+		return nil, err
+	}
+	nodeName := info.NodeName
+	nodeConfig, ok := lc.getNodeConfig(nodeName)
+	if !ok {
+		return nil, fmt.Errorf("%w: no config", ErrNodeNotFound)
+	}
+	info.OldExecPath = nodeConfig.BinaryPath
+
+	if nodeInfo, ok := lc.nodeInfos[nodeName]; ok {
+		if err := utils.CheckDBVersion(nodeInfo.DbDir, binaryVersion.Database); err != nil {
+			return nil, err
+		}
+	}
+	if len(globalConfig) > 0 {
+		configFile, err := mergeUpgradeConfig(nodeConfig.ConfigFile, globalConfig)
+		if err != nil {
+			return nil, err
+		}
+		nodeConfig.ConfigFile = configFile
+	}
+	nodeConfig.BinaryPath = execPath
+	nodeConfig.RedirectStdout = s.cfg.RedirectNodesOutput
+	nodeConfig.RedirectStderr = s.cfg.RedirectNodesOutput
+
+	zap.L().Info("restarting the node", zap.String("node-name", nodeName))
+	if err := lc.nw.RemoveNode(nodeName); err != nil {
+		return nil, err
+	}
+	lc.removeNodeConfig(nodeName)
+	s.dropAttachedPeers(nodeName)
+	nd, err := lc.nw.AddNode(nodeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start: %w", err)
+	}
+	lc.cfg.NodeConfigs = append(lc.cfg.NodeConfigs, nodeConfig)
+	lc.setBinaryVersions(map[string]utils.BinaryVersion{execPath: binaryVersion})
+	return nd, nil
+}
+
+// waitUpgradedNode waits for node [nd] of network [lc] to report healthy,
+// and to have bootstrapped [upgradeBootstrapChains]
+func waitUpgradedNode(ctx context.Context, lc *localNetwork, nd node.Node) error {
+	for {
+		// a node that exited won't come back
+		if exited, err := nd.GetExitStatus(); exited {
+			return fmt.Errorf("node exited: %v", err)
+		}
+		err := checkUpgradedNode(ctx, nd)
+		if err == nil {
+			color.Outf("{{green}}node %q is healthy and bootstrapped{{/}}\n", nd.GetName())
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last error: %v)", ctx.Err(), err)
+		case <-lc.stopCh:
+			return errAborted
+		case <-time.After(upgradePollInterval):
+		}
+	}
+}
+
+func checkUpgradedNode(ctx context.Context, nd node.Node) error {
+	health, err := nd.GetAPIClient().HealthAPI().Health(ctx)
+	if err != nil {
+		return err
+	}
+	if !health.Healthy {
+		return fmt.Errorf("node not healthy")
+	}
+	infoClient := nd.GetAPIClient().InfoAPI()
+	for _, chain := range upgradeBootstrapChains {
+		bootstrapped, err := infoClient.IsBootstrapped(ctx, chain)
+		if err != nil {
+			return err
+		}
+		if !bootstrapped {
+			return fmt.Errorf("chain %q not bootstrapped", chain)
+		}
+	}
+	return nil
+}
+
+// mergeUpgradeConfig merges [globalConfig] into the JSON node config [configFile],
+// keeping the directories set by the runner
+func mergeUpgradeConfig(configFile string, globalConfig map[string]interface{}) (string, error) {
+	var configFileMap map[string]interface{}
+	if err := json.Unmarshal([]byte(configFile), &configFileMap); err != nil {
+		return "", err
+	}
+	runnerKeys := []string{config.LogsDirKey, config.DBPathKey, config.BuildDirKey}
+	runnerValues := make(map[string]interface{}, len(runnerKeys))
+	for _, k := range runnerKeys {
+		if v, ok := configFileMap[k]; ok {
+			runnerValues[k] = v
+		}
+	}
+	mergeAndCheckForIgnores(configFileMap, globalConfig)
+	for _, k := range runnerKeys {
+		if v, ok := runnerValues[k]; ok {
+			configFileMap[k] = v
+		} else {
+			delete(configFileMap, k)
+		}
+	}
+	b, err := json.Marshal(configFileMap)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,583 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/pkg/color"
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/axiacoin/axia/ids"
+	"github.com/axiacoin/axia/utils/constants"
+	apijson "github.com/axiacoin/axia/utils/json"
+	"github.com/axiacoin/axia/utils/units"
+	"github.com/axiacoin/axia/vms/platformvm"
+	"github.com/axiacoin/axia/vms/platformvm/validator"
+	"github.com/axiacoin/axia/vms/secp256k1fx"
+	"github.com/axiacoin/axia/wallet/subnet/primary/common"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultValidatorStake    = 1 * units.Axc
+	defaultValidatorDuration = 300 * time.Hour
+	// 10% fee percent, times 10000 to make it as shares
+	defaultValidatorDelegationFee = 10 * 10000
+	// delay before the validations added by AddNode and AddValidator start
+	validatorStartDelay = 20 * time.Second
+	// timeout for an added validator to be in the current validator set
+	validatorWaitTimeout      = 5 * time.Minute
+	validatorWaitPollInterval = 5 * time.Second
+
+	defaultDelegatorStake = 1 * units.Axc
+
+	platformAPIPath = "/ext/P"
+	// timeout to fetch the validators of all subnets
+	subnetValidatorsTimeout = 10 * time.Second
+	// interval between refreshes of the subnet validators, which change as staking periods start and end
+	subnetValidatorsRefreshInterval = 10 * time.Second
+)
+
+func (s *server) AddValidator(ctx context.Context, req *rpcpb.AddValidatorRequest) (*rpcpb.AddValidatorResponse, error) {
+	zap.L().Info("received add validator request",
+		zap.String("node-name", req.NodeName),
+		zap.Any("validator", req.Validator),
+	)
+	if err := checkPrimaryValidatorSpec(req.Validator); err != nil {
+		return nil, err
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	lc, txID, waitValidator, err := s.issuePrimaryValidator(ctx, req.NodeName, req.Validator)
+	if err != nil {
+		return nil, fmt.Errorf("node %q: %w", req.NodeName, err)
+	}
+	info, err := s.waitPrimaryValidator(ctx, lc, waitValidator)
+	if err != nil {
+		return nil, fmt.Errorf("node %q: %w", req.NodeName, err)
+	}
+	return &rpcpb.AddValidatorResponse{ClusterInfo: info, TxId: txID.String()}, nil
+}
+
+// issuePrimaryValidator issues the tx adding node [nodeName] as a primary network validator
+// as specified by [spec]. Returns the network it was issued on, the tx ID, and the function
+// waiting for the node to be a current validator, if any.
+func (s *server) issuePrimaryValidator(
+	ctx context.Context,
+	nodeName string,
+	spec *rpcpb.PrimaryValidatorSpec,
+) (*localNetwork, ids.ID, func(context.Context) error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ids.Empty, nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ids.Empty, nil, ErrCustomVMsInstalling
+	}
+	nodeInfo, ok := s.network.nodeInfos[nodeName]
+	if !ok {
+		return nil, ids.Empty, nil, ErrNodeNotFound
+	}
+	nodeID, err := ids.NodeIDFromString(nodeInfo.Id)
+	if err != nil {
+		return nil, ids.Empty, nil, err
+	}
+	txID, waitValidator, err := s.network.addPrimaryValidator(ctx, nodeID, spec)
+	return s.network, txID, waitValidator, err
+}
+
+// waitPrimaryValidator waits with [waitValidator], if not nil, without holding [s.mu],
+// then updates the subnet validators of network [lc], and returns the cluster info
+func (s *server) waitPrimaryValidator(
+	ctx context.Context,
+	lc *localNetwork,
+	waitValidator func(context.Context) error,
+) (*rpcpb.ClusterInfo, error) {
+	if waitValidator != nil {
+		if err := waitValidator(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// stopped or replaced while waiting
+	if s.network != lc || s.clusterInfo == nil {
+		return nil, errAborted
+	}
+	s.setSubnetValidators(ctx)
+	return s.clusterInfo, nil
+}
+
+func (s *server) AddDelegator(ctx context.Context, req *rpcpb.AddDelegatorRequest) (*rpcpb.AddDelegatorResponse, error) {
+	zap.L().Info("received add delegator request",
+		zap.String("node-name", req.NodeName),
+		zap.Uint64("stake-amount", req.StakeAmount),
+		zap.Int64("start-time", req.StartTime),
+		zap.Int64("end-time", req.EndTime),
+	)
+	if err := checkStakingPeriod(req.StartTime, req.EndTime); err != nil {
+		return nil, err
+	}
+	if info := s.getClusterInfo(); info == nil {
+		return nil, ErrNotBootstrapped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.network == nil || s.network.nw == nil || !s.clusterInfo.Healthy {
+		return nil, ErrNetworkNotReady
+	}
+	if s.installingCustomVMs {
+		return nil, ErrCustomVMsInstalling
+	}
+	nodeInfo, ok := s.network.nodeInfos[req.NodeName]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+	nodeID, err := ids.NodeIDFromString(nodeInfo.Id)
+	if err != nil {
+		return nil, err
+	}
+	txID, err := s.network.addDelegator(ctx, nodeID, req.StakeAmount, req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("node %q: %w", req.NodeName, err)
+	}
+	s.setSubnetValidators(ctx)
+	return &rpcpb.AddDelegatorResponse{ClusterInfo: s.clusterInfo, TxId: txID.String()}, nil
+}
+
+// checkPrimaryValidatorSpec checks that [spec], if set, has a valid duration,
+// and ends after it starts
+func checkPrimaryValidatorSpec(spec *rpcpb.PrimaryValidatorSpec) error {
+	if spec.GetDuration() < 0 {
+		return fmt.Errorf("%w: duration %ds", ErrInvalidValidatorPeriod, spec.GetDuration())
+	}
+	return checkStakingPeriod(spec.GetStartTime(), spec.GetEndTime())
+}
+
+// checkStakingPeriod checks that [endTime] is after [startTime], if both are set
+func checkStakingPeriod(startTime int64, endTime int64) error {
+	if startTime < 0 || endTime < 0 || (startTime != 0 && endTime != 0 && endTime <= startTime) {
+		return fmt.Errorf("%w: start %d, end %d", ErrInvalidValidatorPeriod, startTime, endTime)
+	}
+	return nil
+}
+
+// addPrimaryValidator issues a tx from the pre-funded test key adding node [nodeID]
+// as a primary network validator as specified by [spec].
+// Unless scheduled with a start time, returns the function waiting for it to be in
+// the current validator set, which does not need the network to be locked.
+func (lc *localNetwork) addPrimaryValidator(
+	ctx context.Context,
+	nodeID ids.NodeID,
+	spec *rpcpb.PrimaryValidatorSpec,
+) (ids.ID, func(context.Context) error, error) {
+	httpRPCEp := lc.nodeInfos[lc.nodeNames[0]].Uri
+	platformCli := platformvm.NewClient(httpRPCEp)
+
+	isValidator, err := isPrimaryValidator(ctx, platformCli, nodeID)
+	if err != nil {
+		return ids.Empty, nil, err
+	}
+	if isValidator {
+		return ids.Empty, nil, fmt.Errorf("%w: %s", ErrAlreadyValidator, nodeID)
+	}
+	baseWallet, _, testKeyAddr, err := lc.setupWallet(ctx, httpRPCEp)
+	if err != nil {
+		return ids.Empty, nil, err
+	}
+
+	stake := spec.GetStakeAmount()
+	if stake == 0 {
+		stake = defaultValidatorStake
+	}
+	delegationFee := spec.GetDelegationFee()
+	if delegationFee == 0 {
+		delegationFee = defaultValidatorDelegationFee
+	}
+	start, end := stakingPeriod(spec.GetStartTime(), spec.GetEndTime(), time.Duration(spec.GetDuration())*time.Second)
+	if !end.After(start) {
+		return ids.Empty, nil, fmt.Errorf("%w: ends at %s, before its start at %s", ErrInvalidValidatorPeriod, end, start)
+	}
+
+	println()
+	color.Outf("{{green}}adding node %s as a primary network validator from %s to %s{{/}}\n", nodeID, start, end)
+	txID, err := lc.addValidator(ctx, baseWallet, nodeID, stake, start, end, delegationFee, testKeyAddr)
+	if err != nil {
+		return ids.Empty, nil, err
+	}
+	zap.L().Info("issued add validator tx",
+		zap.String("node-id", nodeID.String()),
+		zap.Uint64("stake", stake),
+		zap.Time("start", start),
+		zap.Time("end", end),
+		zap.Uint32("delegation-fee", delegationFee),
+		zap.String("tx-id", txID.String()),
+	)
+	if spec.GetStartTime() != 0 {
+		// scheduled, pending until its start
+		return txID, nil, nil
+	}
+	return txID, func(ctx context.Context) error {
+		if err := waitForPrimaryValidator(ctx, platformCli, nodeID); err != nil {
+			return err
+		}
+		color.Outf("{{green}}node %s is a primary network validator{{/}}\n", nodeID)
+		return nil
+	}, nil
+}
+
+// stakingPeriod returns the staking period starting at [startTime], or after
+// [validatorStartDelay] if zero, and ending at [endTime], or after [duration]
+// if zero, or after [defaultValidatorDuration] if both are zero
+func stakingPeriod(startTime int64, endTime int64, duration time.Duration) (time.Time, time.Time) {
+	start := time.Now().Add(validatorStartDelay)
+	if startTime != 0 {
+		start = time.Unix(startTime, 0)
+	}
+	if endTime != 0 {
+		return start, time.Unix(endTime, 0)
+	}
+	if duration == 0 {
+		duration = defaultValidatorDuration
+	}
+	return start, start.Add(duration)
+}
+
+// addValidator issues a tx adding node [nodeID] as a primary network validator,
+// rewarding [rewardAddr]
+func (lc *localNetwork) addValidator(
+	ctx context.Context,
+	baseWallet *refreshableWallet,
+	nodeID ids.NodeID,
+	stake uint64,
+	start time.Time,
+	end time.Time,
+	delegationFee uint32,
+	rewardAddr ids.ShortID,
+) (ids.ID, error) {
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	return baseWallet.P().IssueAddValidatorTx(
+		&validator.Validator{
+			NodeID: nodeID,
+			Start:  uint64(start.Unix()),
+			End:    uint64(end.Unix()),
+			Wght:   stake,
+		},
+		&secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{rewardAddr},
+		},
+		delegationFee,
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+}
+
+// isPrimaryValidator returns true if [nodeID] is in the current primary network validator set
+func isPrimaryValidator(ctx context.Context, platformCli platformvm.Client, nodeID ids.NodeID) (bool, error) {
+	cctx, cancel := createDefaultCtx(ctx)
+	vs, err := platformCli.GetCurrentValidators(cctx, constants.PrimaryNetworkID, []ids.NodeID{nodeID})
+	cancel()
+	if err != nil {
+		return false, err
+	}
+	for _, v := range vs {
+		if v.NodeID == nodeID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// waitForPrimaryValidator waits for [nodeID] to be in the current primary network
+// validator set, for at most [validatorWaitTimeout]
+func waitForPrimaryValidator(ctx context.Context, platformCli platformvm.Client, nodeID ids.NodeID) error {
+	color.Outf("{{blue}}waiting for node %s to be in the current validator set...{{/}}\n", nodeID)
+	wctx, cancel := context.WithTimeout(ctx, validatorWaitTimeout)
+	defer cancel()
+	for {
+		isValidator, err := isPrimaryValidator(wctx, platformCli, nodeID)
+		if err == nil && isValidator {
+			return nil
+		}
+		select {
+		case <-wctx.Done():
+			return fmt.Errorf("node %s not in the current validator set: %w (last error: %v)", nodeID, wctx.Err(), err)
+		case <-time.After(validatorWaitPollInterval):
+		}
+	}
+}
+
+// addDelegator issues a tx from the pre-funded test key delegating [stake] to node [nodeID]
+// from [startTime], or after [validatorStartDelay] if zero, to [endTime],
+// or to the end of the validation if zero
+func (lc *localNetwork) addDelegator(ctx context.Context, nodeID ids.NodeID, stake uint64, startTime int64, endTime int64) (ids.ID, error) {
+	httpRPCEp := lc.nodeInfos[lc.nodeNames[0]].Uri
+	cctx, cancel := createDefaultCtx(ctx)
+	v, err := getPrimaryValidator(cctx, httpRPCEp, nodeID)
+	cancel()
+	if err != nil {
+		return ids.Empty, err
+	}
+	if endTime == 0 {
+		endTime = int64(v.EndTime)
+	}
+	start, end := stakingPeriod(startTime, endTime, 0)
+	if !end.After(start) {
+		return ids.Empty, fmt.Errorf("%w: ends at %s, before its start at %s", ErrInvalidValidatorPeriod, end, start)
+	}
+	if stake == 0 {
+		stake = defaultDelegatorStake
+	}
+	baseWallet, _, testKeyAddr, err := lc.setupWallet(ctx, httpRPCEp)
+	if err != nil {
+		return ids.Empty, err
+	}
+
+	println()
+	color.Outf("{{green}}delegating %d nAXC to node %s from %s to %s{{/}}\n", stake, nodeID, start, end)
+	cctx, cancel = createDefaultCtx(ctx)
+	txID, err := baseWallet.P().IssueAddDelegatorTx(
+		&validator.Validator{
+			NodeID: nodeID,
+			Start:  uint64(start.Unix()),
+			End:    uint64(end.Unix()),
+			Wght:   stake,
+		},
+		&secp256k1fx.OutputOwners{
+			Threshold: 1,
+			Addrs:     []ids.ShortID{testKeyAddr},
+		},
+		common.WithContext(cctx),
+		defaultPoll,
+	)
+	cancel()
+	if err != nil {
+		return ids.Empty, err
+	}
+	zap.L().Info("issued add delegator tx",
+		zap.String("node-id", nodeID.String()),
+		zap.Uint64("stake", stake),
+		zap.Time("start", start),
+		zap.Time("end", end),
+		zap.String("tx-id", txID.String()),
+	)
+	return txID, nil
+}
+
+// apiStaker is a validator or delegator as reported by the platform API
+type apiStaker struct {
+	TxID        string          `json:"txID"`
+	NodeID      string          `json:"nodeID"`
+	StartTime   apijson.Uint64  `json:"startTime"`
+	EndTime     apijson.Uint64  `json:"endTime"`
+	StakeAmount *apijson.Uint64 `json:"stakeAmount"`
+	Weight      *apijson.Uint64 `json:"weight"`
+	Delegators  []apiStaker     `json:"delegators"`
+}
+
+// weight returns the stake of a primary network staker, or the weight of a subnet validator
+func (st apiStaker) weight() uint64 {
+	switch {
+	case st.StakeAmount != nil:
+		return uint64(*st.StakeAmount)
+	case st.Weight != nil:
+		return uint64(*st.Weight)
+	default:
+		return 0
+	}
+}
+
+// getPrimaryValidator returns the current or pending primary network validation of [nodeID]
+func getPrimaryValidator(ctx context.Context, uri string, nodeID ids.NodeID) (apiStaker, error) {
+	params := map[string]interface{}{
+		"subnetID": constants.PrimaryNetworkID.String(),
+		"nodeIDs":  []string{nodeID.String()},
+	}
+	for _, method := range []string{"platform.getCurrentValidators", "platform.getPendingValidators"} {
+		var reply struct {
+			Validators []apiStaker `json:"validators"`
+		}
+		if err := callJSONRPC(ctx, uri+platformAPIPath, method, params, &reply); err != nil {
+			return apiStaker{}, err
+		}
+		for _, v := range reply.Validators {
+			if v.NodeID == nodeID.String() {
+				return v, nil
+			}
+		}
+	}
+	return apiStaker{}, fmt.Errorf("%w: %s", ErrNotValidator, nodeID)
+}
+
+// fetchSubnetValidators returns the current and pending validators of all subnets
+// by subnet ID, as reported by the node at [uri], naming the nodes of [nodeIDToName]
+func fetchSubnetValidators(ctx context.Context, uri string, nodeIDToName map[string]string) (map[string]*rpcpb.SubnetValidators, error) {
+	var subnetsReply struct {
+		Subnets []struct {
+			ID string `json:"id"`
+		} `json:"subnets"`
+	}
+	if err := callJSONRPC(ctx, uri+platformAPIPath, "platform.getSubnets", map[string]interface{}{}, &subnetsReply); err != nil {
+		return nil, err
+	}
+	subnetIDs := []string{constants.PrimaryNetworkID.String()}
+	for _, subnet := range subnetsReply.Subnets {
+		if subnet.ID != subnetIDs[0] {
+			subnetIDs = append(subnetIDs, subnet.ID)
+		}
+	}
+
+	subnetValidators := make(map[string]*rpcpb.SubnetValidators, len(subnetIDs))
+	for _, subnetID := range subnetIDs {
+		params := map[string]interface{}{"subnetID": subnetID}
+		var currentReply struct {
+			Validators []apiStaker `json:"validators"`
+		}
+		if err := callJSONRPC(ctx, uri+platformAPIPath, "platform.getCurrentValidators", params, &currentReply); err != nil {
+			return nil, fmt.Errorf("current validators of subnet %s: %w", subnetID, err)
+		}
+		var pendingReply struct {
+			Validators []apiStaker `json:"validators"`
+			Delegators []apiStaker `json:"delegators"`
+		}
+		if err := callJSONRPC(ctx, uri+platformAPIPath, "platform.getPendingValidators", params, &pendingReply); err != nil {
+			return nil, fmt.Errorf("pending validators of subnet %s: %w", subnetID, err)
+		}
+		subnetValidators[subnetID] = &rpcpb.SubnetValidators{
+			Current:           validatorInfos(currentReply.Validators, nodeIDToName),
+			Pending:           validatorInfos(pendingReply.Validators, nodeIDToName),
+			PendingDelegators: delegatorInfos(pendingReply.Delegators),
+		}
+	}
+	return subnetValidators, nil
+}
+
+// validatorInfos returns [validators] sorted by start time and node ID
+func validatorInfos(validators []apiStaker, nodeIDToName map[string]string) []*rpcpb.ValidatorInfo {
+	infos := make([]*rpcpb.ValidatorInfo, 0, len(validators))
+	for _, v := range validators {
+		infos = append(infos, &rpcpb.ValidatorInfo{
+			NodeId:     v.NodeID,
+			NodeName:   nodeIDToName[v.NodeID],
+			TxId:       v.TxID,
+			StartTime:  int64(v.StartTime),
+			EndTime:    int64(v.EndTime),
+			Weight:     v.weight(),
+			Delegators: delegatorInfos(v.Delegators),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].StartTime != infos[j].StartTime {
+			return infos[i].StartTime < infos[j].StartTime
+		}
+		return infos[i].NodeId < infos[j].NodeId
+	})
+	return infos
+}
+
+// delegatorInfos returns [delegators] sorted by start time and tx ID
+func delegatorInfos(delegators []apiStaker) []*rpcpb.DelegatorInfo {
+	infos := make([]*rpcpb.DelegatorInfo, 0, len(delegators))
+	for _, d := range delegators {
+		infos = append(infos, &rpcpb.DelegatorInfo{
+			NodeId:      d.NodeID,
+			TxId:        d.TxID,
+			StartTime:   int64(d.StartTime),
+			EndTime:     int64(d.EndTime),
+			StakeAmount: d.weight(),
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].StartTime != infos[j].StartTime {
+			return infos[i].StartTime < infos[j].StartTime
+		}
+		return infos[i].TxId < infos[j].TxId
+	})
+	return infos
+}
+
+// subnetValidatorsEndpoint returns the URI of the node to fetch the subnet validators from,
+// and the node names by node ID
+func (lc *localNetwork) subnetValidatorsEndpoint() (string, map[string]string) {
+	nodeIDToName := make(map[string]string, len(lc.nodeInfos))
+	for nodeName, nodeInfo := range lc.nodeInfos {
+		nodeIDToName[nodeInfo.Id] = nodeName
+	}
+	return lc.nodeInfos[lc.nodeNames[0]].Uri, nodeIDToName
+}
+
+// setSubnetValidators updates the subnet validators of the cluster info.
+// Assumes [s.mu] is held.
+func (s *server) setSubnetValidators(ctx context.Context) {
+	uri, nodeIDToName := s.network.subnetValidatorsEndpoint()
+	cctx, cancel := context.WithTimeout(ctx, subnetValidatorsTimeout)
+	subnetValidators, err := fetchSubnetValidators(cctx, uri, nodeIDToName)
+	cancel()
+	if err != nil {
+		zap.L().Warn("failed to fetch subnet validators", zap.Error(err))
+		return
+	}
+	s.clusterInfo.SubnetValidators = subnetValidators
+}
+
+// subnetValidatorsLoop refreshes the subnet validators of the cluster info
+// every [subnetValidatorsRefreshInterval], until the server is closed.
+// The RPCs changing the validators refresh them on their own.
+func (s *server) subnetValidatorsLoop(ctx context.Context) {
+	tc := time.NewTicker(subnetValidatorsRefreshInterval)
+	defer tc.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.closed:
+			return
+		case <-tc.C:
+		}
+		s.updateSubnetValidators(ctx)
+	}
+}
+
+// updateSubnetValidators updates the subnet validators of the cluster info,
+// if the network is healthy
+func (s *server) updateSubnetValidators(ctx context.Context) {
+	s.mu.RLock()
+	if s.network == nil || s.network.nw == nil || s.clusterInfo == nil || !s.clusterInfo.Healthy {
+		s.mu.RUnlock()
+		return
+	}
+	lc := s.network
+	uri, nodeIDToName := lc.subnetValidatorsEndpoint()
+	s.mu.RUnlock()
+
+	// fetch without holding the lock, as for the resource usage
+	cctx, cancel := context.WithTimeout(ctx, subnetValidatorsTimeout)
+	subnetValidators, err := fetchSubnetValidators(cctx, uri, nodeIDToName)
+	cancel()
+	if err != nil {
+		zap.L().Debug("failed to fetch subnet validators", zap.Error(err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// stopped or replaced while fetching
+	if s.network != lc || s.clusterInfo == nil {
+		return
+	}
+	// a fresh map, as the previous one may be read without holding the lock
+	s.clusterInfo.SubnetValidators = subnetValidators
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/axiacoin/axia-network-runner/rpcpb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPrimaryValidatorSpec(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(checkPrimaryValidatorSpec(nil))
+	assert.NoError(checkPrimaryValidatorSpec(&rpcpb.PrimaryValidatorSpec{}))
+	assert.NoError(checkPrimaryValidatorSpec(&rpcpb.PrimaryValidatorSpec{StakeAmount: 1, Duration: 3600, DelegationFee: 20000}))
+	err := checkPrimaryValidatorSpec(&rpcpb.PrimaryValidatorSpec{Duration: -1})
+	assert.True(errors.Is(err, ErrInvalidValidatorPeriod))
+	assert.NoError(checkPrimaryValidatorSpec(&rpcpb.PrimaryValidatorSpec{StartTime: 100, EndTime: 200}))
+	err = checkPrimaryValidatorSpec(&rpcpb.PrimaryValidatorSpec{StartTime: 200, EndTime: 100})
+	assert.True(errors.Is(err, ErrInvalidValidatorPeriod))
+}
+
+func TestCheckStakingPeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(checkStakingPeriod(0, 0))
+	assert.NoError(checkStakingPeriod(100, 0))
+	assert.NoError(checkStakingPeriod(0, 100))
+	assert.NoError(checkStakingPeriod(100, 200))
+	assert.True(errors.Is(checkStakingPeriod(100, 100), ErrInvalidValidatorPeriod))
+	assert.True(errors.Is(checkStakingPeriod(-1, 0), ErrInvalidValidatorPeriod))
+	assert.True(errors.Is(checkStakingPeriod(0, -1), ErrInvalidValidatorPeriod))
+}
+
+func TestStakingPeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	start, end := stakingPeriod(100, 200, time.Hour)
+	assert.Equal(time.Unix(100, 0), start)
+	assert.Equal(time.Unix(200, 0), end)
+
+	start, end = stakingPeriod(100, 0, time.Hour)
+	assert.Equal(time.Unix(100, 0), start)
+	assert.Equal(time.Unix(100, 0).Add(time.Hour), end)
+
+	start, end = stakingPeriod(100, 0, 0)
+	assert.Equal(time.Unix(100, 0).Add(defaultValidatorDuration), end)
+
+	before := time.Now()
+	start, end = stakingPeriod(0, 0, time.Hour)
+	assert.False(start.Before(before.Add(validatorStartDelay)))
+	assert.Equal(start.Add(time.Hour), end)
+}
+
+func TestFetchSubnetValidators(t *testing.T) {
+	assert := assert.New(t)
+
+	const (
+		primaryNetworkID = "11111111111111111111111111111111LpoYY"
+		subnetID         = "2bRCr6B4MiEfSjidDwxDpdCyviwnfUVqB2HGwhm947w9YYqb7r"
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			Params struct {
+				SubnetID string `json:"subnetID"`
+			} `json:"params"`
+		}
+		if r.URL.Path != platformAPIPath || json.NewDecoder(r.Body).Decode(&req) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var result string
+		switch {
+		case req.Method == "platform.getSubnets":
+			result = `{"subnets":[{"id":"` + primaryNetworkID + `"},{"id":"` + subnetID + `"}]}`
+		case req.Method == "platform.getCurrentValidators" && req.Params.SubnetID == primaryNetworkID:
+			result = `{"validators":[
+				{"txID":"tx2","nodeID":"NodeID-2","startTime":"20","endTime":"200","stakeAmount":"2000"},
+				{"txID":"tx1","nodeID":"NodeID-1","startTime":"10","endTime":"100","stakeAmount":"1000",
+					"delegators":[{"txID":"tx3","nodeID":"NodeID-1","startTime":"30","endTime":"90","stakeAmount":"500"}]}
+			]}`
+		case req.Method == "platform.getCurrentValidators":
+			result = `{"validators":[{"txID":"tx4","nodeID":"NodeID-1","startTime":"40","endTime":"80","weight":"10"}]}`
+		case req.Method == "platform.getPendingValidators" && req.Params.SubnetID == primaryNetworkID:
+			result = `{"validators":[{"txID":"tx5","nodeID":"NodeID-3","startTime":"50","endTime":"500","stakeAmount":"3000"}],
+				"delegators":[{"txID":"tx6","nodeID":"NodeID-2","startTime":"60","endTime":"150","stakeAmount":"600"}]}`
+		case req.Method == "platform.getPendingValidators":
+			result = `{"validators":[],"delegators":[]}`
+		default:
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+	defer srv.Close()
+
+	subnetValidators, err := fetchSubnetValidators(context.Background(), srv.URL, map[string]string{
+		"NodeID-1": "node1",
+		"NodeID-2": "node2",
+	})
+	assert.NoError(err)
+	assert.Len(subnetValidators, 2)
+
+	primary := subnetValidators[primaryNetworkID]
+	assert.Equal([]*rpcpb.ValidatorInfo{
+		{
+			NodeId: "NodeID-1", NodeName: "node1", TxId: "tx1", StartTime: 10, EndTime: 100, Weight: 1000,
+			Delegators: []*rpcpb.DelegatorInfo{{NodeId: "NodeID-1", TxId: "tx3", StartTime: 30, EndTime: 90, StakeAmount: 500}},
+		},
+		{NodeId: "NodeID-2", NodeName: "node2", TxId: "tx2", StartTime: 20, EndTime: 200, Weight: 2000, Delegators: []*rpcpb.DelegatorInfo{}},
+	}, primary.Current)
+	assert.Equal([]*rpcpb.ValidatorInfo{
+		{NodeId: "NodeID-3", TxId: "tx5", StartTime: 50, EndTime: 500, Weight: 3000, Delegators: []*rpcpb.DelegatorInfo{}},
+	}, primary.Pending)
+	assert.Equal([]*rpcpb.DelegatorInfo{
+		{NodeId: "NodeID-2", TxId: "tx6", StartTime: 60, EndTime: 150, StakeAmount: 600},
+	}, primary.PendingDelegators)
+
+	subnet := subnetValidators[subnetID]
+	assert.Len(subnet.Current, 1)
+	assert.Equal(uint64(10), subnet.Current[0].Weight)
+	assert.Empty(subnet.Pending)
+	assert.Empty(subnet.PendingDelegators)
+}
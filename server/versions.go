@@ -0,0 +1,90 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package server
+
+import (
+	"fmt"
+
+	"github.com/axiacoin/axia-network-runner/local"
+	"github.com/axiacoin/axia-network-runner/utils"
+	"go.uber.org/zap"
+)
+
+// detectBinaryVersions runs each of [execPaths] with --version,
+// and returns their versions by exec path.
+// Detection is best-effort: a binary whose version cannot be detected gets an empty version,
+// so that its database version is not checked.
+func detectBinaryVersions(execPaths ...string) map[string]utils.BinaryVersion {
+	versions := make(map[string]utils.BinaryVersion)
+	for _, execPath := range execPaths {
+		if _, ok := versions[execPath]; ok {
+			continue
+		}
+		version, err := utils.GetBinaryVersion(execPath)
+		if err != nil {
+			zap.L().Warn("failed to detect binary version", zap.String("exec-path", execPath), zap.Error(err))
+		}
+		if version.Database == "" {
+			zap.L().Warn("unknown binary database version, not checking the databases", zap.String("exec-path", execPath))
+		}
+		zap.L().Info("detected binary version",
+			zap.String("exec-path", execPath),
+			zap.String("app-version", version.App),
+			zap.String("db-version", version.Database),
+		)
+		versions[execPath] = version
+	}
+	return versions
+}
+
+// checkSnapshotBinaries detects the versions of the binaries the nodes of snapshot [snapshotName]
+// are loaded with ([execPath] if not empty), and unless [force], checks that they can open
+// the saved databases
+func checkSnapshotBinaries(
+	snapshotsDir string,
+	snapshotName string,
+	execPath string,
+	force bool,
+) (map[string]utils.BinaryVersion, error) {
+	networkConfig, err := local.GetSnapshotConfig(snapshotsDir, snapshotName)
+	if err != nil {
+		return nil, err
+	}
+	versions := make(map[string]utils.BinaryVersion)
+	for _, nodeConfig := range networkConfig.NodeConfigs {
+		binaryPath := nodeConfig.BinaryPath
+		if execPath != "" {
+			binaryPath = execPath
+		}
+		version, ok := versions[binaryPath]
+		if !ok {
+			version = detectBinaryVersions(binaryPath)[binaryPath]
+			versions[binaryPath] = version
+		}
+		if force {
+			continue
+		}
+		dbDir := local.GetSnapshotDbDir(snapshotsDir, snapshotName, nodeConfig.Name)
+		if err := utils.CheckDBVersion(dbDir, version.Database); err != nil {
+			return nil, fmt.Errorf("node %q: %w (force to load anyway)", nodeConfig.Name, err)
+		}
+	}
+	return versions, nil
+}
+
+// setBinaryVersions records the versions of binaries, to be reported
+// in the info of the nodes running them
+func (lc *localNetwork) setBinaryVersions(versions map[string]utils.BinaryVersion) {
+	lc.binaryVersionsMu.Lock()
+	defer lc.binaryVersionsMu.Unlock()
+	for execPath, version := range versions {
+		lc.binaryVersions[execPath] = version
+	}
+}
+
+func (lc *localNetwork) getBinaryVersion(execPath string) utils.BinaryVersion {
+	lc.binaryVersionsMu.RLock()
+	defer lc.binaryVersionsMu.RUnlock()
+	return lc.binaryVersions[execPath]
+}
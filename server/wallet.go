@@ -7,6 +7,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/axiacoin/axia/codec"
 	"github.com/axiacoin/axia/ids"
 	"github.com/axiacoin/axia/utils/constants"
 	"github.com/axiacoin/axia/vms/avm"
@@ -38,6 +39,9 @@ type refreshableWallet struct {
 	xBuilder x.Builder
 	xSigner  x.Signer
 
+	// shared by the SwapChain and CoreChain backends
+	utxos primary.UTXOs
+
 	httpRPCEp string
 }
 
@@ -84,6 +88,8 @@ func createRefreshableWallet(ctx context.Context, httpRPCEp string, kc *secp256k
 		xBuilder: xBuilder,
 		xSigner:  xSigner,
 
+		utxos: utxos,
+
 		httpRPCEp: httpRPCEp,
 	}, nil
 }
@@ -103,3 +109,23 @@ func (w *refreshableWallet) refresh(httpRPCEp string) {
 	w.Wallet = primary.NewWallet(pw, xw)
 	w.httpRPCEp = httpRPCEp
 }
+
+// fetchAtomicUTXOs adds the UTXOs exported from [sourceChainID] to the SwapChain or CoreChain
+// [destinationChainID] to the wallet, e.g. the ones exported by the AXChain,
+// which are not fetched on creation
+func (w *refreshableWallet) fetchAtomicUTXOs(ctx context.Context, sourceChainID ids.ID, destinationChainID ids.ID) error {
+	var (
+		client primary.UTXOClient
+		c      codec.Manager
+	)
+	if destinationChainID == constants.PlatformChainID {
+		client = platformvm.NewClient(w.httpRPCEp)
+		c = platformvm.Codec
+	} else {
+		client = avm.NewClient(w.httpRPCEp, "X")
+		c = x.Parser.Codec()
+	}
+	cctx, cancel := createDefaultCtx(ctx)
+	defer cancel()
+	return primary.AddAllUTXOs(cctx, w.utxos, client, c, sourceChainID, destinationChainID, w.kc.Addrs.List())
+}
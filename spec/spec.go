@@ -0,0 +1,147 @@
+// Copyright (C) 2019-2022, Axia Systems, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package spec defines the declarative network spec
+// that the server converges a network to.
+package spec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RestartPolicy defines when a running node is restarted to apply its spec
+type RestartPolicy string
+
+const (
+	// Restart the node if its binary, config or beacon flag changed.
+	RestartOnChange RestartPolicy = "on-change"
+	// Restart the node on every apply.
+	RestartAlways RestartPolicy = "always"
+	// Never restart the node, changes are only reported.
+	RestartNever RestartPolicy = "never"
+)
+
+var (
+	ErrNoNodes                  = errors.New("spec has no nodes")
+	ErrNoBeacon                 = errors.New("spec has no beacon node")
+	ErrEmptyNodeName            = errors.New("empty node name")
+	ErrDuplicateNodeName        = errors.New("duplicate node name")
+	ErrNoExecPath               = errors.New("no exec path for node")
+	ErrInvalidRestartPolicy     = errors.New("invalid restart policy")
+	ErrUnknownAttachedPeersNode = errors.New("attached peers for unknown node")
+	ErrPluginDirWithoutVMs      = errors.New("non-empty plugin dir but empty custom VMs")
+	ErrVMsWithoutPluginDir      = errors.New("empty plugin dir but non-empty custom VMs")
+)
+
+// Spec is the desired state of a network.
+// It can be given as YAML or JSON.
+type Spec struct {
+	// Default axia binary for the nodes.
+	ExecPath string `yaml:"execPath"`
+	// Config applied to all nodes.
+	// Overridden by each node's config.
+	GlobalNodeConfig map[string]interface{} `yaml:"globalNodeConfig"`
+	// Comma-separated subnet IDs to whitelist on all nodes,
+	// in addition to the subnets of the custom VMs.
+	WhitelistedSubnets string `yaml:"whitelistedSubnets"`
+	// Plugin directory to load all custom VM executables from.
+	PluginDir string `yaml:"pluginDir"`
+	// Map from custom VM name to its genesis file path.
+	// A subnet and a blockchain are created for each of them.
+	// Only installed when the network is started.
+	CustomVMs map[string]string `yaml:"customVMs"`
+	Nodes     []Node            `yaml:"nodes"`
+	// Map from node name to the number of test peers to attach to it.
+	AttachedPeers map[string]uint32 `yaml:"attachedPeers"`
+}
+
+// Node is the desired state of a node
+type Node struct {
+	// Must be unique across all nodes of the spec.
+	Name string `yaml:"name"`
+	// If empty, Spec.ExecPath is used.
+	ExecPath string `yaml:"execPath"`
+	// Config of this node. Overrides Spec.GlobalNodeConfig.
+	Config map[string]interface{} `yaml:"config"`
+	// True if other nodes should use this node as a bootstrap beacon.
+	IsBeacon bool `yaml:"isBeacon"`
+	// Defaults to RestartOnChange.
+	RestartPolicy RestartPolicy `yaml:"restartPolicy"`
+}
+
+// Parse parses a YAML or JSON spec, and validates it.
+// Unknown fields are rejected, to catch typos.
+func Parse(b []byte) (*Spec, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	spec := &Spec{}
+	if err := dec.Decode(spec); err != nil {
+		return nil, fmt.Errorf("couldn't parse spec: %w", err)
+	}
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Validate returns an error if this spec is invalid
+func (s *Spec) Validate() error {
+	if len(s.Nodes) == 0 {
+		return ErrNoNodes
+	}
+	if s.PluginDir != "" && len(s.CustomVMs) == 0 {
+		return ErrPluginDirWithoutVMs
+	}
+	if s.PluginDir == "" && len(s.CustomVMs) > 0 {
+		return ErrVMsWithoutPluginDir
+	}
+	names := make(map[string]struct{}, len(s.Nodes))
+	someNodeIsBeacon := false
+	for _, node := range s.Nodes {
+		if node.Name == "" {
+			return ErrEmptyNodeName
+		}
+		if _, ok := names[node.Name]; ok {
+			return fmt.Errorf("%w: %q", ErrDuplicateNodeName, node.Name)
+		}
+		names[node.Name] = struct{}{}
+		if node.GetExecPath(s) == "" {
+			return fmt.Errorf("%w: %q", ErrNoExecPath, node.Name)
+		}
+		switch node.RestartPolicy {
+		case "", RestartOnChange, RestartAlways, RestartNever:
+		default:
+			return fmt.Errorf("%w %q for node %q", ErrInvalidRestartPolicy, node.RestartPolicy, node.Name)
+		}
+		someNodeIsBeacon = someNodeIsBeacon || node.IsBeacon
+	}
+	if !someNodeIsBeacon {
+		return ErrNoBeacon
+	}
+	for nodeName := range s.AttachedPeers {
+		if _, ok := names[nodeName]; !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownAttachedPeersNode, nodeName)
+		}
+	}
+	return nil
+}
+
+// GetExecPath returns the binary of this node in [spec]
+func (n *Node) GetExecPath(spec *Spec) string {
+	if n.ExecPath != "" {
+		return n.ExecPath
+	}
+	return spec.ExecPath
+}
+
+// GetRestartPolicy returns the restart policy of this node, with its default applied
+func (n *Node) GetRestartPolicy() RestartPolicy {
+	if n.RestartPolicy == "" {
+		return RestartOnChange
+	}
+	return n.RestartPolicy
+}
@@ -0,0 +1,150 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	yamlSpec := `
+execPath: /tmp/axia
+globalNodeConfig:
+  log-level: DEBUG
+nodes:
+  - name: node1
+    isBeacon: true
+  - name: node2
+    execPath: /tmp/other-axia
+    restartPolicy: never
+    config:
+      http-port: 9652
+attachedPeers:
+  node1: 2
+`
+	spec, err := Parse([]byte(yamlSpec))
+	assert.NoError(err)
+	assert.Equal("/tmp/axia", spec.ExecPath)
+	assert.Equal(map[string]interface{}{"log-level": "DEBUG"}, spec.GlobalNodeConfig)
+	assert.Len(spec.Nodes, 2)
+	assert.Equal("/tmp/axia", spec.Nodes[0].GetExecPath(spec))
+	assert.Equal(RestartOnChange, spec.Nodes[0].GetRestartPolicy())
+	assert.True(spec.Nodes[0].IsBeacon)
+	assert.Equal("/tmp/other-axia", spec.Nodes[1].GetExecPath(spec))
+	assert.Equal(RestartNever, spec.Nodes[1].GetRestartPolicy())
+	assert.EqualValues(9652, spec.Nodes[1].Config["http-port"])
+	assert.Equal(map[string]uint32{"node1": 2}, spec.AttachedPeers)
+
+	// JSON is also accepted
+	jsonSpec := `{"execPath":"/tmp/axia","nodes":[{"name":"node1","isBeacon":true}]}`
+	spec, err = Parse([]byte(jsonSpec))
+	assert.NoError(err)
+	assert.Len(spec.Nodes, 1)
+
+	// unknown fields are rejected
+	_, err = Parse([]byte("execPath: /tmp/axia\nnode: []\n"))
+	assert.Error(err)
+}
+
+func TestValidate(t *testing.T) {
+	type test struct {
+		name        string
+		spec        Spec
+		expectedErr error
+	}
+	tests := []test{
+		{
+			name:        "no nodes",
+			spec:        Spec{ExecPath: "/tmp/axia"},
+			expectedErr: ErrNoNodes,
+		},
+		{
+			name: "no beacon",
+			spec: Spec{
+				ExecPath: "/tmp/axia",
+				Nodes:    []Node{{Name: "node1"}},
+			},
+			expectedErr: ErrNoBeacon,
+		},
+		{
+			name: "empty node name",
+			spec: Spec{
+				ExecPath: "/tmp/axia",
+				Nodes:    []Node{{IsBeacon: true}},
+			},
+			expectedErr: ErrEmptyNodeName,
+		},
+		{
+			name: "duplicate node name",
+			spec: Spec{
+				ExecPath: "/tmp/axia",
+				Nodes:    []Node{{Name: "node1", IsBeacon: true}, {Name: "node1"}},
+			},
+			expectedErr: ErrDuplicateNodeName,
+		},
+		{
+			name: "no exec path",
+			spec: Spec{
+				Nodes: []Node{{Name: "node1", IsBeacon: true}},
+			},
+			expectedErr: ErrNoExecPath,
+		},
+		{
+			name: "invalid restart policy",
+			spec: Spec{
+				ExecPath: "/tmp/axia",
+				Nodes:    []Node{{Name: "node1", IsBeacon: true, RestartPolicy: "sometimes"}},
+			},
+			expectedErr: ErrInvalidRestartPolicy,
+		},
+		{
+			name: "attached peers for unknown node",
+			spec: Spec{
+				ExecPath:      "/tmp/axia",
+				Nodes:         []Node{{Name: "node1", IsBeacon: true}},
+				AttachedPeers: map[string]uint32{"node2": 1},
+			},
+			expectedErr: ErrUnknownAttachedPeersNode,
+		},
+		{
+			name: "plugin dir without custom VMs",
+			spec: Spec{
+				ExecPath:  "/tmp/axia",
+				PluginDir: "/tmp/plugins",
+				Nodes:     []Node{{Name: "node1", IsBeacon: true}},
+			},
+			expectedErr: ErrPluginDirWithoutVMs,
+		},
+		{
+			name: "custom VMs without plugin dir",
+			spec: Spec{
+				ExecPath:  "/tmp/axia",
+				CustomVMs: map[string]string{"subnetevm": "/tmp/genesis.json"},
+				Nodes:     []Node{{Name: "node1", IsBeacon: true}},
+			},
+			expectedErr: ErrVMsWithoutPluginDir,
+		},
+		{
+			name: "valid",
+			spec: Spec{
+				ExecPath:  "/tmp/axia",
+				PluginDir: "/tmp/plugins",
+				CustomVMs: map[string]string{"subnetevm": "/tmp/genesis.json"},
+				Nodes: []Node{
+					{Name: "node1", IsBeacon: true},
+					{Name: "node2", RestartPolicy: RestartAlways},
+				},
+				AttachedPeers: map[string]uint32{"node2": 1},
+			},
+			expectedErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.Validate()
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
@@ -7,6 +7,7 @@ package e2e_test
 import (
 	"context"
 	"flag"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -42,6 +43,7 @@ var (
 	gRPCGatewayEp string
 	execPath1     string
 	execPath2     string
+	artifactsDir  string
 
 	newNodeName       = "test-add-node"
 	customNodeConfigs = map[string]string{
@@ -87,6 +89,12 @@ func init() {
 		"",
 		"axia executable path (to upgrade to)",
 	)
+	flag.StringVar(
+		&artifactsDir,
+		"artifacts-dir",
+		"",
+		"[optional] directory to write the network artifacts to when a spec fails",
+	)
 }
 
 var cli client.Client
@@ -101,6 +109,33 @@ var _ = ginkgo.BeforeSuite(func() {
 	gomega.Ω(err).Should(gomega.BeNil())
 })
 
+var _ = ginkgo.JustAfterEach(func() {
+	report := ginkgo.CurrentSpecReport()
+	if artifactsDir == "" || !report.Failed() {
+		return
+	}
+	if err := os.MkdirAll(artifactsDir, 0o755); err != nil {
+		color.Outf("{{red}}failed to create artifacts dir:{{/}} %v\n", err)
+		return
+	}
+	artifactsPath := filepath.Join(artifactsDir, fmt.Sprintf("artifacts-%d.tar.gz", time.Now().Unix()))
+	f, err := os.Create(artifactsPath)
+	if err != nil {
+		color.Outf("{{red}}failed to create artifacts file:{{/}} %v\n", err)
+		return
+	}
+	defer f.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	err = cli.CollectArtifacts(ctx, f)
+	cancel()
+	if err != nil {
+		// the network may not be running, e.g. on failed start
+		color.Outf("{{red}}failed to collect artifacts:{{/}} %v\n", err)
+		return
+	}
+	color.Outf("{{yellow}}spec %q failed, artifacts written to{{/}} %s\n", report.FullText(), artifactsPath)
+})
+
 var _ = ginkgo.AfterSuite(func() {
 	color.Outf("{{red}}shutting down cluster{{/}}\n")
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
@@ -269,6 +304,27 @@ var _ = ginkgo.Describe("[Start/Remove/Restart/Add/Stop]", func() {
 		})
 	})
 
+	time.Sleep(10 * time.Second)
+	ginkgo.It("can upgrade", func() {
+		ginkgo.By("calling upgrade API with the second binary", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+			resp, err := cli.UpgradeNetwork(
+				ctx,
+				execPath2,
+				client.WithNodeNames([]string{"node1", "node2", "node3"}),
+				client.WithBatchSize(2),
+			)
+			cancel()
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(resp.Error).Should(gomega.BeEmpty())
+			gomega.Ω(resp.UpgradedNodes).Should(gomega.HaveLen(3))
+			for _, nodeName := range []string{"node1", "node2", "node3"} {
+				gomega.Ω(resp.ClusterInfo.NodeInfos[nodeName].ExecPath).Should(gomega.Equal(execPath2))
+			}
+			color.Outf("{{green}}successfully upgraded:{{/}} %+v\n", resp.UpgradedNodes)
+		})
+	})
+
 	time.Sleep(10 * time.Second)
 	ginkgo.It("can attach a peer", func() {
 		ginkgo.By("calling attach peer API", func() {
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// clock ticks per second used by /proc/<pid>/stat times.
+// This is USER_HZ, which is 100 on all the platforms we run on.
+const clockTicksPerSecond = 100
+
+// ResourceUsage is a snapshot of the resources held by a process
+type ResourceUsage struct {
+	// user + system CPU time
+	CPUSeconds float64
+	// resident set size
+	RSSBytes uint64
+	OpenFDs  uint32
+	Threads  uint32
+}
+
+// GetResourceUsage samples the resource usage of process [pid] from /proc.
+// Only available on Linux.
+func GetResourceUsage(pid int) (ResourceUsage, error) {
+	procDir := filepath.Join("/proc", strconv.Itoa(pid))
+	usage := ResourceUsage{}
+
+	// the command name may contain spaces and parentheses,
+	// so only split what follows its closing parenthesis
+	stat, err := os.ReadFile(filepath.Join(procDir, "stat"))
+	if err != nil {
+		return usage, err
+	}
+	commEnd := strings.LastIndexByte(string(stat), ')')
+	if commEnd == -1 {
+		return usage, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	// fields after the command name start at field 3 (state)
+	fields := strings.Fields(string(stat[commEnd+1:]))
+	if len(fields) < 13 {
+		return usage, fmt.Errorf("malformed stat for pid %d", pid)
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return usage, fmt.Errorf("couldn't parse utime for pid %d: %w", pid, err)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return usage, fmt.Errorf("couldn't parse stime for pid %d: %w", pid, err)
+	}
+	usage.CPUSeconds = float64(utime+stime) / clockTicksPerSecond
+
+	status, err := os.Open(filepath.Join(procDir, "status"))
+	if err != nil {
+		return usage, err
+	}
+	defer status.Close()
+	scanner := bufio.NewScanner(status)
+	for scanner.Scan() {
+		keyValue := strings.SplitN(scanner.Text(), ":", 2)
+		if len(keyValue) != 2 {
+			continue
+		}
+		valueFields := strings.Fields(keyValue[1])
+		if len(valueFields) == 0 {
+			continue
+		}
+		switch keyValue[0] {
+		case "VmRSS":
+			// reported in kB
+			rss, err := strconv.ParseUint(valueFields[0], 10, 64)
+			if err != nil {
+				return usage, fmt.Errorf("couldn't parse VmRSS for pid %d: %w", pid, err)
+			}
+			usage.RSSBytes = rss * 1024
+		case "Threads":
+			threads, err := strconv.ParseUint(valueFields[0], 10, 32)
+			if err != nil {
+				return usage, fmt.Errorf("couldn't parse Threads for pid %d: %w", pid, err)
+			}
+			usage.Threads = uint32(threads)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return usage, err
+	}
+
+	fds, err := os.ReadDir(filepath.Join(procDir, "fd"))
+	if err != nil {
+		return usage, err
+	}
+	usage.OpenFDs = uint32(len(fds))
+	return usage, nil
+}
+
+// DirSize returns the total size of the regular files under [dir]
+func DirSize(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// files may be removed while walking, e.g. compacted db files
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		size += uint64(info.Size())
+		return nil
+	})
+	return size, err
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetResourceUsage(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("/proc is only available on linux")
+	}
+	assert := assert.New(t)
+	usage, err := GetResourceUsage(os.Getpid())
+	assert.NoError(err)
+	assert.NotZero(usage.RSSBytes)
+	assert.NotZero(usage.Threads)
+	assert.NotZero(usage.OpenFDs)
+
+	_, err = GetResourceUsage(-1)
+	assert.Error(err)
+}
+
+func TestDirSize(t *testing.T) {
+	assert := assert.New(t)
+	dir := t.TempDir()
+	assert.NoError(os.WriteFile(filepath.Join(dir, "a"), make([]byte, 10), 0o600))
+	assert.NoError(os.MkdirAll(filepath.Join(dir, "sub"), 0o700))
+	assert.NoError(os.WriteFile(filepath.Join(dir, "sub", "b"), make([]byte, 32), 0o600))
+	size, err := DirSize(dir)
+	assert.NoError(err)
+	assert.EqualValues(42, size)
+}
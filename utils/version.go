@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeout to run a binary with --version
+const versionTimeout = 10 * time.Second
+
+var (
+	ErrInvalidVersionOutput  = errors.New("unexpected --version output")
+	ErrInvalidVersion        = errors.New("invalid version")
+	ErrIncompatibleDBVersion = errors.New("incompatible database version")
+)
+
+// matches the database version in the --version output,
+// e.g., "axia/1.7.14 [database=v1.4.5, rpcchainvm=15, commit=...]"
+var dbVersionRegexp = regexp.MustCompile(`database=(v[0-9]+\.[0-9]+\.[0-9]+)`)
+
+// BinaryVersion is the version reported by a node binary
+type BinaryVersion struct {
+	// e.g., "axia/1.7.14"
+	App string
+	// e.g., "v1.4.5", empty if not reported
+	Database string
+}
+
+// GetBinaryVersion runs [execPath] with --version and parses its output
+func GetBinaryVersion(execPath string) (BinaryVersion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, execPath, "--version").Output()
+	if err != nil {
+		return BinaryVersion{}, fmt.Errorf("failed to run %q --version: %w", execPath, err)
+	}
+	version, err := ParseBinaryVersion(string(out))
+	if err != nil {
+		return BinaryVersion{}, fmt.Errorf("%q: %w", execPath, err)
+	}
+	return version, nil
+}
+
+// ParseBinaryVersion parses the output of a node binary run with --version.
+// Older binaries do not report their database version, which is then left empty.
+func ParseBinaryVersion(output string) (BinaryVersion, error) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return BinaryVersion{}, fmt.Errorf("%w: empty", ErrInvalidVersionOutput)
+	}
+	version := BinaryVersion{App: fields[0]}
+	if matches := dbVersionRegexp.FindStringSubmatch(output); len(matches) == 2 {
+		version.Database = matches[1]
+	}
+	return version, nil
+}
+
+// CheckDBVersion returns an error if [dbDir] holds a database newer than [dbVersion],
+// which a binary with that database version refuses to open.
+// Nothing is checked if [dbVersion] is empty, i.e., unknown.
+// Databases are stored under "<db-dir>/<network name>/<database version>".
+func CheckDBVersion(dbDir string, dbVersion string) error {
+	if dbVersion == "" {
+		return nil
+	}
+	if _, err := parseVersion(dbVersion); err != nil {
+		return err
+	}
+	matches, err := filepath.Glob(filepath.Join(dbDir, "*", "v*"))
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			continue
+		}
+		existingVersion := filepath.Base(match)
+		cmp, err := compareVersions(existingVersion, dbVersion)
+		if err != nil {
+			// not a database dir
+			continue
+		}
+		if cmp > 0 {
+			return fmt.Errorf("%w: %q holds a %s database, newer than the binary's %s", ErrIncompatibleDBVersion, dbDir, existingVersion, dbVersion)
+		}
+	}
+	return nil
+}
+
+// compareVersions returns -1, 0 or 1 if version [a] is older, the same as or newer than [b]
+func compareVersions(a string, b string) (int, error) {
+	va, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := range va {
+		switch {
+		case va[i] < vb[i]:
+			return -1, nil
+		case va[i] > vb[i]:
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// parseVersion parses a "vX.Y.Z" version
+func parseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if !strings.HasPrefix(version, "v") || len(parts) != 3 {
+		return parsed, fmt.Errorf("%w %q", ErrInvalidVersion, version)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return parsed, fmt.Errorf("%w %q", ErrInvalidVersion, version)
+		}
+		parsed[i] = n
+	}
+	return parsed, nil
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBinaryVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	version, err := ParseBinaryVersion("axia/1.7.14 [database=v1.4.5, rpcchainvm=15, commit=abc]\n")
+	assert.NoError(err)
+	assert.Equal(BinaryVersion{App: "axia/1.7.14", Database: "v1.4.5"}, version)
+
+	_, err = ParseBinaryVersion("")
+	assert.True(errors.Is(err, ErrInvalidVersionOutput))
+	// no database version reported
+	version, err = ParseBinaryVersion("axia/1.7.14")
+	assert.NoError(err)
+	assert.Equal(BinaryVersion{App: "axia/1.7.14"}, version)
+}
+
+func TestGetBinaryVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	execPath := filepath.Join(t.TempDir(), "axia")
+	script := "#!/bin/sh\necho 'axia/1.7.14 [database=v1.4.5, commit=abc]'\n"
+	assert.NoError(os.WriteFile(execPath, []byte(script), 0o755))
+	version, err := GetBinaryVersion(execPath)
+	assert.NoError(err)
+	assert.Equal(BinaryVersion{App: "axia/1.7.14", Database: "v1.4.5"}, version)
+
+	_, err = GetBinaryVersion(execPath + "-missing")
+	assert.Error(err)
+}
+
+func TestCheckDBVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	dbDir := t.TempDir()
+	// no database yet
+	assert.NoError(CheckDBVersion(dbDir, "v1.4.5"))
+
+	assert.NoError(os.MkdirAll(filepath.Join(dbDir, "network-1337", "v1.4.5"), 0o755))
+	assert.NoError(CheckDBVersion(dbDir, "v1.4.5"))
+	assert.NoError(CheckDBVersion(dbDir, "v1.10.0"))
+	assert.True(errors.Is(CheckDBVersion(dbDir, "v1.4.4"), ErrIncompatibleDBVersion))
+	assert.True(errors.Is(CheckDBVersion(dbDir, "1.4.5"), ErrInvalidVersion))
+	// unknown database version
+	assert.NoError(CheckDBVersion(dbDir, ""))
+}